@@ -0,0 +1,304 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package amd implements answering machine detection for outbound calls.
+package amd
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/livekit/media-sdk"
+)
+
+// Result is a result of answering machine detection.
+type Result int
+
+const (
+	// ResultUnknown is reported when the detector cannot make a decision, for example on dead air.
+	ResultUnknown Result = iota
+	// ResultHuman is reported when a short greeting is followed by silence.
+	ResultHuman
+	// ResultMachine is reported for long greetings.
+	ResultMachine
+	// ResultBeep is reported at the end of the voicemail beep, when the machine starts recording.
+	ResultBeep
+)
+
+func (r Result) String() string {
+	switch r {
+	case ResultUnknown:
+		return "unknown"
+	case ResultHuman:
+		return "human"
+	case ResultMachine:
+		return "machine"
+	case ResultBeep:
+		return "beep"
+	}
+	return fmt.Sprintf("Result(%d)", int(r))
+}
+
+// Reasons for the decision.
+const (
+	ReasonInitialSilence = "initial_silence"
+	ReasonAfterGreeting  = "after_greeting_silence"
+	ReasonLongGreeting   = "long_greeting"
+	ReasonMaxWords       = "max_words"
+	ReasonTimeout        = "timeout"
+	ReasonBeep           = "beep"
+)
+
+// Decision is reported by the Detector.
+type Decision struct {
+	Result Result
+	Reason string
+	// Time is the time of the decision, relative to the start of the audio stream.
+	Time time.Duration
+	// Greeting is the duration of the voice in the greeting.
+	Greeting time.Duration
+	// Words is the number of words in the greeting.
+	Words int
+}
+
+// Default detection thresholds.
+const (
+	DefaultInitialSilence       = 2500 * time.Millisecond
+	DefaultGreeting             = 1500 * time.Millisecond
+	DefaultAfterGreetingSilence = 800 * time.Millisecond
+	DefaultTotalAnalysis        = 5 * time.Second
+	DefaultMinWordLength        = 100 * time.Millisecond
+	DefaultBetweenWordsSilence  = 50 * time.Millisecond
+	DefaultMaxWords             = 3
+	DefaultSilenceThreshold     = -40
+	DefaultBeepTimeout          = 30 * time.Second
+	DefaultBeepMinDur           = 120 * time.Millisecond
+)
+
+// Config sets detection thresholds. Zero values are replaced with defaults.
+type Config struct {
+	// InitialSilence is the max silence before the greeting. Longer silence is reported as ResultUnknown.
+	InitialSilence time.Duration
+	// Greeting is the max duration of the voice in the human greeting. Longer greetings are reported as ResultMachine.
+	Greeting time.Duration
+	// AfterGreetingSilence is the silence after the greeting which is reported as ResultHuman.
+	AfterGreetingSilence time.Duration
+	// TotalAnalysis is the max duration of the analysis. If no decision is made, ResultUnknown is reported.
+	TotalAnalysis time.Duration
+	// MinWordLength is the min duration of the voice to be counted as a word.
+	MinWordLength time.Duration
+	// BetweenWordsSilence is the min silence that separates words.
+	BetweenWordsSilence time.Duration
+	// MaxWords is the max number of words in the human greeting. More words are reported as ResultMachine.
+	MaxWords int
+	// SilenceThreshold is the level in dBFS below which the audio is considered silent.
+	SilenceThreshold float64
+	// BeepTimeout is how long the detector waits for the voicemail beep after the start of the stream.
+	// Negative value disables beep detection.
+	BeepTimeout time.Duration
+	// BeepMinDur is the min duration of the beep tone.
+	BeepMinDur time.Duration
+}
+
+func (c *Config) setDefaults() {
+	if c.InitialSilence <= 0 {
+		c.InitialSilence = DefaultInitialSilence
+	}
+	if c.Greeting <= 0 {
+		c.Greeting = DefaultGreeting
+	}
+	if c.AfterGreetingSilence <= 0 {
+		c.AfterGreetingSilence = DefaultAfterGreetingSilence
+	}
+	if c.TotalAnalysis <= 0 {
+		c.TotalAnalysis = DefaultTotalAnalysis
+	}
+	if c.MinWordLength <= 0 {
+		c.MinWordLength = DefaultMinWordLength
+	}
+	if c.BetweenWordsSilence <= 0 {
+		c.BetweenWordsSilence = DefaultBetweenWordsSilence
+	}
+	if c.MaxWords <= 0 {
+		c.MaxWords = DefaultMaxWords
+	}
+	if c.SilenceThreshold == 0 {
+		c.SilenceThreshold = DefaultSilenceThreshold
+	}
+	if c.BeepTimeout == 0 {
+		c.BeepTimeout = DefaultBeepTimeout
+	}
+	if c.BeepMinDur <= 0 {
+		c.BeepMinDur = DefaultBeepMinDur
+	}
+}
+
+const (
+	// blockDur is the duration of the block used for voice activity detection.
+	blockDur = 10 * time.Millisecond
+)
+
+// NewDetector creates a PCM writer that detects answering machines.
+//
+// The detector classifies the greeting by its length, the number of words and the silence timing,
+// using energy-based voice activity detection. Handler is called once with the decision.
+// If the decision is ResultMachine, the detector continues to listen for the beep and calls the handler again
+// with ResultBeep when the beep ends. The beep may also be reported without ResultMachine,
+// if it is heard before the decision is made.
+func NewDetector(sampleRate int, conf *Config, h func(d Decision)) *Detector {
+	if sampleRate <= 0 {
+		panic("invalid sample rate")
+	}
+	var c Config
+	if conf != nil {
+		c = *conf
+	}
+	c.setDefaults()
+	d := &Detector{
+		h:          h,
+		conf:       c,
+		sampleRate: sampleRate,
+		block:      int(time.Duration(sampleRate) * blockDur / time.Second),
+	}
+	fullScale := float64(math.MaxInt16) * float64(math.MaxInt16) / 2
+	d.silencePower = fullScale * math.Pow(10, c.SilenceThreshold/10)
+	if c.BeepTimeout > 0 {
+		d.beep = newBeepDetector(sampleRate, c.BeepMinDur, d.silencePower)
+	}
+	return d
+}
+
+// Detector detects answering machines. See NewDetector.
+type Detector struct {
+	h            func(d Decision)
+	conf         Config
+	sampleRate   int
+	block        int
+	silencePower float64
+
+	buf     media.PCM16Sample
+	pos     int // position of the current block in samples
+	decided bool
+	done    bool
+
+	voice    time.Duration // total voice in the greeting
+	voiceRun time.Duration // consecutive voice
+	silence  time.Duration // consecutive silence
+	greeting bool
+	inWord   bool
+	words    int
+	beep     *beepDetector
+}
+
+func (d *Detector) String() string {
+	return fmt.Sprintf("AMD(%d)", d.sampleRate)
+}
+
+func (d *Detector) SampleRate() int {
+	return d.sampleRate
+}
+
+func (d *Detector) Close() error {
+	return nil
+}
+
+func (d *Detector) WriteSample(in media.PCM16Sample) error {
+	if d.done {
+		return nil
+	}
+	if d.beep != nil {
+		if end, ok := d.beep.process(in); ok {
+			d.decide(ResultBeep, ReasonBeep, d.dur(end))
+			return nil
+		}
+	}
+	d.buf = append(d.buf, in...)
+	for len(d.buf) >= d.block && !d.done {
+		d.update(d.buf[:d.block])
+		d.buf = d.buf[:copy(d.buf, d.buf[d.block:])]
+		d.pos += d.block
+	}
+	return nil
+}
+
+func (d *Detector) dur(samples int) time.Duration {
+	return time.Duration(samples) * time.Second / time.Duration(d.sampleRate)
+}
+
+func (d *Detector) decide(r Result, reason string, at time.Duration) {
+	d.decided = true
+	if r != ResultMachine || d.beep == nil {
+		d.done = true
+	}
+	if d.h != nil {
+		d.h(Decision{Result: r, Reason: reason, Time: at, Greeting: d.voice, Words: d.words})
+	}
+}
+
+// update processes a single block of audio.
+func (d *Detector) update(b media.PCM16Sample) {
+	now := d.dur(d.pos + len(b))
+	if d.decided {
+		if now >= d.conf.BeepTimeout {
+			d.done = true
+		}
+		return
+	}
+	var power float64
+	for _, v := range b {
+		power += float64(v) * float64(v)
+	}
+	power /= float64(len(b))
+	if power < d.silencePower {
+		d.silence += blockDur
+		d.voiceRun = 0
+		if d.silence >= d.conf.BetweenWordsSilence {
+			d.inWord = false
+		}
+		if !d.greeting && d.silence >= d.conf.InitialSilence {
+			d.decide(ResultUnknown, ReasonInitialSilence, now)
+			return
+		}
+		if d.greeting && d.silence >= d.conf.AfterGreetingSilence {
+			d.decide(ResultHuman, ReasonAfterGreeting, now)
+			return
+		}
+	} else {
+		d.silence = 0
+		d.voiceRun += blockDur
+		if d.greeting {
+			d.voice += blockDur
+		}
+		if !d.inWord && d.voiceRun >= d.conf.MinWordLength {
+			if !d.greeting {
+				d.greeting = true
+				d.voice = d.voiceRun
+			}
+			d.inWord = true
+			d.words++
+			if d.words > d.conf.MaxWords {
+				d.decide(ResultMachine, ReasonMaxWords, now)
+				return
+			}
+		}
+		if d.greeting && d.voice >= d.conf.Greeting {
+			d.decide(ResultMachine, ReasonLongGreeting, now)
+			return
+		}
+	}
+	if now >= d.conf.TotalAnalysis {
+		d.decide(ResultUnknown, ReasonTimeout, now)
+	}
+}
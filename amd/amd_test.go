@@ -0,0 +1,244 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amd
+
+import (
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/res"
+	"github.com/livekit/media-sdk/res/testdata"
+)
+
+// segment of the synthesized audio: speech-like noise, a tone or silence.
+type segment struct {
+	dur   time.Duration
+	voice bool
+	tone  float64
+}
+
+func synth(rate int, segs ...segment) []media.PCM16Sample {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	var samples media.PCM16Sample
+	for _, s := range segs {
+		n := int(time.Duration(rate) * s.dur / time.Second)
+		for i := range n {
+			var v float64
+			switch {
+			case s.voice:
+				// noise modulated with a syllable rate
+				v = 4000 * rnd.NormFloat64() * (0.6 + 0.4*math.Sin(2*math.Pi*4*float64(i)/float64(rate)))
+			case s.tone != 0:
+				v = 8000 * math.Sin(2*math.Pi*s.tone*float64(i)/float64(rate))
+			default:
+				v = 20 * rnd.NormFloat64()
+			}
+			samples = append(samples, int16(v))
+		}
+	}
+	frame := rate / media.DefFramesPerSec
+	var frames []media.PCM16Sample
+	for len(samples) > 0 {
+		n := min(frame, len(samples))
+		frames = append(frames, samples[:n])
+		samples = samples[n:]
+	}
+	return frames
+}
+
+func detect(t testing.TB, rate int, conf *Config, frames []media.PCM16Sample) []Decision {
+	var got []Decision
+	d := NewDetector(rate, conf, func(v Decision) {
+		got = append(got, v)
+	})
+	for _, f := range frames {
+		require.NoError(t, d.WriteSample(f))
+	}
+	require.NoError(t, d.Close())
+	return got
+}
+
+func ms(v int) time.Duration {
+	return time.Duration(v) * time.Millisecond
+}
+
+func TestDetector(t *testing.T) {
+	cases := []struct {
+		name   string
+		segs   []segment
+		exp    []Result
+		reason string
+	}{
+		{
+			name:   "human",
+			segs:   []segment{{dur: ms(500)}, {dur: ms(600), voice: true}, {dur: 2 * time.Second}},
+			exp:    []Result{ResultHuman},
+			reason: ReasonAfterGreeting,
+		},
+		{
+			name:   "long greeting",
+			segs:   []segment{{dur: ms(300)}, {dur: 3 * time.Second, voice: true}},
+			exp:    []Result{ResultMachine},
+			reason: ReasonLongGreeting,
+		},
+		{
+			name: "max words",
+			segs: []segment{
+				{dur: ms(300), voice: true}, {dur: ms(200)},
+				{dur: ms(300), voice: true}, {dur: ms(200)},
+				{dur: ms(300), voice: true}, {dur: ms(200)},
+				{dur: ms(300), voice: true}, {dur: ms(200)},
+			},
+			exp:    []Result{ResultMachine},
+			reason: ReasonMaxWords,
+		},
+		{
+			name:   "dead air",
+			segs:   []segment{{dur: 3 * time.Second}},
+			exp:    []Result{ResultUnknown},
+			reason: ReasonInitialSilence,
+		},
+		{
+			name: "timeout",
+			segs: []segment{
+				{dur: ms(300), voice: true}, {dur: ms(700)},
+				{dur: ms(300), voice: true}, {dur: ms(700)},
+				{dur: ms(80), voice: true}, {dur: ms(700)},
+				{dur: ms(80), voice: true}, {dur: ms(700)},
+				{dur: ms(80), voice: true}, {dur: ms(700)},
+				{dur: ms(80), voice: true}, {dur: ms(700)},
+			},
+			exp:    []Result{ResultUnknown},
+			reason: ReasonTimeout,
+		},
+		{
+			name: "voicemail",
+			segs: []segment{
+				{dur: ms(200)}, {dur: 3 * time.Second, voice: true}, {dur: ms(300)},
+				{dur: ms(400), tone: 1000}, {dur: time.Second},
+			},
+			exp:    []Result{ResultMachine, ResultBeep},
+			reason: ReasonLongGreeting,
+		},
+		{
+			name: "beep only",
+			segs: []segment{
+				{dur: ms(500)}, {dur: ms(500), tone: 1400}, {dur: time.Second},
+			},
+			exp:    []Result{ResultBeep},
+			reason: ReasonBeep,
+		},
+	}
+	for _, rate := range []int{8000, 16000, 48000} {
+		for _, c := range cases {
+			t.Run(strconv.Itoa(rate)+"/"+c.name, func(t *testing.T) {
+				got := detect(t, rate, nil, synth(rate, c.segs...))
+				var res []Result
+				for _, v := range got {
+					res = append(res, v.Result)
+				}
+				require.Equal(t, c.exp, res)
+				require.Equal(t, c.reason, got[0].Reason)
+			})
+		}
+	}
+}
+
+func TestDetectorTiming(t *testing.T) {
+	const rate = 8000
+	got := detect(t, rate, nil, synth(rate,
+		segment{dur: ms(200)}, segment{dur: 3 * time.Second, voice: true}, segment{dur: ms(300)},
+		segment{dur: ms(400), tone: 1000}, segment{dur: time.Second},
+	))
+	require.Len(t, got, 2)
+	require.InDelta(t, ms(200)+DefaultGreeting, got[0].Time, float64(ms(20)))
+	require.Equal(t, DefaultGreeting, got[0].Greeting)
+	require.InDelta(t, ms(3900), got[1].Time, float64(ms(20)))
+}
+
+func TestDetectorConfig(t *testing.T) {
+	const rate = 8000
+	segs := []segment{{dur: ms(200)}, {dur: 2 * time.Second, voice: true}, {dur: time.Second}}
+
+	got := detect(t, rate, &Config{Greeting: 3 * time.Second}, synth(rate, segs...))
+	require.Len(t, got, 1)
+	require.Equal(t, ResultHuman, got[0].Result)
+
+	// beep detection disabled: no callbacks after the machine is detected
+	segs = []segment{{dur: 2 * time.Second, voice: true}, {dur: ms(400), tone: 1000}, {dur: time.Second}}
+	got = detect(t, rate, &Config{BeepTimeout: -1}, synth(rate, segs...))
+	require.Len(t, got, 1)
+	require.Equal(t, ResultMachine, got[0].Result)
+
+	// beep after the timeout is ignored
+	got = detect(t, rate, &Config{BeepTimeout: 2 * time.Second}, synth(rate, segs...))
+	require.Len(t, got, 1)
+}
+
+func TestDetectorMaxWords(t *testing.T) {
+	const rate = 8000
+	words := func(n int) []segment {
+		var segs []segment
+		for range n {
+			segs = append(segs, segment{dur: ms(200), voice: true}, segment{dur: ms(200)})
+		}
+		return append(segs, segment{dur: time.Second})
+	}
+	for _, maxWords := range []int{2, DefaultMaxWords} {
+		conf := &Config{MaxWords: maxWords}
+
+		// exactly MaxWords words is still a human greeting
+		got := detect(t, rate, conf, synth(rate, words(maxWords)...))
+		require.Len(t, got, 1)
+		require.Equal(t, ResultHuman, got[0].Result)
+		require.Equal(t, maxWords, got[0].Words)
+
+		got = detect(t, rate, conf, synth(rate, words(maxWords+1)...))
+		require.Len(t, got, 1)
+		require.Equal(t, ResultMachine, got[0].Result)
+		require.Equal(t, ReasonMaxWords, got[0].Reason)
+		require.Equal(t, maxWords+1, got[0].Words)
+	}
+}
+
+func TestDetectorSpeech(t *testing.T) {
+	frames := res.ReadOggAudioFile(testdata.TestAudioOgg, res.SampleRate, 1)
+
+	// a recorded message is a machine, and speech is not detected as a beep
+	got := detect(t, res.SampleRate, nil, frames)
+	require.Len(t, got, 1)
+	require.Equal(t, ResultMachine, got[0].Result)
+
+	b := newBeepDetector(res.SampleRate, DefaultBeepMinDur, 0)
+	for _, f := range frames {
+		_, ok := b.process(f)
+		require.False(t, ok)
+	}
+
+	// a short greeting followed by silence is a human
+	short := append([]media.PCM16Sample{}, frames[:30]...)
+	for range 50 {
+		short = append(short, make(media.PCM16Sample, len(frames[0])))
+	}
+	got = detect(t, res.SampleRate, nil, short)
+	require.Len(t, got, 1)
+	require.Equal(t, ResultHuman, got[0].Result)
+}
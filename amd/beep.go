@@ -0,0 +1,112 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amd
+
+import (
+	"math"
+	"time"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/internal/goertzel"
+)
+
+// Beep detection parameters.
+const (
+	// beepWindow is the analysis window of the beep detector.
+	beepWindow = 20 * time.Millisecond
+	// beepMinFreq and beepMaxFreq limit frequencies of the beep.
+	beepMinFreq = 300
+	beepMaxFreq = 3000
+	// beepToneRatio is the min ratio of the tone power to the total power of the signal.
+	beepToneRatio = 0.8
+	// beepFreqTolerance is the max relative frequency change between windows of the same beep.
+	beepFreqTolerance = 0.03
+)
+
+// beepDetector detects pure tones of any frequency, which are typically used by voicemail.
+//
+// Frequency of each window is estimated with the least squares fit of the sine recurrence x[n] = c*x[n-1] - x[n-2],
+// and the tone is confirmed by the Goertzel filter at the estimated frequency.
+type beepDetector struct {
+	sampleRate int
+	n          int
+	minLen     int
+	minPower   float64
+
+	buf media.PCM16Sample
+	pos int // position of the current window in samples
+
+	freq  float64 // frequency of the current tone, if any
+	start int     // start of the current tone in samples
+	end   int     // end of the current tone in samples
+}
+
+func newBeepDetector(sampleRate int, minDur time.Duration, minPower float64) *beepDetector {
+	return &beepDetector{
+		sampleRate: sampleRate,
+		n:          int(time.Duration(sampleRate) * beepWindow / time.Second),
+		minLen:     int(time.Duration(sampleRate) * minDur / time.Second),
+		minPower:   minPower,
+	}
+}
+
+// process the audio and return the end position of the beep, if it ended in this frame.
+func (b *beepDetector) process(in media.PCM16Sample) (int, bool) {
+	b.buf = append(b.buf, in...)
+	end, found := 0, false
+	for len(b.buf) >= b.n {
+		f := b.analyze(b.buf[:b.n])
+		switch {
+		case f != 0 && b.freq != 0 && math.Abs(f-b.freq) <= b.freq*beepFreqTolerance:
+			b.end = b.pos + b.n
+		default:
+			if b.freq != 0 && b.end-b.start >= b.minLen && !found {
+				end, found = b.end, true
+			}
+			b.freq = f
+			b.start, b.end = b.pos, b.pos+b.n
+		}
+		b.buf = b.buf[:copy(b.buf, b.buf[b.n:])]
+		b.pos += b.n
+	}
+	return end, found
+}
+
+// analyze returns the frequency of the window if it contains a pure tone, or zero otherwise.
+func (b *beepDetector) analyze(w media.PCM16Sample) float64 {
+	var power, num, den float64
+	for i, v := range w {
+		x := float64(v)
+		power += x * x
+		if i >= 2 {
+			x1 := float64(w[i-1])
+			num += (x + float64(w[i-2])) * x1
+			den += x1 * x1
+		}
+	}
+	power /= float64(len(w))
+	if power < b.minPower || den == 0 {
+		return 0
+	}
+	c := max(min(num/den, 2), -2)
+	freq := math.Acos(c/2) * float64(b.sampleRate) / (2 * math.Pi)
+	if freq < beepMinFreq || freq > beepMaxFreq {
+		return 0
+	}
+	if goertzel.Power(w, freq, b.sampleRate) < beepToneRatio*power {
+		return 0
+	}
+	return freq
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cn implements comfort noise payload (RFC 3389) and discontinuous transmission.
+package cn
+
+import (
+	"io"
+	"math"
+
+	"github.com/livekit/media-sdk"
+)
+
+const (
+	SDPName     = "CN/8000"
+	SampleRate  = 8000
+	PayloadType = 13
+
+	// MaxOrder is the max number of reflection coefficients in the SID frame.
+	MaxOrder = 12
+)
+
+func init() {
+	media.RegisterCodec(media.NewCodec(media.CodecInfo{
+		SDPName:     SDPName,
+		SampleRate:  SampleRate,
+		RTPDefType:  PayloadType,
+		RTPIsStatic: true,
+		Priority:    -100, // let it be last in SDP
+	}))
+}
+
+// SID is a silence insertion descriptor frame, which describes the background noise.
+type SID struct {
+	// Level of the noise in -dBov (0-127).
+	Level byte
+	// Reflection coefficients of the noise spectrum, in the range (-1, 1). Optional.
+	Reflection []float64
+}
+
+// Decode SID frame from the CN payload.
+func Decode(data []byte) (SID, error) {
+	if len(data) < 1 {
+		return SID{}, io.ErrUnexpectedEOF
+	}
+	s := SID{Level: data[0] & 0x7f}
+	data = data[1:]
+	if len(data) > MaxOrder {
+		data = data[:MaxOrder]
+	}
+	if len(data) != 0 {
+		s.Reflection = make([]float64, len(data))
+		for i, q := range data {
+			s.Reflection[i] = (float64(q) - 127) / 128
+		}
+	}
+	return s, nil
+}
+
+// Encode SID frame to the CN payload. It returns the number of bytes written.
+func Encode(out []byte, s SID) (int, error) {
+	refl := s.Reflection
+	if len(refl) > MaxOrder {
+		refl = refl[:MaxOrder]
+	}
+	n := 1 + len(refl)
+	if len(out) < n {
+		return 0, io.ErrShortBuffer
+	}
+	out[0] = min(s.Level, 127)
+	for i, k := range refl {
+		// Coefficients are quantized linearly, 127 is zero.
+		out[1+i] = byte(max(min(math.Round(k*128)+127, 254), 0))
+	}
+	return n, nil
+}
+
+// levelPower returns the mean square of PCM16 samples for a given level in -dBov.
+func levelPower(level byte) float64 {
+	const fullScale = float64(math.MaxInt16) * float64(math.MaxInt16)
+	return fullScale * math.Pow(10, -float64(level)/10)
+}
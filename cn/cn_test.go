@@ -0,0 +1,218 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cn
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/rtp"
+	"github.com/livekit/media-sdk/vad"
+)
+
+func TestSID(t *testing.T) {
+	var buf [1 + MaxOrder]byte
+	n, err := Encode(buf[:], SID{Level: 60, Reflection: []float64{0.5, -0.25, 0}})
+	require.NoError(t, err)
+	require.Equal(t, []byte{60, 191, 95, 127}, buf[:n])
+
+	s, err := Decode(buf[:n])
+	require.NoError(t, err)
+	require.Equal(t, SID{Level: 60, Reflection: []float64{0.5, -0.25, 0}}, s)
+
+	s, err = Decode([]byte{0xff})
+	require.NoError(t, err)
+	require.Equal(t, SID{Level: 127}, s)
+
+	_, err = Decode(nil)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	_, err = Encode(buf[:2], SID{Reflection: []float64{0.5, 0.5}})
+	require.ErrorIs(t, err, io.ErrShortBuffer)
+
+	// clamped
+	n, err = Encode(buf[:], SID{Level: 200, Reflection: []float64{-1, 1}})
+	require.NoError(t, err)
+	require.Equal(t, []byte{127, 0, 254}, buf[:n])
+}
+
+func generate(refl []float64, level byte, frames int) media.PCM16Sample {
+	g := NewGenerator()
+	g.SetSID(SID{Level: level, Reflection: refl})
+	buf := make(media.PCM16Sample, SampleRate/rtp.DefFramesPerSec)
+	g.Generate(buf) // skip the gain ramp
+	var out media.PCM16Sample
+	for range frames {
+		g.Generate(buf)
+		out = append(out, buf...)
+	}
+	return out
+}
+
+func TestGenerator(t *testing.T) {
+	g := NewGenerator()
+	buf := make(media.PCM16Sample, 160)
+	g.Generate(buf)
+	require.Equal(t, make(media.PCM16Sample, 160), buf)
+
+	for _, refl := range [][]float64{
+		nil,
+		{-0.9},
+		{-0.7, 0.4, -0.2},
+	} {
+		out := generate(refl, 40, 50)
+		require.InDelta(t, 40, int(vad.Level(out)), 1)
+
+		// estimated spectrum matches the original
+		d := &DTX{decimate: 1, corr: make([]float64, len(refl)+1)}
+		d.addCorr(out)
+		got := levinson(nil, d.corr)
+		require.Len(t, got, len(refl))
+		for i := range refl {
+			require.InDelta(t, refl[i], got[i], 0.05)
+		}
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	for _, rate := range []int{8000, 16000} {
+		var frames []media.PCM16Sample
+		d := NewDecoder(media.NewPCM16FrameWriter(&frames, rate))
+		require.Equal(t, rate, d.SampleRate())
+		require.False(t, d.Active())
+
+		require.NoError(t, d.HandleRTP(&rtp.Header{PayloadType: PayloadType}, []byte{50, 30}))
+		require.True(t, d.Active())
+		time.Sleep(150 * time.Millisecond)
+		require.NoError(t, d.HandleRTP(&rtp.Header{PayloadType: PayloadType}, []byte{30}))
+		time.Sleep(100 * time.Millisecond)
+
+		// regular audio stops the noise
+		audio := make(media.PCM16Sample, rate/rtp.DefFramesPerSec)
+		require.NoError(t, d.WriteSample(audio))
+		require.False(t, d.Active())
+		time.Sleep(50 * time.Millisecond)
+		require.NoError(t, d.Close())
+
+		require.GreaterOrEqual(t, len(frames), 8)
+		require.Equal(t, audio, frames[len(frames)-1])
+		noise := frames[:len(frames)-1]
+		for _, f := range noise {
+			require.Len(t, f, rate/rtp.DefFramesPerSec)
+		}
+		require.InDelta(t, 50, int(vad.Level(noise[3])), 2)
+		require.InDelta(t, 30, int(vad.Level(noise[len(noise)-1])), 2)
+
+		// closed decoder ignores SID
+		require.NoError(t, d.HandleRTP(&rtp.Header{PayloadType: PayloadType}, []byte{50}))
+		require.False(t, d.Active())
+	}
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	var audio []byte
+	mux := rtp.NewMux(rtp.HandlerFunc(func(h *rtp.Header, payload []byte) error {
+		audio = append(audio, h.PayloadType)
+		return nil
+	}))
+	var frames []media.PCM16Sample
+	d := RegisterDecoder(mux, PayloadType, media.NewPCM16FrameWriter(&frames, 16000))
+	defer d.Close()
+
+	require.NoError(t, mux.HandleRTP(&rtp.Header{PayloadType: PayloadType}, []byte{50}))
+	require.True(t, d.Active())
+	require.Empty(t, audio)
+
+	require.NoError(t, mux.HandleRTP(&rtp.Header{PayloadType: 0}, []byte{0xff}))
+	require.Equal(t, []byte{0}, audio)
+}
+
+type encoder struct {
+	s *rtp.Stream
+}
+
+func (e *encoder) String() string                      { return "encoder" }
+func (e *encoder) SampleRate() int                     { return SampleRate }
+func (e *encoder) Close() error                        { return nil }
+func (e *encoder) WriteSample(media.PCM16Sample) error { return e.s.WritePayload([]byte{0}, false) }
+
+func TestDTX(t *testing.T) {
+	var buf rtp.Buffer
+	sw := rtp.NewSeqWriter(&buf)
+	audio := sw.NewStream(0, SampleRate)
+	d := NewDTX(&encoder{audio}, audio, sw.NewStream(PayloadType, SampleRate), nil)
+
+	quiet := generate([]float64{-0.8}, 60, 1)
+	loud := generate([]float64{-0.8}, 40, 1)
+	write := func(n int, v media.VoiceActivity, frame media.PCM16Sample) {
+		for range n {
+			d.SetVoiceActivity(v)
+			require.NoError(t, d.WriteSample(frame))
+		}
+	}
+	write(10, media.VoiceActivity{Voice: true}, quiet)
+	write(60, media.VoiceActivity{Level: 60}, quiet)
+	require.True(t, d.Silent())
+	write(2, media.VoiceActivity{Level: 40}, loud)
+	write(10, media.VoiceActivity{Voice: true}, quiet)
+	require.False(t, d.Silent())
+	require.NoError(t, d.Close())
+
+	type pkt struct {
+		typ    byte
+		ts     uint32
+		marker bool
+		level  byte
+	}
+	var got []pkt
+	for _, p := range buf {
+		v := pkt{typ: p.PayloadType, ts: p.Timestamp, marker: p.Marker}
+		if p.PayloadType == PayloadType {
+			s, err := Decode(p.Payload)
+			require.NoError(t, err)
+			require.Len(t, s.Reflection, DefaultOrder)
+			require.InDelta(t, -0.8, s.Reflection[0], 0.25)
+			v.level = s.Level
+		}
+		got = append(got, v)
+	}
+	var exp []pkt
+	for i := range 10 {
+		exp = append(exp, pkt{ts: uint32(i * 160)})
+	}
+	exp = append(exp,
+		pkt{typ: PayloadType, ts: 10 * 160, level: 60}, // start of silence
+		pkt{typ: PayloadType, ts: 60 * 160, level: 60}, // refresh
+		pkt{typ: PayloadType, ts: 70 * 160, level: 40}, // level change
+	)
+	for i := range 10 {
+		exp = append(exp, pkt{ts: uint32((72 + i) * 160), marker: i == 0})
+	}
+	require.Equal(t, exp, got)
+}
+
+func TestDTXNoMetadata(t *testing.T) {
+	var buf rtp.Buffer
+	sw := rtp.NewSeqWriter(&buf)
+	audio := sw.NewStream(0, SampleRate)
+	d := NewDTX(&encoder{audio}, audio, sw.NewStream(PayloadType, SampleRate), nil)
+	for range 10 {
+		require.NoError(t, d.WriteSample(make(media.PCM16Sample, 160)))
+	}
+	require.Len(t, buf, 10)
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cn
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/rtp"
+)
+
+// Default DTX parameters.
+const (
+	DefaultOrder          = 8
+	DefaultLevelThreshold = 2
+	DefaultMinInterval    = 100 * time.Millisecond
+	DefaultMaxInterval    = time.Second
+)
+
+// DTXConfig sets parameters of discontinuous transmission. Zero values are replaced with defaults.
+type DTXConfig struct {
+	// Order is the number of reflection coefficients sent in SID frames (up to MaxOrder).
+	Order int
+	// LevelThreshold is the noise level change in dB that triggers a SID update.
+	LevelThreshold int
+	// MinInterval is the min interval between SID updates.
+	MinInterval time.Duration
+	// MaxInterval is the max interval between SID updates, even if the noise doesn't change.
+	MaxInterval time.Duration
+}
+
+func (c *DTXConfig) setDefaults() {
+	if c.Order <= 0 {
+		c.Order = DefaultOrder
+	}
+	c.Order = min(c.Order, MaxOrder)
+	if c.LevelThreshold <= 0 {
+		c.LevelThreshold = DefaultLevelThreshold
+	}
+	if c.MinInterval <= 0 {
+		c.MinInterval = DefaultMinInterval
+	}
+	if c.MaxInterval <= 0 {
+		c.MaxInterval = DefaultMaxInterval
+	}
+}
+
+// NewDTX creates a PCM writer for discontinuous transmission.
+//
+// Frames with voice are passed to the audio encoder enc, which must write each frame as one packet to the audio stream.
+// During silence, frames are not encoded and SID frames describing the noise are sent to the cn stream instead.
+// The first SID is sent at the start of the silence, and updates are sent when the noise level changes.
+//
+// DTX relies on voice activity metadata, for example, from vad.NewWriter. Without it, all frames are encoded.
+func NewDTX(enc media.WriteCloser[media.PCM16Sample], audio, cn *rtp.Stream, conf *DTXConfig) *DTX {
+	var c DTXConfig
+	if conf != nil {
+		c = *conf
+	}
+	c.setDefaults()
+	rate := enc.SampleRate()
+	if rate <= 0 {
+		panic("invalid sample rate")
+	}
+	return &DTX{
+		enc:        enc,
+		audio:      audio,
+		cn:         cn,
+		conf:       c,
+		sampleRate: rate,
+		decimate:   max(rate/SampleRate, 1),
+		corr:       make([]float64, c.Order+1),
+		cur:        media.VoiceActivity{Voice: true},
+	}
+}
+
+// DTX implements discontinuous transmission. See NewDTX.
+type DTX struct {
+	enc        media.WriteCloser[media.PCM16Sample]
+	audio      *rtp.Stream
+	cn         *rtp.Stream
+	conf       DTXConfig
+	sampleRate int
+	decimate   int
+
+	cur     media.VoiceActivity
+	silent  bool
+	since   time.Duration // time since the last SID
+	level   byte          // level of the last SID
+	corr    []float64     // autocorrelation of the noise since the last SID
+	refl    []float64
+	buf     []float64
+	payload [1 + MaxOrder]byte
+}
+
+func (d *DTX) String() string {
+	return fmt.Sprintf("DTX(%d) -> %s", d.sampleRate, d.enc)
+}
+
+func (d *DTX) SampleRate() int {
+	return d.sampleRate
+}
+
+func (d *DTX) Close() error {
+	return d.enc.Close()
+}
+
+// Silent returns true if DTX is currently sending comfort noise instead of audio.
+func (d *DTX) Silent() bool {
+	return d.silent
+}
+
+// SetVoiceActivity implements media.VoiceActivityWriter.
+func (d *DTX) SetVoiceActivity(v media.VoiceActivity) {
+	d.cur = v
+}
+
+func (d *DTX) WriteSample(in media.PCM16Sample) error {
+	if d.cur.Voice {
+		d.silent = false
+		return d.enc.WriteSample(in)
+	}
+	d.addCorr(in)
+	dur := time.Duration(len(in)) * time.Second / time.Duration(d.sampleRate)
+	send := false
+	switch {
+	case !d.silent:
+		send = true
+	case d.since >= d.conf.MaxInterval:
+		send = true
+	case d.since >= d.conf.MinInterval:
+		diff := int(d.cur.Level) - int(d.level)
+		send = diff >= d.conf.LevelThreshold || -diff >= d.conf.LevelThreshold
+	}
+	d.silent = true
+	if send {
+		if err := d.sendSID(); err != nil {
+			return err
+		}
+	}
+	d.since += dur
+	d.audio.Skip()
+	return nil
+}
+
+func (d *DTX) sendSID() error {
+	d.refl = levinson(d.refl[:0], d.corr)
+	s := SID{Level: d.cur.Level, Reflection: d.refl}
+	n, err := Encode(d.payload[:], s)
+	if err != nil {
+		return err
+	}
+	d.level = s.Level
+	d.since = 0
+	clear(d.corr)
+	d.cn.ResetTimestamp(d.audio.GetCurrentTimestamp())
+	return d.cn.WritePayloadAtCurrent(d.payload[:n], false)
+}
+
+// addCorr accumulates the autocorrelation of the frame decimated to 8 kHz.
+func (d *DTX) addCorr(in media.PCM16Sample) {
+	d.buf = d.buf[:0]
+	for i := 0; i+d.decimate <= len(in); i += d.decimate {
+		var s float64
+		for _, x := range in[i : i+d.decimate] {
+			s += float64(x)
+		}
+		d.buf = append(d.buf, s/float64(d.decimate))
+	}
+	for lag := range d.corr {
+		var s float64
+		for i := lag; i < len(d.buf); i++ {
+			s += d.buf[i] * d.buf[i-lag]
+		}
+		d.corr[lag] += s
+	}
+}
+
+// levinson computes reflection coefficients from the autocorrelation with Levinson-Durbin recursion.
+// The sign convention matches reflectionToLPC.
+func levinson(refl []float64, r []float64) []float64 {
+	if len(r) < 2 || r[0] <= 0 {
+		return refl
+	}
+	// Slight white noise correction keeps the recursion well-conditioned.
+	e := r[0] * (1 + 1e-9)
+	a := make([]float64, 0, len(r)-1)
+	tmp := make([]float64, len(r)-1)
+	for m := 1; m < len(r); m++ {
+		acc := r[m]
+		for i, ai := range a {
+			acc += ai * r[m-1-i]
+		}
+		k := max(min(-acc/e, maxReflection), -maxReflection)
+		copy(tmp, a)
+		for i := range a {
+			a[i] = tmp[i] + k*tmp[m-2-i]
+		}
+		a = append(a, k)
+		refl = append(refl, k)
+		e *= 1 - k*k
+	}
+	return refl
+}
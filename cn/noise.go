@@ -0,0 +1,215 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cn
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/rtp"
+)
+
+// maxReflection limits reflection coefficients to keep the synthesis filter stable.
+const maxReflection = 0.995
+
+// NewGenerator creates a comfort noise generator. Noise is silent until the first SID is set.
+func NewGenerator() *Generator {
+	return &Generator{
+		rnd: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+// Generator synthesizes comfort noise from SID frames: white noise is shaped with an all-pole filter
+// defined by reflection coefficients and scaled to the noise level.
+type Generator struct {
+	rnd  *rand.Rand
+	lpc  []float64 // direct form coefficients of the synthesis filter
+	mem  []float64 // filter memory, last output samples
+	gain float64   // current excitation gain
+	next float64   // target excitation gain
+}
+
+// SetSID updates noise parameters. The level change is smoothed over the next generated frame.
+func (g *Generator) SetSID(s SID) {
+	g.lpc = reflectionToLPC(g.lpc[:0], s.Reflection)
+	if len(g.mem) != len(g.lpc) {
+		g.mem = make([]float64, len(g.lpc))
+	}
+	// Prediction error power of the AR process is R0 * prod(1 - k^2).
+	power := levelPower(s.Level)
+	for _, k := range s.Reflection {
+		k = max(min(k, maxReflection), -maxReflection)
+		power *= 1 - k*k
+	}
+	g.next = math.Sqrt(power)
+}
+
+// Generate fills the buffer with noise.
+func (g *Generator) Generate(out media.PCM16Sample) {
+	if len(out) == 0 {
+		return
+	}
+	step := (g.next - g.gain) / float64(len(out))
+	for i := range out {
+		g.gain += step
+		y := g.gain * g.rnd.NormFloat64()
+		for j, a := range g.lpc {
+			y -= a * g.mem[j]
+		}
+		if len(g.mem) != 0 {
+			copy(g.mem[1:], g.mem)
+			g.mem[0] = y
+		}
+		out[i] = int16(max(min(y, math.MaxInt16), math.MinInt16))
+	}
+	g.gain = g.next
+}
+
+// reflectionToLPC converts reflection coefficients to direct form LPC coefficients a[1..p] (step-up recursion).
+// The synthesis filter is 1/A(z), where A(z) = 1 + sum(a[i] * z^-i).
+func reflectionToLPC(a []float64, refl []float64) []float64 {
+	tmp := make([]float64, len(refl))
+	for m, k := range refl {
+		k = max(min(k, maxReflection), -maxReflection)
+		copy(tmp, a)
+		for i := 0; i < m; i++ {
+			a[i] = tmp[i] + k*tmp[m-1-i]
+		}
+		a = append(a, k)
+	}
+	return a
+}
+
+// NewDecoder creates a comfort noise decoder. It is both an RTP handler for CN payloads,
+// and a PCM writer for the regular audio decoded from the same RTP stream.
+// Closing the decoder stops the noise and closes w.
+//
+// After each SID frame, the decoder generates noise to w in real time, in 20 ms frames.
+// The noise stops when the regular audio is written to the decoder, or when the decoder is closed.
+// Noise is generated at 8 kHz and resampled, if necessary.
+func NewDecoder(w media.PCM16Writer) *Decoder {
+	return &Decoder{
+		w:     w,
+		noise: media.ResampleWriter(media.NopCloser[media.PCM16Sample](w), SampleRate),
+		gen:   NewGenerator(),
+	}
+}
+
+// RegisterDecoder creates a comfort noise decoder writing to w and registers it in the mux for CN payload type.
+// The decoder must be used as an output of the regular audio decoder from the same mux, see NewDecoder.
+func RegisterDecoder(mux *rtp.Mux, typ byte, w media.PCM16Writer) *Decoder {
+	d := NewDecoder(w)
+	mux.Register(typ, d)
+	return d
+}
+
+// Decoder generates comfort noise. See NewDecoder.
+type Decoder struct {
+	mu     sync.Mutex
+	w      media.PCM16Writer
+	noise  media.PCM16Writer
+	gen    *Generator
+	stop   chan struct{}
+	closed bool
+}
+
+func (d *Decoder) String() string {
+	return fmt.Sprintf("CN(%d) -> %s", SampleRate, d.w)
+}
+
+func (d *Decoder) SampleRate() int {
+	return d.w.SampleRate()
+}
+
+// Active returns true if the decoder is generating noise.
+func (d *Decoder) Active() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stop != nil
+}
+
+// HandleRTP handles a CN payload and starts generating the noise.
+func (d *Decoder) HandleRTP(_ *rtp.Header, payload []byte) error {
+	s, err := Decode(payload)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil
+	}
+	d.gen.SetSID(s)
+	if d.stop == nil {
+		d.stop = make(chan struct{})
+		go d.run(d.stop)
+	}
+	return nil
+}
+
+// WriteSample writes the regular audio and stops the noise.
+func (d *Decoder) WriteSample(in media.PCM16Sample) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopNoise()
+	return d.w.WriteSample(in)
+}
+
+func (d *Decoder) stopNoise() {
+	if d.stop != nil {
+		close(d.stop)
+		d.stop = nil
+	}
+}
+
+func (d *Decoder) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	d.stopNoise()
+	// noise writer doesn't close w, but releases the resampler
+	nerr := d.noise.Close()
+	if err := d.w.Close(); err != nil {
+		return err
+	}
+	return nerr
+}
+
+func (d *Decoder) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(rtp.DefFrameDur)
+	defer ticker.Stop()
+	buf := make(media.PCM16Sample, SampleRate/rtp.DefFramesPerSec)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		d.mu.Lock()
+		select {
+		case <-stop:
+			d.mu.Unlock()
+			return
+		default:
+		}
+		d.gen.Generate(buf)
+		_ = d.noise.WriteSample(buf)
+		d.mu.Unlock()
+	}
+}
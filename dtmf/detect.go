@@ -0,0 +1,238 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtmf
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/internal/goertzel"
+)
+
+// Detection parameters. Timing and twist limits follow ITU-T Q.24.
+const (
+	// detectWindow is the size of the analysis window at 8 kHz (25.6 ms).
+	// It gives a frequency resolution good enough to separate DTMF frequencies.
+	detectWindow = 205
+	// detectMinHits is the number of consecutive windows (with 50% overlap) required to accept a digit.
+	// Three windows span ~50 ms, thus tones of 40 ms and longer are accepted, while tones shorter than 23 ms are rejected.
+	detectMinHits = 3
+	// detectMaxMisses is the number of consecutive windows without a digit that end the tone.
+	// It allows short interruptions (up to 10 ms) within a tone.
+	detectMaxMisses = 2
+
+	// detectMinLevel is the min level of each frequency in dBm0.
+	detectMinLevel = -36
+	// detectNormalTwist is the max level difference in dB when the high frequency is weaker than the low one.
+	detectNormalTwist = 8
+	// detectReverseTwist is the max level difference in dB when the high frequency is stronger than the low one.
+	detectReverseTwist = 4
+	// detectRelPeak is the min difference in dB between the detected frequency and other frequencies in the same group.
+	detectRelPeak = 6
+	// detectHarmonic is the min difference in dB between the high frequency and its second harmonic.
+	// Voice typically has strong harmonics, which helps to reject talk-off.
+	detectHarmonic = 10
+	// detectToneRatio is the min ratio of DTMF tones power to the total power of the signal.
+	detectToneRatio = 0.5
+
+	// dBm0 of a full scale sine wave in PCM16.
+	fullScaleDBm0 = 3.17
+)
+
+var (
+	dtmfLow  = [4]float64{dtmfLow1, dtmfLow2, dtmfLow3, dtmfLow4}
+	dtmfHigh = [4]float64{dtmfHigh1, dtmfHigh2, dtmfHigh3, dtmfHigh4}
+	// dtmfCode maps low and high frequency indexes to DTMF codes.
+	dtmfCode = [4][4]byte{
+		{code1, code2, code3, codeA},
+		{code4, code5, code6, codeB},
+		{code7, code8, code9, codeC},
+		{codeStar, code0, codeHash, codeD},
+	}
+)
+
+// NewDetector creates a PCM writer that detects in-band DTMF tones using the Goertzel algorithm.
+//
+// Handler is called once per each detected digit, after the tone ends. Event duration is set in RTP timestamp
+// units for telephone-event (8 kHz), and volume is an estimated tone level in dBm0.
+func NewDetector(sampleRate int, h Handler) *Detector {
+	if sampleRate < 8000 {
+		panic("sample rate is too low for DTMF detection")
+	}
+	n := detectWindow * sampleRate / 8000
+	d := &Detector{
+		h:          h,
+		sampleRate: sampleRate,
+		n:          n,
+		hop:        n / 2,
+		buf:        make(media.PCM16Sample, 0, 2*n),
+		cand:       0xff,
+	}
+	for i := range dtmfLow {
+		d.low[i] = goertzel.New(dtmfLow[i], sampleRate)
+		d.high[i] = goertzel.New(dtmfHigh[i], sampleRate)
+		d.harm[i] = goertzel.New(2*dtmfHigh[i], sampleRate)
+	}
+	fullScale := float64(math.MaxInt16) * float64(math.MaxInt16) / 2
+	d.minPower = fullScale * dbToPower(detectMinLevel-fullScaleDBm0)
+	return d
+}
+
+// Detector detects in-band DTMF tones. See NewDetector.
+type Detector struct {
+	h          Handler
+	sampleRate int
+	n          int // window size
+	hop        int // window step
+	minPower   float64
+
+	low, high, harm [4]goertzel.Filter
+
+	buf media.PCM16Sample
+	pos int64 // position of the window start in samples
+
+	cand     byte  // candidate digit code
+	hits     int   // consecutive windows with the candidate digit
+	misses   int   // consecutive windows without a digit
+	active   bool  // candidate digit is accepted
+	start    int64 // position of the first window with the candidate digit
+	end      int64 // end of the last window with the candidate digit
+	maxLevel float64
+}
+
+func (d *Detector) String() string {
+	return fmt.Sprintf("DTMFDetector(%d)", d.sampleRate)
+}
+
+func (d *Detector) SampleRate() int {
+	return d.sampleRate
+}
+
+// Close flushes the digit that is still active.
+func (d *Detector) Close() error {
+	d.endDigit()
+	return nil
+}
+
+func (d *Detector) WriteSample(in media.PCM16Sample) error {
+	d.buf = append(d.buf, in...)
+	for len(d.buf) >= d.n {
+		code, power, ok := d.analyze(d.buf[:d.n])
+		d.update(code, power, ok)
+		d.buf = d.buf[:copy(d.buf, d.buf[d.hop:])]
+		d.pos += int64(d.hop)
+	}
+	return nil
+}
+
+// update the state machine with the result of a single window.
+func (d *Detector) update(code byte, power float64, ok bool) {
+	if !ok {
+		d.misses++
+		if d.misses >= detectMaxMisses {
+			d.endDigit()
+		}
+		return
+	}
+	d.misses = 0
+	if code != d.cand {
+		d.endDigit()
+		d.cand = code
+		d.start = d.pos
+	}
+	d.hits++
+	d.end = d.pos + int64(d.n)
+	d.maxLevel = max(d.maxLevel, power)
+	if d.hits >= detectMinHits {
+		d.active = true
+	}
+}
+
+// endDigit ends the current candidate digit and emits an event, if it was accepted.
+func (d *Detector) endDigit() {
+	if d.active && d.h != nil {
+		// Windows partially covering the tone are detected as well, so adjust the duration by a half of the window.
+		dur := time.Duration(d.end-d.start-int64(d.hop)) * time.Second / time.Duration(d.sampleRate)
+		vol := -(10*math.Log10(d.maxLevel/(float64(math.MaxInt16)*float64(math.MaxInt16)/2)) + fullScaleDBm0)
+		d.h(Event{
+			Code:   d.cand,
+			Digit:  eventToChar[d.cand],
+			Volume: byte(min(max(math.Round(vol), 0), 63)),
+			Dur:    uint16(min(dur/(time.Second/SampleRate), math.MaxUint16)),
+			End:    true,
+		})
+	}
+	d.cand = 0xff
+	d.hits = 0
+	d.active = false
+	d.maxLevel = 0
+}
+
+// analyze a single window and return the detected DTMF code and its power.
+func (d *Detector) analyze(w media.PCM16Sample) (byte, float64, bool) {
+	var low, high [4]float64
+	for i := range low {
+		low[i] = d.low[i].Power(w)
+		high[i] = d.high[i].Power(w)
+	}
+	row, col := argmax(low[:]), argmax(high[:])
+	pl, ph := low[row], high[col]
+	if pl < d.minPower || ph < d.minPower {
+		return 0, 0, false
+	}
+	// twist
+	if pl > ph*dbToPower(detectNormalTwist) || ph > pl*dbToPower(detectReverseTwist) {
+		return 0, 0, false
+	}
+	// relative peak in each group
+	for i := range low {
+		if i != row && low[i]*dbToPower(detectRelPeak) > pl {
+			return 0, 0, false
+		}
+		if i != col && high[i]*dbToPower(detectRelPeak) > ph {
+			return 0, 0, false
+		}
+	}
+	// second harmonic
+	if d.harm[col].Power(w)*dbToPower(detectHarmonic) > ph {
+		return 0, 0, false
+	}
+	// total power
+	var total float64
+	for _, v := range w {
+		total += float64(v) * float64(v)
+	}
+	total /= float64(len(w))
+	if pl+ph < total*detectToneRatio {
+		return 0, 0, false
+	}
+	return dtmfCode[row][col], pl + ph, true
+}
+
+func argmax(arr []float64) int {
+	j := 0
+	for i, v := range arr {
+		if v > arr[j] {
+			j = i
+		}
+	}
+	return j
+}
+
+func dbToPower(db float64) float64 {
+	return math.Pow(10, db/10)
+}
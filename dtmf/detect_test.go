@@ -0,0 +1,152 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtmf
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/tones"
+)
+
+func TestDetector(t *testing.T) {
+	for _, rate := range []int{8000, 16000, 48000} {
+		t.Run(strconv.Itoa(rate), func(t *testing.T) {
+			t.Parallel()
+			var got []Event
+			d := NewDetector(rate, func(ev Event) {
+				got = append(got, ev)
+			})
+			const digits = "19*#0ad"
+			err := Write(context.Background(), d, nil, 0, digits)
+			require.NoError(t, err)
+			require.NoError(t, d.Close())
+
+			require.Len(t, got, len(digits))
+			for i, ev := range got {
+				require.Equal(t, digits[i], ev.Digit)
+				require.Equal(t, charToEvent[digits[i]], ev.Code)
+				require.True(t, ev.End)
+				require.InDelta(t, eventDur/(time.Second/SampleRate), ev.Dur, 0.02*SampleRate)
+				require.InDelta(t, 6, ev.Volume, 1) // two tones at -8.8 dBm0 each
+			}
+		})
+	}
+}
+
+// genTone generates a DTMF-like signal with a given duration and level of each frequency (in dBFS).
+func genTone(buf media.PCM16Sample, rate int, freq []float64, levels []float64) {
+	for i := range buf {
+		var v float64
+		for j, f := range freq {
+			v += math.MaxInt16 * math.Pow(10, levels[j]/20) * math.Sin(2*math.Pi*f*float64(i)/float64(rate))
+		}
+		buf[i] = int16(v)
+	}
+}
+
+func detect(rate int, samples ...media.PCM16Sample) []Event {
+	var got []Event
+	d := NewDetector(rate, func(ev Event) {
+		got = append(got, ev)
+	})
+	for _, s := range samples {
+		_ = d.WriteSample(s)
+	}
+	_ = d.Close()
+	return got
+}
+
+func TestDetectorTiming(t *testing.T) {
+	const rate = 8000
+	silence := make(media.PCM16Sample, rate/10)
+	tone := func(dur time.Duration) media.PCM16Sample {
+		buf := make(media.PCM16Sample, int(time.Duration(rate)*dur/time.Second))
+		genTone(buf, rate, []float64{dtmfLow2, dtmfHigh2}, []float64{-10, -10})
+		return buf
+	}
+	// Q.24: accept 40 ms tones, reject tones shorter than 23 ms
+	require.Len(t, detect(rate, silence, tone(45*time.Millisecond), silence), 1)
+	require.Empty(t, detect(rate, silence, tone(20*time.Millisecond), silence))
+
+	// short interruption doesn't split the digit
+	got := detect(rate, silence, tone(100*time.Millisecond), make(media.PCM16Sample, rate/200), tone(100*time.Millisecond), silence)
+	require.Len(t, got, 1)
+	require.Equal(t, byte('5'), got[0].Digit)
+
+	// but a pause does
+	got = detect(rate, silence, tone(100*time.Millisecond), silence, tone(100*time.Millisecond), silence)
+	require.Len(t, got, 2)
+}
+
+func TestDetectorReject(t *testing.T) {
+	const rate = 8000
+	buf := make(media.PCM16Sample, rate/2)
+	check := func(name string, freq []float64, levels []float64, exp bool) {
+		t.Run(name, func(t *testing.T) {
+			genTone(buf, rate, freq, levels)
+			got := detect(rate, buf)
+			if exp {
+				require.Len(t, got, 1)
+			} else {
+				require.Empty(t, got)
+			}
+		})
+	}
+	check("normal", []float64{dtmfLow1, dtmfHigh1}, []float64{-10, -10}, true)
+	check("normal twist", []float64{dtmfLow1, dtmfHigh1}, []float64{-10, -16}, true)
+	check("normal twist high", []float64{dtmfLow1, dtmfHigh1}, []float64{-10, -20}, false)
+	check("reverse twist", []float64{dtmfLow1, dtmfHigh1}, []float64{-13, -10}, true)
+	check("reverse twist high", []float64{dtmfLow1, dtmfHigh1}, []float64{-16, -10}, false)
+	check("too quiet", []float64{dtmfLow1, dtmfHigh1}, []float64{-45, -45}, false)
+	check("single", []float64{dtmfLow1}, []float64{-10}, false)
+	check("off frequency", []float64{dtmfLow1 * 1.05, dtmfHigh1 * 1.05}, []float64{-10, -10}, false)
+	check("two rows", []float64{dtmfLow1, dtmfLow2, dtmfHigh1}, []float64{-10, -10, -10}, false)
+
+	t.Run("talk-off", func(t *testing.T) {
+		// voice-like signal: harmonics of a varying pitch with noise
+		rnd := rand.New(rand.NewPCG(1, 2))
+		buf := make(media.PCM16Sample, 10*rate)
+		var ph float64
+		for i := range buf {
+			pitch := 110 + 40*math.Sin(2*math.Pi*float64(i)/rate)
+			ph += 2 * math.Pi * pitch / rate
+			var v float64
+			for h := 1; h <= 30; h++ {
+				v += math.Sin(float64(h)*ph) / float64(h)
+			}
+			buf[i] = int16(4000*v + 500*rnd.NormFloat64())
+		}
+		require.Empty(t, detect(rate, buf))
+	})
+
+	t.Run("dial tone", func(t *testing.T) {
+		var got []Event
+		d := NewDetector(rate, func(ev Event) {
+			got = append(got, ev)
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+		_ = tones.Play(ctx, d, math.MaxInt16/2, []tones.Tone{{Freq: []tones.Hz{350, 440}}})
+		require.Empty(t, got)
+	})
+}
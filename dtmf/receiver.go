@@ -0,0 +1,167 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtmf
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/livekit/media-sdk/rtp"
+)
+
+// ReceiverTimeout is the time after the last packet of an event when it's considered ended, if all end packets were lost.
+const ReceiverTimeout = time.Second
+
+// NewReceiver creates an RTP handler for telephone-event (RFC 4733) stream.
+//
+// Handler is called exactly once per key press, when the event ends, with the total event duration.
+// Receiver recovers events with the lost marker packet, ignores duplicate and reordered packets,
+// and joins segments of long-duration events. Durations longer than the max value of Event.Dur are clamped.
+//
+// If all end packets are lost, the event is reported when the next one starts, after ReceiverTimeout, or on Close.
+func NewReceiver(h Handler) *Receiver {
+	return &Receiver{h: h}
+}
+
+// Receiver handles telephone-event RTP packets. See NewReceiver.
+type Receiver struct {
+	h Handler
+
+	mu     sync.Mutex
+	timer  *time.Timer
+	init   bool
+	lastTS uint32 // timestamp of the last (current) event segment
+	cur    *receiverEvent
+	closed bool
+	events []Event // ended events, reported after unlocking
+}
+
+type receiverEvent struct {
+	ev     Event
+	segTS  uint32 // timestamp of the current segment
+	segDur uint32 // duration of the current segment
+	dur    uint32 // duration of all previous segments
+}
+
+var _ rtp.HandlerCloser = (*Receiver)(nil)
+
+func (r *Receiver) String() string {
+	return "DTMFReceiver"
+}
+
+func (r *Receiver) HandleRTP(h *rtp.Header, payload []byte) error {
+	ev, err := Decode(payload)
+	if err != nil {
+		return nil // ignore malformed packets
+	}
+	r.mu.Lock()
+	defer r.unlock()
+	if r.closed {
+		return nil
+	}
+	if r.init && h.Timestamp != r.lastTS {
+		if int32(h.Timestamp-r.lastTS) < 0 {
+			return nil // late packet of one of the previous events
+		}
+		if r.cur != nil && r.isContinuation(h, ev) {
+			// Next segment of a long-duration event.
+			r.cur.dur += r.cur.segDur
+			r.cur.segTS, r.cur.segDur = h.Timestamp, 0
+		} else {
+			// New event, while the previous one didn't end (end packets were lost).
+			r.emit()
+		}
+	}
+	if r.init && h.Timestamp == r.lastTS && r.cur == nil {
+		return nil // event already reported, duplicate end packets or late updates
+	}
+	r.init = true
+	r.lastTS = h.Timestamp
+	if r.cur == nil {
+		// Start of the event. Marker packet might be lost, so any packet with a new timestamp starts the event.
+		r.cur = &receiverEvent{ev: ev, segTS: h.Timestamp}
+	}
+	// Packets with the same timestamp might be reordered, so keep the max duration.
+	r.cur.segDur = max(r.cur.segDur, uint32(ev.Dur))
+	r.cur.ev.Volume = ev.Volume
+	if ev.End {
+		r.emit()
+		return nil
+	}
+	r.resetTimer()
+	return nil
+}
+
+// isContinuation checks if the packet is a new segment of the current long-duration event (RFC 4733, 2.5.1.3).
+func (r *Receiver) isContinuation(h *rtp.Header, ev Event) bool {
+	cur := r.cur
+	if h.Marker || ev.Code != cur.ev.Code {
+		return false
+	}
+	return cur.segDur == math.MaxUint16 || h.Timestamp == cur.segTS+cur.segDur
+}
+
+func (r *Receiver) resetTimer() {
+	if r.timer == nil {
+		r.timer = time.AfterFunc(ReceiverTimeout, r.onTimeout)
+	} else {
+		r.timer.Reset(ReceiverTimeout)
+	}
+}
+
+func (r *Receiver) onTimeout() {
+	r.mu.Lock()
+	defer r.unlock()
+	r.emit()
+}
+
+// emit the current event, if any.
+func (r *Receiver) emit() {
+	cur := r.cur
+	if cur == nil {
+		return
+	}
+	r.cur = nil
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	ev := cur.ev
+	ev.Dur = uint16(min(cur.dur+cur.segDur, math.MaxUint16))
+	ev.End = true
+	r.events = append(r.events, ev)
+}
+
+// unlock the receiver and call the handler for ended events.
+// Handler is called without the lock, so it may call methods of the Receiver.
+func (r *Receiver) unlock() {
+	events := r.events
+	r.events = nil
+	r.mu.Unlock()
+	if r.h == nil {
+		return
+	}
+	for _, ev := range events {
+		r.h(ev)
+	}
+}
+
+// Close reports the event that is still in progress and stops the receiver.
+func (r *Receiver) Close() {
+	r.mu.Lock()
+	defer r.unlock()
+	r.emit()
+	r.closed = true
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtmf
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk/rtp"
+)
+
+type receiverTest struct {
+	mu   sync.Mutex
+	got  []Event
+	recv *Receiver
+}
+
+func newReceiverTest() *receiverTest {
+	r := &receiverTest{}
+	r.recv = NewReceiver(func(ev Event) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.got = append(r.got, ev)
+	})
+	return r
+}
+
+func (r *receiverTest) Handle(t testing.TB, packets []*rtp.Packet) {
+	for _, p := range packets {
+		require.NoError(t, r.recv.HandleRTP(&p.Header, p.Payload))
+	}
+}
+
+func (r *receiverTest) Digits() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var s []byte
+	for _, ev := range r.got {
+		s = append(s, ev.Digit)
+	}
+	return string(s)
+}
+
+func writeEvents(t testing.TB, digits string) []*rtp.Packet {
+	var buf rtp.Buffer
+	w := rtp.NewSeqWriter(&buf).NewStream(101, SampleRate)
+	err := Write(context.Background(), nil, w, 1000, digits)
+	require.NoError(t, err)
+	return buf
+}
+
+func TestReceiver(t *testing.T) {
+	packets := writeEvents(t, "1w23")
+	const dur = 13 * 160
+
+	t.Run("normal", func(t *testing.T) {
+		r := newReceiverTest()
+		r.Handle(t, packets)
+		r.recv.Close()
+		require.Equal(t, "123", r.Digits())
+		for _, ev := range r.got {
+			require.EqualValues(t, dur, ev.Dur)
+			require.True(t, ev.End)
+			require.EqualValues(t, eventVolume, ev.Volume)
+		}
+	})
+
+	t.Run("lost marker", func(t *testing.T) {
+		r := newReceiverTest()
+		r.Handle(t, slices.DeleteFunc(slices.Clone(packets), func(p *rtp.Packet) bool {
+			return p.Marker
+		}))
+		r.recv.Close()
+		require.Equal(t, "123", r.Digits())
+	})
+
+	t.Run("lost end", func(t *testing.T) {
+		r := newReceiverTest()
+		r.Handle(t, slices.DeleteFunc(slices.Clone(packets), func(p *rtp.Packet) bool {
+			ev, err := Decode(p.Payload)
+			require.NoError(t, err)
+			return ev.End
+		}))
+		require.Equal(t, "12", r.Digits())
+		r.recv.Close()
+		require.Equal(t, "123", r.Digits())
+		for _, ev := range r.got {
+			require.EqualValues(t, dur-160, ev.Dur)
+		}
+	})
+
+	t.Run("reordered", func(t *testing.T) {
+		r := newReceiverTest()
+		reordered := slices.Clone(packets)
+		for i := 0; i+1 < len(reordered); i += 3 {
+			reordered[i], reordered[i+1] = reordered[i+1], reordered[i]
+		}
+		r.Handle(t, reordered)
+		r.recv.Close()
+		require.Equal(t, "123", r.Digits())
+		for _, ev := range r.got {
+			require.EqualValues(t, dur, ev.Dur)
+		}
+	})
+
+	t.Run("duplicated", func(t *testing.T) {
+		r := newReceiverTest()
+		var dup []*rtp.Packet
+		for _, p := range packets {
+			dup = append(dup, p, p)
+		}
+		r.Handle(t, dup)
+		r.recv.Close()
+		require.Equal(t, "123", r.Digits())
+	})
+
+	t.Run("close from handler", func(t *testing.T) {
+		var got []Event
+		var recv *Receiver
+		recv = NewReceiver(func(ev Event) {
+			got = append(got, ev)
+			recv.Close()
+		})
+		for _, p := range packets {
+			require.NoError(t, recv.HandleRTP(&p.Header, p.Payload))
+		}
+		require.Len(t, got, 1)
+		require.Equal(t, byte('1'), got[0].Digit)
+	})
+}
+
+func TestReceiverLongPress(t *testing.T) {
+	r := newReceiverTest()
+	var packets []*rtp.Packet
+	add := func(ts uint32, marker bool, dur uint16, end bool) {
+		var buf [4]byte
+		_, err := Encode(buf[:], Event{Digit: '5', Dur: dur, End: end})
+		require.NoError(t, err)
+		packets = append(packets, &rtp.Packet{
+			Header:  rtp.Header{SequenceNumber: uint16(len(packets)), Timestamp: ts, Marker: marker},
+			Payload: slices.Clone(buf[:]),
+		})
+	}
+	const start = 5000
+	add(start, true, 160, false)
+	add(start, false, 32000, false)
+	add(start, false, math.MaxUint16, false)
+	// next segment
+	add(start+math.MaxUint16, false, 160, false)
+	add(start+math.MaxUint16, false, 1000, true)
+	r.Handle(t, packets)
+	r.recv.Close()
+	require.Equal(t, "5", r.Digits())
+	require.EqualValues(t, math.MaxUint16, r.got[0].Dur) // clamped
+
+	// short event after a long one
+	r = newReceiverTest()
+	packets = packets[:0]
+	add(start, true, 160, false)
+	add(start, false, 320, true)
+	add(start+320, true, 160, true)
+	r.Handle(t, packets)
+	r.recv.Close()
+	require.Equal(t, "55", r.Digits())
+}
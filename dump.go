@@ -16,9 +16,19 @@ package media
 
 import (
 	"fmt"
+	"io"
 	"os"
 )
 
+// fileWriters maps file extensions to functions creating file writers. See RegisterFileWriter.
+var fileWriters = make(map[string]any)
+
+// RegisterFileWriter registers a writer of a file format for a given file extension.
+// DumpWriter uses it for files with this extension, instead of writing raw frames.
+func RegisterFileWriter[T Frame](ext string, fnc func(w io.WriteCloser, sampleRate int) WriteCloser[T]) {
+	fileWriters[ext] = fnc
+}
+
 func DumpWriterPCM16(name string, w PCM16Writer) PCM16Writer {
 	return DumpWriter[PCM16Sample]("s16le", name, w)
 }
@@ -30,8 +40,14 @@ func DumpWriter[T Frame](ext string, name string, w WriteCloser[T]) WriteCloser[
 	if err != nil {
 		panic(err)
 	}
+	var fw WriteCloser[T]
+	if fnc, ok := fileWriters[ext].(func(w io.WriteCloser, sampleRate int) WriteCloser[T]); ok {
+		fw = fnc(f, rate)
+	} else {
+		fw = NewFileWriter[T](f, rate)
+	}
 	return MultiWriter[T]{
 		w,
-		NewFileWriter[T](f, rate),
+		fw,
 	}
 }
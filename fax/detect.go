@@ -0,0 +1,334 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fax implements detection of fax signals in the audio.
+package fax
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"time"
+
+	"github.com/livekit/media-sdk"
+)
+
+// Signal is a type of the fax signal.
+type Signal int
+
+const (
+	// SignalCNG is a calling tone sent by the calling fax terminal (T.30): 1100 Hz, 0.5 s on, 3 s off.
+	SignalCNG Signal = iota + 1
+	// SignalCED is an answer tone sent by the called terminal (T.30, V.25): continuous 2100 Hz.
+	SignalCED
+	// SignalANSam is an answer tone with amplitude modulation (V.8): 2100 Hz, modulated with 15 Hz.
+	SignalANSam
+	// SignalV21 is a V.21 preamble of HDLC flags, which starts T.30 negotiation.
+	SignalV21
+)
+
+func (s Signal) String() string {
+	switch s {
+	case SignalCNG:
+		return "cng"
+	case SignalCED:
+		return "ced"
+	case SignalANSam:
+		return "ansam"
+	case SignalV21:
+		return "v21"
+	}
+	return fmt.Sprintf("Signal(%d)", int(s))
+}
+
+// Detection is a fax signal detected by the Detector.
+type Detection struct {
+	Signal Signal
+	// PhaseReversals is set for answer tones with phase reversals (/ANS and /ANSam),
+	// which request to disable network echo cancellers.
+	PhaseReversals bool
+	// Start is the time when the signal started, relative to the start of the audio stream.
+	Start time.Duration
+	// Time is the time when the signal was detected, relative to the start of the audio stream.
+	Time time.Duration
+}
+
+// Detection parameters.
+const (
+	// detectBlock is the analysis block for tones. Fax tones are multiples of 100 Hz, thus they are orthogonal in the block.
+	detectBlock = 10 * time.Millisecond
+	// detectMaxMisses is the number of consecutive blocks without a tone that end the tone.
+	// It allows dropouts caused by phase reversals.
+	detectMaxMisses = 3
+	// detectMinLevel is the min level of the signal in dBm0 (T.30 receiver sensitivity).
+	detectMinLevel = -43
+	// detectToneRatio is the min ratio of the tone power to the total power of the signal.
+	detectToneRatio = 0.7
+
+	cngFreq   = 1100
+	cngMinDur = 400 * time.Millisecond
+	cngMaxDur = 700 * time.Millisecond
+
+	cedFreq = 2100
+	// cedDetectDur is the duration of the answer tone required for detection.
+	// It covers two phase reversal intervals (450 ms) and many periods of the modulation.
+	cedDetectDur = time.Second
+	// cedModFreq and cedMinModDepth are parameters of ANSam amplitude modulation (20% at 15 Hz).
+	cedModFreq     = 15
+	cedMinModDepth = 0.1
+	// cedReversalGap is the min interval between phase reversals.
+	cedReversalGap = 100 * time.Millisecond
+
+	v21Baud  = 300
+	v21Mark  = 1650 // V.21 channel 2, binary 1
+	v21Space = 1850 // V.21 channel 2, binary 0
+	// v21MinFlags is the number of consecutive HDLC flags required for detection.
+	v21MinFlags = 6
+	// v21ToneRatio is the min ratio of the FSK power to the total power of the signal.
+	v21ToneRatio = 0.6
+	hdlcFlag     = 0x7e
+
+	// dBm0 of a full scale sine wave in PCM16.
+	fullScaleDBm0 = 3.17
+)
+
+// NewDetector creates a PCM writer that detects fax signals: CNG, CED/ANSam tones and V.21 preamble.
+//
+// Handler is called once for each signal type.
+func NewDetector(sampleRate int, h func(d Detection)) *Detector {
+	if sampleRate < 8000 {
+		panic("sample rate is too low for fax detection")
+	}
+	fullScale := float64(math.MaxInt16) * float64(math.MaxInt16) / 2
+	d := &Detector{
+		h:          h,
+		sampleRate: sampleRate,
+		n:          int(time.Duration(sampleRate) * detectBlock / time.Second),
+		minPower:   fullScale * math.Pow(10, (detectMinLevel-fullScaleDBm0)/10),
+	}
+	d.cng = newToneTracker(cngFreq, sampleRate, d.n)
+	d.ced = newToneTracker(cedFreq, sampleRate, d.n)
+	d.v21 = newFSK(sampleRate, d.minPower)
+	return d
+}
+
+// Detector detects fax signals. See NewDetector.
+type Detector struct {
+	h          func(d Detection)
+	sampleRate int
+	n          int // block size
+	minPower   float64
+
+	buf media.PCM16Sample
+	pos int // position of the current block in samples
+
+	cng      *toneTracker
+	ced      *toneTracker
+	v21      *fsk
+	reported [SignalV21 + 1]bool
+}
+
+func (d *Detector) String() string {
+	return fmt.Sprintf("FaxDetector(%d)", d.sampleRate)
+}
+
+func (d *Detector) SampleRate() int {
+	return d.sampleRate
+}
+
+func (d *Detector) Close() error {
+	return nil
+}
+
+func (d *Detector) WriteSample(in media.PCM16Sample) error {
+	if !d.reported[SignalV21] {
+		if start, end, ok := d.v21.process(in); ok {
+			d.report(Detection{Signal: SignalV21, Start: d.dur(start), Time: d.dur(end)})
+		}
+	}
+	d.buf = append(d.buf, in...)
+	for len(d.buf) >= d.n {
+		d.processBlock(d.buf[:d.n])
+		d.buf = d.buf[:copy(d.buf, d.buf[d.n:])]
+		d.pos += d.n
+	}
+	return nil
+}
+
+func (d *Detector) dur(samples int) time.Duration {
+	return time.Duration(samples) * time.Second / time.Duration(d.sampleRate)
+}
+
+func (d *Detector) report(v Detection) {
+	if d.reported[v.Signal] {
+		return
+	}
+	d.reported[v.Signal] = true
+	if d.h != nil {
+		d.h(v)
+	}
+}
+
+func (d *Detector) processBlock(b media.PCM16Sample) {
+	var total float64
+	for _, v := range b {
+		total += float64(v) * float64(v)
+	}
+	total /= float64(len(b))
+
+	if d.cng.update(b, d.pos, total, d.minPower) == toneEnded {
+		if dur := d.dur(d.cng.end - d.cng.start); dur >= cngMinDur && dur <= cngMaxDur {
+			d.report(Detection{Signal: SignalCNG, Start: d.dur(d.cng.start), Time: d.dur(d.pos + d.n)})
+		}
+	}
+	if d.ced.update(b, d.pos, total, d.minPower) == toneOn && d.dur(d.ced.end-d.ced.start) >= cedDetectDur {
+		sig := SignalCED
+		if d.ced.modDepth() >= cedMinModDepth {
+			sig = SignalANSam
+		}
+		d.report(Detection{
+			Signal:         sig,
+			PhaseReversals: d.ced.reversals != 0,
+			Start:          d.dur(d.ced.start),
+			Time:           d.dur(d.pos + d.n),
+		})
+	}
+}
+
+type toneState int
+
+const (
+	toneOff = toneState(iota)
+	toneOn
+	toneEnded
+)
+
+// toneTracker tracks a single frequency tone in consecutive blocks, including its phase and amplitude envelope.
+type toneTracker struct {
+	sampleRate int
+	w          float64    // angular frequency per sample
+	ref        float64    // phase of the reference at the start of the block
+	step       float64    // phase advance of the reference per block
+	revGap     int        // min samples between phase reversals
+	on         bool       // tone is present
+	start, end int        // tone position in samples
+	misses     int        // consecutive blocks without the tone
+	blocks     int        // blocks with the tone
+	prev       complex128 // correlation of the previous block with the tone
+	prevBlock  int        // position of the previous block with the tone
+	drift      float64    // phase drift per block, caused by the frequency offset
+	lastDev    float64    // phase deviation from the drift in the previous block
+	lastRev    int        // position of the last phase reversal
+	reversals  int
+	envI, envQ float64 // amplitude envelope correlation with the modulation frequency
+	envSum     float64
+}
+
+func newToneTracker(freq float64, sampleRate, n int) *toneTracker {
+	w := 2 * math.Pi * freq / float64(sampleRate)
+	return &toneTracker{
+		sampleRate: sampleRate,
+		w:          w,
+		step:       math.Mod(w*float64(n), 2*math.Pi),
+		revGap:     int(time.Duration(sampleRate) * cedReversalGap / time.Second),
+	}
+}
+
+// update the tracker with the next block and return the state of the tone.
+func (t *toneTracker) update(b media.PCM16Sample, pos int, total, minPower float64) toneState {
+	var z complex128
+	for i, v := range b {
+		s, c := math.Sincos(t.w*float64(i) + t.ref)
+		z += complex(float64(v)*c, -float64(v)*s)
+	}
+	t.ref = math.Mod(t.ref+t.step, 2*math.Pi)
+	n := float64(len(b))
+	power := 2 * real(z*cmplx.Conj(z)) / (n * n)
+	if power < minPower || power < total*detectToneRatio {
+		if !t.on {
+			return toneOff
+		}
+		t.misses++
+		if t.misses <= detectMaxMisses {
+			return toneOn
+		}
+		t.on = false
+		return toneEnded
+	}
+	if !t.on {
+		t.reset(pos)
+	} else {
+		t.trackPhase(z, (pos-t.prevBlock)/len(b), pos)
+	}
+	t.blocks++
+	t.misses = 0
+	t.end = pos + len(b)
+	t.prev, t.prevBlock = z, pos
+
+	// envelope for amplitude modulation
+	amp := 2 * cmplx.Abs(z) / n
+	s, c := math.Sincos(2 * math.Pi * cedModFreq * float64(pos-t.start) / float64(t.sampleRate))
+	t.envI += amp * c
+	t.envQ += amp * s
+	t.envSum += amp
+	return toneOn
+}
+
+func (t *toneTracker) reset(pos int) {
+	t.on = true
+	t.start = pos
+	t.blocks = 0
+	t.drift, t.lastDev = 0, 0
+	t.lastRev = pos - t.revGap
+	t.reversals = 0
+	t.envI, t.envQ, t.envSum = 0, 0, 0
+}
+
+// trackPhase detects phase reversals of the tone. Blocks are the number of blocks since the previous one with the tone.
+func (t *toneTracker) trackPhase(z complex128, blocks, pos int) {
+	dphi := cmplx.Phase(z * cmplx.Conj(t.prev))
+	if t.blocks == 1 {
+		// initial estimate of the frequency offset
+		t.drift = dphi / float64(blocks)
+		return
+	}
+	dev := wrapPhase(dphi - t.drift*float64(blocks))
+	if math.Abs(dev) < math.Pi/4 {
+		t.drift += dev / 2 / float64(blocks)
+	}
+	// Reversals in the middle of the block are spread over two blocks.
+	if math.Abs(dev+t.lastDev) > 2*math.Pi/3 && pos-t.lastRev >= t.revGap {
+		t.reversals++
+		t.lastRev = pos
+		t.lastDev = 0
+		return
+	}
+	t.lastDev = dev
+}
+
+// modDepth returns the depth of the amplitude modulation of the tone.
+func (t *toneTracker) modDepth() float64 {
+	if t.envSum == 0 {
+		return 0
+	}
+	return 2 * math.Hypot(t.envI, t.envQ) / t.envSum
+}
+
+func wrapPhase(v float64) float64 {
+	v = math.Mod(v+math.Pi, 2*math.Pi)
+	if v < 0 {
+		v += 2 * math.Pi
+	}
+	return v - math.Pi
+}
@@ -0,0 +1,233 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fax
+
+import (
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/res"
+	"github.com/livekit/media-sdk/res/testdata"
+	"github.com/livekit/media-sdk/tones"
+)
+
+const testAmp = 8000
+
+// signalGen generates a test signal sample by sample with a continuous phase.
+type signalGen struct {
+	rate  int
+	phase float64
+	out   media.PCM16Sample
+}
+
+func (g *signalGen) dur(d time.Duration) int {
+	return int(time.Duration(g.rate) * d / time.Second)
+}
+
+func (g *signalGen) tone(freq float64, d time.Duration) {
+	g.toneFunc(d, func(float64) (float64, float64) { return freq, 1 })
+}
+
+func (g *signalGen) toneFunc(d time.Duration, fnc func(t float64) (freq, amp float64)) {
+	for i := range g.dur(d) {
+		freq, amp := fnc(float64(i) / float64(g.rate))
+		g.phase += 2 * math.Pi * freq / float64(g.rate)
+		g.out = append(g.out, int16(testAmp*amp*math.Sin(g.phase)))
+	}
+}
+
+func (g *signalGen) silence(d time.Duration) {
+	g.out = append(g.out, make(media.PCM16Sample, g.dur(d))...)
+}
+
+// answerTone generates a 2100 Hz tone with an optional amplitude modulation and phase reversals.
+func (g *signalGen) answerTone(d time.Duration, offset float64, am, reversals bool) {
+	rev := 0
+	g.toneFunc(d, func(t float64) (float64, float64) {
+		amp := 1.0
+		if am {
+			amp = 0.8 + 0.2*math.Sin(2*math.Pi*15*t)
+		}
+		if n := int(t / 0.45); reversals && n != rev {
+			rev = n
+			g.phase += math.Pi
+		}
+		return 2100 + offset, amp
+	})
+}
+
+// v21 generates V.21 channel 2 FSK with given bytes, sent LSB first.
+func (g *signalGen) v21(data []byte) {
+	bitLen := float64(g.rate) / 300
+	for i := 0; i < int(float64(len(data)*8)*bitLen); i++ {
+		b := int(float64(i) / bitLen)
+		freq := 1850.0
+		if data[b/8]>>(b%8)&1 != 0 {
+			freq = 1650
+		}
+		g.phase += 2 * math.Pi * freq / float64(g.rate)
+		g.out = append(g.out, int16(testAmp*math.Sin(g.phase)))
+	}
+}
+
+func detect(t testing.TB, rate int, samples media.PCM16Sample) []Detection {
+	var got []Detection
+	d := NewDetector(rate, func(v Detection) {
+		got = append(got, v)
+	})
+	frame := rate / media.DefFramesPerSec
+	for len(samples) > 0 {
+		n := min(frame, len(samples))
+		require.NoError(t, d.WriteSample(samples[:n]))
+		samples = samples[n:]
+	}
+	require.NoError(t, d.Close())
+	return got
+}
+
+func TestDetector(t *testing.T) {
+	flags := make([]byte, 40)
+	for i := range flags {
+		flags[i] = hdlcFlag
+	}
+	cases := []struct {
+		name string
+		gen  func(g *signalGen)
+		exp  Detection
+	}{
+		{
+			name: "cng",
+			gen: func(g *signalGen) {
+				for range 3 {
+					g.tone(1100, 500*time.Millisecond)
+					g.silence(3 * time.Second)
+				}
+			},
+			exp: Detection{Signal: SignalCNG, Start: 0, Time: 540 * time.Millisecond},
+		},
+		{
+			name: "ced",
+			gen: func(g *signalGen) {
+				g.silence(200 * time.Millisecond)
+				g.answerTone(3*time.Second, 0, false, false)
+			},
+			exp: Detection{Signal: SignalCED, Start: 200 * time.Millisecond, Time: 1200 * time.Millisecond},
+		},
+		{
+			name: "ans with reversals",
+			gen: func(g *signalGen) {
+				g.answerTone(3*time.Second, 10, false, true)
+			},
+			exp: Detection{Signal: SignalCED, PhaseReversals: true, Time: time.Second},
+		},
+		{
+			name: "ansam",
+			gen: func(g *signalGen) {
+				g.answerTone(3*time.Second, -5, true, false)
+			},
+			exp: Detection{Signal: SignalANSam, Time: time.Second},
+		},
+		{
+			name: "ansam with reversals",
+			gen: func(g *signalGen) {
+				g.answerTone(3*time.Second, 0, true, true)
+			},
+			exp: Detection{Signal: SignalANSam, PhaseReversals: true, Time: time.Second},
+		},
+		{
+			name: "v21",
+			gen: func(g *signalGen) {
+				g.silence(100 * time.Millisecond)
+				g.v21(flags)
+			},
+			exp: Detection{Signal: SignalV21, Start: 100 * time.Millisecond, Time: 100*time.Millisecond + 6*8*time.Second/300},
+		},
+	}
+	for _, rate := range []int{8000, 16000, 48000} {
+		for _, c := range cases {
+			t.Run(strconv.Itoa(rate)+"/"+c.name, func(t *testing.T) {
+				g := &signalGen{rate: rate}
+				c.gen(g)
+				got := detect(t, rate, g.out)
+				require.Len(t, got, 1)
+				require.Equal(t, c.exp.Signal, got[0].Signal)
+				require.Equal(t, c.exp.PhaseReversals, got[0].PhaseReversals)
+				require.InDelta(t, c.exp.Start, got[0].Start, float64(20*time.Millisecond))
+				require.InDelta(t, c.exp.Time, got[0].Time, float64(20*time.Millisecond))
+			})
+		}
+	}
+}
+
+func TestDetectorSequence(t *testing.T) {
+	const rate = 8000
+	g := &signalGen{rate: rate}
+	g.tone(1100, 500*time.Millisecond)
+	g.silence(time.Second)
+	g.answerTone(2600*time.Millisecond, 0, false, false)
+	g.silence(75 * time.Millisecond)
+	flags := make([]byte, 30)
+	for i := range flags {
+		flags[i] = hdlcFlag
+	}
+	g.v21(flags)
+
+	var got []Signal
+	for _, v := range detect(t, rate, g.out) {
+		got = append(got, v.Signal)
+	}
+	require.Equal(t, []Signal{SignalCNG, SignalCED, SignalV21}, got)
+}
+
+func TestDetectorReject(t *testing.T) {
+	t.Run("speech", func(t *testing.T) {
+		var samples media.PCM16Sample
+		for _, f := range res.ReadOggAudioFile(testdata.TestAudioOgg, res.SampleRate, 1) {
+			samples = append(samples, f...)
+		}
+		require.Empty(t, detect(t, res.SampleRate, samples))
+	})
+	t.Run("v21 data", func(t *testing.T) {
+		rnd := rand.New(rand.NewPCG(1, 2))
+		data := make([]byte, 100)
+		for i := range data {
+			data[i] = byte(rnd.IntN(256))
+		}
+		g := &signalGen{rate: 8000}
+		g.v21(data)
+		require.Empty(t, detect(t, 8000, g.out))
+	})
+	t.Run("short tones", func(t *testing.T) {
+		g := &signalGen{rate: 8000}
+		g.tone(1100, 200*time.Millisecond)
+		g.silence(time.Second)
+		g.tone(1100, 2*time.Second)
+		g.silence(time.Second)
+		g.tone(2100, 500*time.Millisecond)
+		g.silence(time.Second)
+		require.Empty(t, detect(t, 8000, g.out))
+	})
+	t.Run("call progress", func(t *testing.T) {
+		buf := make(media.PCM16Sample, 5*8000)
+		tones.Generate(buf, 0, 5*time.Second, testAmp, []tones.Hz{350, 440})
+		require.Empty(t, detect(t, 8000, buf))
+	})
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fax
+
+import (
+	"math"
+	"math/cmplx"
+
+	"github.com/livekit/media-sdk"
+)
+
+// fsk is a V.21 channel 2 demodulator that counts HDLC flags.
+//
+// Mark and space frequencies are correlated with the signal in a sliding window of one bit.
+// Bit clock is recovered from transitions between mark and space.
+type fsk struct {
+	n        int     // window size, one bit
+	bitLen   float64 // bit length in samples
+	minPower float64
+
+	// sliding window state
+	hist         []float64    // signal history
+	mark, space  []complex128 // history of mark and space correlations
+	rotM, rotS   complex128   // reference phasors for the current sample
+	stepM, stepS complex128   // phasor rotation per sample
+	sumM, sumS   complex128
+	sumP         float64
+	idx          int // position in the history ring
+	pos          int // position of the current sample
+
+	present   bool    // FSK signal is present
+	bit       bool    // last bit decision
+	nextBit   float64 // position of the next bit sample
+	bits      uint8   // last 8 bits
+	sinceFlag int     // bits since the last flag
+	flags     int     // consecutive flags
+	start     int     // start of the flag sequence
+}
+
+func newFSK(sampleRate int, minPower float64) *fsk {
+	n := int(math.Round(float64(sampleRate) / v21Baud))
+	step := func(f float64) complex128 {
+		return cmplx.Rect(1, -2*math.Pi*f/float64(sampleRate))
+	}
+	return &fsk{
+		n:        n,
+		bitLen:   float64(sampleRate) / v21Baud,
+		minPower: minPower,
+		hist:     make([]float64, n),
+		mark:     make([]complex128, n),
+		space:    make([]complex128, n),
+		rotM:     1,
+		rotS:     1,
+		stepM:    step(v21Mark),
+		stepS:    step(v21Space),
+	}
+}
+
+// process the audio and return the position of the flag sequence, once enough flags are received.
+func (f *fsk) process(in media.PCM16Sample) (int, int, bool) {
+	for _, v := range in {
+		if f.sample(float64(v)) {
+			return f.start, f.pos, true
+		}
+	}
+	return 0, 0, false
+}
+
+func (f *fsk) sample(x float64) bool {
+	m, s := complex(x, 0)*f.rotM, complex(x, 0)*f.rotS
+	f.sumM += m - f.mark[f.idx]
+	f.sumS += s - f.space[f.idx]
+	f.sumP += x*x - f.hist[f.idx]*f.hist[f.idx]
+	f.mark[f.idx], f.space[f.idx], f.hist[f.idx] = m, s, x
+	f.rotM *= f.stepM
+	f.rotS *= f.stepS
+	f.idx++
+	f.pos++
+	if f.idx == f.n {
+		f.idx = 0
+		f.resync()
+	}
+	if f.pos < f.n {
+		return false
+	}
+
+	n := float64(f.n)
+	pm := 2 * real(f.sumM*cmplx.Conj(f.sumM)) / (n * n)
+	ps := 2 * real(f.sumS*cmplx.Conj(f.sumS)) / (n * n)
+	total := f.sumP / n
+	if total < f.minPower || max(pm, ps) < total*v21ToneRatio {
+		f.present = false
+		f.flags = 0
+		return false
+	}
+	bit := pm > ps
+	if !f.present || bit != f.bit {
+		// Decision changes in the middle of the window, thus the next bit is fully in the window after a half of the bit.
+		f.present = true
+		f.bit = bit
+		f.nextBit = float64(f.pos) + f.bitLen/2
+	}
+	if float64(f.pos) < f.nextBit {
+		return false
+	}
+	f.nextBit += f.bitLen
+	return f.addBit(bit)
+}
+
+// resync recalculates sliding sums to avoid accumulation of rounding errors, and normalizes reference phasors.
+func (f *fsk) resync() {
+	f.sumM, f.sumS, f.sumP = 0, 0, 0
+	for i := range f.hist {
+		f.sumM += f.mark[i]
+		f.sumS += f.space[i]
+		f.sumP += f.hist[i] * f.hist[i]
+	}
+	f.rotM /= complex(cmplx.Abs(f.rotM), 0)
+	f.rotS /= complex(cmplx.Abs(f.rotS), 0)
+}
+
+// addBit adds the next bit and returns true when enough consecutive HDLC flags are received.
+func (f *fsk) addBit(bit bool) bool {
+	f.bits <<= 1
+	if bit {
+		f.bits |= 1
+	}
+	f.sinceFlag++
+	if f.bits != hdlcFlag {
+		if f.sinceFlag >= 8 {
+			f.flags = 0
+		}
+		return false
+	}
+	if f.flags == 0 || f.sinceFlag != 8 {
+		f.flags = 0
+		f.start = f.pos - int(8*f.bitLen)
+	}
+	f.flags++
+	f.sinceFlag = 0
+	return f.flags >= v21MinFlags
+}
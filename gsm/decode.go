@@ -0,0 +1,199 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gsm
+
+// decoderState is the state of GSM 06.10 decoder.
+type decoderState struct {
+	dp0   [160]int16 // reconstructed short term residual
+	nrp   int16      // last valid LTP lag
+	larpp [2][8]int16
+	j     int
+	v     [9]int16 // short term synthesis filter state
+	msr   int16    // deemphasis filter state
+}
+
+func newDecoderState() *decoderState {
+	return &decoderState{nrp: 40}
+}
+
+// decode decodes frame parameters to 160 samples of PCM (section 4.3).
+func (s *decoderState) decode(out []int16, f *frame) {
+	var (
+		erp [40]int16
+		wt  [160]int16
+	)
+	for j := range 4 {
+		rpeDecoding(f.xmaxc[j], f.Mc[j], &f.xMc[j], &erp)
+		s.longTermSynthesis(f.Nc[j], f.bc[j], &erp)
+		copy(wt[j*40:], s.dp0[120:160])
+	}
+	s.shortTermSynthesis(&f.LARc, &wt, out)
+	s.postprocessing(out[:160])
+}
+
+// longTermSynthesis implements section 4.3.2.
+// The subframe is reconstructed to dp0[120:160], with 120 samples of history before it.
+func (s *decoderState) longTermSynthesis(Ncr, bcr int16, erp *[40]int16) {
+	Nr := Ncr
+	if Ncr < 40 || Ncr > 120 {
+		Nr = s.nrp
+	}
+	s.nrp = Nr
+	brp := tabQLB[bcr]
+	drp := s.dp0[:160]
+	for k := range 40 {
+		drpp := multR(brp, drp[120+k-int(Nr)])
+		drp[120+k] = add(erp[k], drpp)
+	}
+	copy(drp[:120], drp[40:160])
+}
+
+// shortTermSynthesis implements section 4.3.3.
+func (s *decoderState) shortTermSynthesis(LARcr *[8]int16, wt *[160]int16, out []int16) {
+	larppJ := &s.larpp[s.j]
+	s.j ^= 1
+	larppJ1 := &s.larpp[s.j]
+	decodeLAR(LARcr, larppJ)
+
+	for _, seg := range larSegments {
+		var rp [8]int16
+		seg.interpolate(larppJ1, larppJ, &rp)
+		larpToRp(&rp)
+		s.synthesisFilter(&rp, wt[seg.start:seg.end], out[seg.start:seg.end])
+	}
+}
+
+func (s *decoderState) synthesisFilter(rrp *[8]int16, wt, sr []int16) {
+	v := &s.v
+	for k, sri := range wt {
+		for i := 7; i >= 0; i-- {
+			sri = sub(sri, multR(rrp[i], v[i]))
+			v[i+1] = add(v[i], multR(rrp[i], sri))
+		}
+		v[0] = sri
+		sr[k] = sri
+	}
+}
+
+// postprocessing implements deemphasis, upscaling and truncation (section 4.3.5).
+func (s *decoderState) postprocessing(out []int16) {
+	msr := s.msr
+	for k, v := range out {
+		msr = add(v, multR(msr, 28180))
+		out[k] = add(msr, msr) &^ 7
+	}
+	s.msr = msr
+}
+
+// rpeDecoding implements section 4.3.1.
+func rpeDecoding(xmaxcr, Mcr int16, xMcr *[13]int16, erp *[40]int16) {
+	exp, mant := xmaxcToExpMant(xmaxcr)
+	var xMp [13]int16
+	apcmInverseQuantization(xMcr, mant, exp, &xMp)
+	rpeGridPositioning(Mcr, &xMp, erp)
+}
+
+// xmaxcToExpMant computes exponent and mantissa of the decoded xmaxc.
+func xmaxcToExpMant(xmaxc int16) (exp, mant int16) {
+	if xmaxc > 15 {
+		exp = xmaxc>>3 - 1
+	}
+	mant = xmaxc - exp<<3
+	if mant == 0 {
+		return -4, 7
+	}
+	for mant <= 7 {
+		mant = mant<<1 | 1
+		exp--
+	}
+	return exp, mant - 8
+}
+
+func apcmInverseQuantization(xMc *[13]int16, mant, exp int16, xMp *[13]int16) {
+	temp1 := tabFAC[mant]
+	temp2 := sub(6, exp)
+	temp3 := asl(1, sub(temp2, 1))
+	for i, v := range xMc {
+		temp := (v<<1 - 7) << 12 // restore sign
+		temp = multR(temp1, temp)
+		temp = add(temp, temp3)
+		xMp[i] = asr(temp, temp2)
+	}
+}
+
+func rpeGridPositioning(Mc int16, xMp *[13]int16, ep *[40]int16) {
+	clear(ep[:])
+	for i, v := range xMp {
+		ep[int(Mc)+3*i] = v
+	}
+}
+
+// decodeLAR decodes coded Log-Area Ratios (section 4.2.8).
+func decodeLAR(LARc, LARpp *[8]int16) {
+	for i, v := range LARc {
+		temp := add(v, tabMIC[i]) << 10
+		temp = sub(temp, tabB[i]<<1)
+		temp = multR(tabINVA[i], temp)
+		LARpp[i] = add(temp, temp)
+	}
+}
+
+// larSegment is a range of samples which use the same interpolation of LAR parameters (section 4.2.9.1).
+type larSegment struct {
+	start, end  int
+	interpolate func(prev, cur, out *[8]int16)
+}
+
+var larSegments = []larSegment{
+	{0, 13, func(prev, cur, out *[8]int16) {
+		for i := range out {
+			out[i] = add(prev[i]>>2, cur[i]>>2)
+			out[i] = add(out[i], prev[i]>>1)
+		}
+	}},
+	{13, 27, func(prev, cur, out *[8]int16) {
+		for i := range out {
+			out[i] = add(prev[i]>>1, cur[i]>>1)
+		}
+	}},
+	{27, 40, func(prev, cur, out *[8]int16) {
+		for i := range out {
+			out[i] = add(prev[i]>>2, cur[i]>>2)
+			out[i] = add(out[i], cur[i]>>1)
+		}
+	}},
+	{40, 160, func(prev, cur, out *[8]int16) {
+		*out = *cur
+	}},
+}
+
+// larpToRp converts interpolated LAR parameters to reflection coefficients (section 4.2.9.2).
+func larpToRp(LARp *[8]int16) {
+	for i, v := range LARp {
+		temp := abs(v)
+		switch {
+		case temp < 11059:
+			temp <<= 1
+		case temp < 20070:
+			temp += 11059
+		default:
+			temp = add(temp>>2, 26112)
+		}
+		if v < 0 {
+			temp = -temp
+		}
+		LARp[i] = temp
+	}
+}
@@ -0,0 +1,372 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gsm
+
+// encoderState is the state of GSM 06.10 encoder. Zero value is a valid initial state.
+type encoderState struct {
+	dp0   [280]int16 // reconstructed short term residual: 120 samples of history and the current frame
+	z1    int16      // offset compensation state
+	lz2   int32
+	mp    int16 // preemphasis state
+	u     [8]int16
+	larpp [2][8]int16
+	j     int
+}
+
+// encode encodes 160 samples of PCM to frame parameters (section 4.2).
+func (s *encoderState) encode(f *frame, in []int16) {
+	var (
+		so [160]int16
+		e  [50]int16 // e[5:45] is the current subframe, the rest is zero padding for the weighting filter
+	)
+	s.preprocess(&so, in[:160])
+	lpcAnalysis(&so, &f.LARc)
+	s.shortTermAnalysis(&f.LARc, &so)
+
+	for j := range 4 {
+		dp := s.dp0[40*j : 120+40*(j+1)] // 120 samples of history and the subframe
+		d := so[40*j : 40*(j+1)]
+		f.Nc[j], f.bc[j] = ltpParameters(d, dp[:120])
+		dpp := ltpFiltering(f.bc[j], f.Nc[j], dp, d, e[5:45])
+		f.xmaxc[j], f.Mc[j] = rpeEncoding(&e, &f.xMc[j])
+		for i := range 40 {
+			dp[120+i] = add(e[5+i], dpp[i])
+		}
+	}
+	copy(s.dp0[:120], s.dp0[160:])
+}
+
+// preprocess implements downscaling, offset compensation and preemphasis (section 4.2.1 - 4.2.3).
+func (s *encoderState) preprocess(so *[160]int16, in []int16) {
+	z1, lz2, mp := s.z1, s.lz2, s.mp
+	for k, v := range in {
+		SO := v >> 3 << 2
+
+		s1 := SO - z1
+		z1 = SO
+
+		ls2 := int32(s1) << 15
+		msp := int16(lz2 >> 15)
+		lsp := int16(lz2 - int32(msp)<<15)
+		ls2 += int32(multR(lsp, 32735))
+		lz2 = ladd(int32(msp)*32735, ls2)
+
+		// sof[k] with rounding
+		temp := ladd(lz2, 16384)
+
+		msp = multR(mp, -28180)
+		mp = int16(temp >> 15)
+		so[k] = add(mp, msp)
+	}
+	s.z1, s.lz2, s.mp = z1, lz2, mp
+}
+
+// lpcAnalysis computes and codes Log-Area Ratios (section 4.2.4 - 4.2.7).
+func lpcAnalysis(s *[160]int16, LARc *[8]int16) {
+	var acf [9]int32
+	autocorrelation(s, &acf)
+	reflectionCoefficients(&acf, LARc)
+	toLogAreaRatios(LARc)
+	quantizeLAR(LARc)
+}
+
+// autocorrelation computes autocorrelation with dynamic scaling of the input.
+// The input is rescaled in place, which is lossy, but it is exactly what the reference does.
+func autocorrelation(s *[160]int16, acf *[9]int32) {
+	var smax int16
+	for _, v := range s {
+		if v := abs(v); v > smax {
+			smax = v
+		}
+	}
+	var scalauto int16
+	if smax != 0 {
+		scalauto = 4 - norm(int32(smax)<<16)
+	}
+	if scalauto > 0 {
+		for k, v := range s {
+			s[k] = multR(v, 16384>>(scalauto-1))
+		}
+	}
+	for k := range acf {
+		var sum int32
+		for i := k; i < 160; i++ {
+			sum += int32(s[i]) * int32(s[i-k])
+		}
+		acf[k] = sum << 1
+	}
+	if scalauto > 0 {
+		for k := range s {
+			s[k] <<= scalauto
+		}
+	}
+}
+
+// reflectionCoefficients implements Schur recursion with 16 bit arithmetic.
+func reflectionCoefficients(acf *[9]int32, r *[8]int16) {
+	*r = [8]int16{}
+	if acf[0] == 0 {
+		return
+	}
+	temp := norm(acf[0])
+	var ACF, P, K [9]int16
+	for i, v := range acf {
+		ACF[i] = int16((v << temp) >> 16)
+	}
+	copy(K[1:8], ACF[1:8])
+	P = ACF
+
+	for n := 1; n <= 8; n++ {
+		temp := abs(P[1])
+		if P[0] < temp {
+			return
+		}
+		rn := div(temp, P[0])
+		if P[1] > 0 {
+			rn = -rn
+		}
+		r[n-1] = rn
+		if n == 8 {
+			return
+		}
+		P[0] = add(P[0], multR(P[1], rn))
+		for m := 1; m <= 8-n; m++ {
+			P[m] = add(P[m+1], multR(K[m], rn))
+			K[m] = add(K[m], multR(P[m+1], rn))
+		}
+	}
+}
+
+// toLogAreaRatios transforms reflection coefficients to Log-Area Ratios (section 4.2.6).
+func toLogAreaRatios(r *[8]int16) {
+	for i, v := range r {
+		temp := abs(v)
+		switch {
+		case temp < 22118:
+			temp >>= 1
+		case temp < 31130:
+			temp -= 11059
+		default:
+			temp = (temp - 26112) << 2
+		}
+		if v < 0 {
+			temp = -temp
+		}
+		r[i] = temp
+	}
+}
+
+// quantizeLAR quantizes and codes Log-Area Ratios (section 4.2.7).
+func quantizeLAR(LAR *[8]int16) {
+	for i, v := range LAR {
+		temp := mult(tabA[i], v)
+		temp = add(temp, tabB[i])
+		temp = add(temp, 256)
+		temp >>= 9
+		switch {
+		case temp > tabMAC[i]:
+			temp = tabMAC[i] - tabMIC[i]
+		case temp < tabMIC[i]:
+			temp = 0
+		default:
+			temp -= tabMIC[i]
+		}
+		LAR[i] = temp
+	}
+}
+
+// shortTermAnalysis implements short term analysis filtering of the frame in place (section 4.2.8 - 4.2.10).
+func (s *encoderState) shortTermAnalysis(LARc *[8]int16, so *[160]int16) {
+	larppJ := &s.larpp[s.j]
+	s.j ^= 1
+	larppJ1 := &s.larpp[s.j]
+	decodeLAR(LARc, larppJ)
+
+	for _, seg := range larSegments {
+		var rp [8]int16
+		seg.interpolate(larppJ1, larppJ, &rp)
+		larpToRp(&rp)
+		s.analysisFilter(&rp, so[seg.start:seg.end])
+	}
+}
+
+func (s *encoderState) analysisFilter(rp *[8]int16, so []int16) {
+	u := &s.u
+	for k, di := range so {
+		sav := di
+		for i := range u {
+			ui := u[i]
+			u[i] = sav
+			sav = add(ui, multR(rp[i], di))
+			di = add(di, multR(rp[i], ui))
+		}
+		so[k] = di
+	}
+}
+
+// ltpParameters computes the LTP lag and the coded LTP gain (section 4.2.11).
+// The dp contains 120 samples of the reconstructed short term residual preceding the subframe d.
+func ltpParameters(d, dp []int16) (Nc, bc int16) {
+	var dmax int16
+	for _, v := range d {
+		if v := abs(v); v > dmax {
+			dmax = v
+		}
+	}
+	var temp int16
+	if dmax != 0 {
+		temp = norm(int32(dmax) << 16)
+	}
+	var scal int16
+	if temp <= 6 {
+		scal = 6 - temp
+	}
+	var wt [40]int16
+	for k, v := range d {
+		wt[k] = v >> scal
+	}
+
+	// search for the maximum cross-correlation
+	var lmax int32
+	Nc = 40
+	for lambda := 40; lambda <= 120; lambda++ {
+		var sum int32
+		for k, v := range wt {
+			sum += int32(v) * int32(dp[120+k-lambda])
+		}
+		if sum > lmax {
+			Nc = int16(lambda)
+			lmax = sum
+		}
+	}
+	lmax <<= 1
+	lmax >>= 6 - scal
+
+	// power of the reconstructed short term residual
+	var lpower int32
+	for k := range 40 {
+		v := int32(dp[120+k-int(Nc)] >> 3)
+		lpower += v * v
+	}
+	lpower <<= 1
+
+	if lmax <= 0 {
+		return Nc, 0
+	}
+	if lmax >= lpower {
+		return Nc, 3
+	}
+	temp = norm(lpower)
+	R := int16((lmax << temp) >> 16)
+	S := int16((lpower << temp) >> 16)
+	for bc = 0; bc <= 2; bc++ {
+		if R <= mult(S, tabDLB[bc]) {
+			break
+		}
+	}
+	return Nc, bc
+}
+
+// ltpFiltering computes the long term residual e and returns the estimated signal (section 4.2.12).
+// The dp contains 120 samples of history, followed by the current subframe.
+func ltpFiltering(bc, Nc int16, dp, d, e []int16) [40]int16 {
+	var dpp [40]int16
+	bp := tabQLB[bc]
+	for k := range 40 {
+		dpp[k] = multR(bp, dp[120+k-int(Nc)])
+		e[k] = sub(d[k], dpp[k])
+	}
+	return dpp
+}
+
+// rpeEncoding implements section 4.2.13 - 4.2.18.
+// The e contains the long term residual in e[5:45]. It is replaced with the quantized residual.
+func rpeEncoding(e *[50]int16, xMc *[13]int16) (xmaxc, Mc int16) {
+	var (
+		x   [40]int16
+		xM  [13]int16
+		xMp [13]int16
+	)
+	weightingFilter(e, &x)
+	Mc = rpeGridSelection(&x, &xM)
+	xmaxc, exp, mant := apcmQuantization(&xM, xMc)
+	apcmInverseQuantization(xMc, mant, exp, &xMp)
+	var ep [40]int16
+	rpeGridPositioning(Mc, &xMp, &ep)
+	copy(e[5:45], ep[:])
+	return xmaxc, Mc
+}
+
+func weightingFilter(e *[50]int16, x *[40]int16) {
+	for k := range x {
+		sum := int32(4096) // rounding
+		for i, h := range tabH {
+			sum += int32(e[k+i]) * int32(h)
+		}
+		x[k] = sat(sum >> 13)
+	}
+}
+
+func rpeGridSelection(x *[40]int16, xM *[13]int16) (Mc int16) {
+	var em int32
+	for m := range 4 {
+		var sum int32
+		for i := range 13 {
+			v := int32(x[m+3*i] >> 2)
+			sum += v * v
+		}
+		sum <<= 1
+		if m == 0 || sum > em {
+			Mc = int16(m)
+			em = sum
+		}
+	}
+	for i := range xM {
+		xM[i] = x[int(Mc)+3*i]
+	}
+	return Mc
+}
+
+func apcmQuantization(xM, xMc *[13]int16) (xmaxc, exp, mant int16) {
+	var xmax int16
+	for _, v := range xM {
+		if v := abs(v); v > xmax {
+			xmax = v
+		}
+	}
+
+	// quantizing and coding of xmax
+	temp := xmax >> 9
+	itest := false
+	for range 6 {
+		itest = itest || temp <= 0
+		temp >>= 1
+		if !itest {
+			exp++
+		}
+	}
+	xmaxc = add(xmax>>(exp+5), exp<<3)
+
+	// quantizing and coding of the RPE sequence
+	exp, mant = xmaxcToExpMant(xmaxc)
+	temp1 := 6 - exp // normalization by the exponent
+	temp2 := tabNRFAC[mant]
+	for i, v := range xM {
+		temp := v << temp1
+		temp = mult(temp, temp2)
+		xMc[i] = temp>>12 + 4 // make all values positive
+	}
+	return xmaxc, exp, mant
+}
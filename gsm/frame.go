@@ -0,0 +1,136 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gsm
+
+import "errors"
+
+const (
+	// FrameSamples is the number of PCM samples in a single GSM frame (20 ms).
+	FrameSamples = 160
+	// FrameSize is the size of a single encoded GSM frame in bytes, as defined by RFC 3551.
+	FrameSize = 33
+
+	// magic is a signature in the upper 4 bits of each encoded frame.
+	magic = 0xD
+
+	// numParams is the number of parameters in a frame.
+	numParams = 8 + 4*(4+13)
+)
+
+var errInvalidFrame = errors.New("gsm: invalid frame")
+
+// larBits is the number of bits for each of the coded Log-Area Ratios.
+var larBits = [8]uint{6, 6, 5, 5, 4, 4, 3, 3}
+
+// frame holds coded parameters of a single frame, using names from GSM 06.10.
+type frame struct {
+	LARc  [8]int16     // Log-Area Ratios
+	Nc    [4]int16     // LTP lag
+	bc    [4]int16     // LTP gain
+	Mc    [4]int16     // RPE grid position
+	xmaxc [4]int16     // block amplitude
+	xMc   [4][13]int16 // RPE pulses
+}
+
+// params returns frame parameters in the order used by the test sequences of GSM 06.10.
+func (f *frame) params() []int16 {
+	out := make([]int16, 0, numParams)
+	out = append(out, f.LARc[:]...)
+	for j := range 4 {
+		out = append(out, f.Nc[j], f.bc[j], f.Mc[j], f.xmaxc[j])
+		out = append(out, f.xMc[j][:]...)
+	}
+	return out
+}
+
+// setParams is the reverse of params.
+func (f *frame) setParams(p []int16) {
+	p = p[copy(f.LARc[:], p):]
+	for j := range 4 {
+		f.Nc[j], f.bc[j], f.Mc[j], f.xmaxc[j] = p[0], p[1], p[2], p[3]
+		p = p[4+copy(f.xMc[j][:], p[4:]):]
+	}
+}
+
+// pack writes the frame in RTP format (RFC 3551, section 4.5.8) to out, which must be at least FrameSize long.
+func (f *frame) pack(out []byte) {
+	w := bitWriter{buf: out[:FrameSize]}
+	clear(w.buf)
+	w.write(magic, 4)
+	for i, n := range larBits {
+		w.write(f.LARc[i], n)
+	}
+	for j := range 4 {
+		w.write(f.Nc[j], 7)
+		w.write(f.bc[j], 2)
+		w.write(f.Mc[j], 2)
+		w.write(f.xmaxc[j], 6)
+		for _, v := range f.xMc[j] {
+			w.write(v, 3)
+		}
+	}
+}
+
+// unpack reads the frame in RTP format.
+func (f *frame) unpack(data []byte) error {
+	if len(data) < FrameSize || data[0]>>4 != magic {
+		return errInvalidFrame
+	}
+	r := bitReader{buf: data[:FrameSize]}
+	r.read(4)
+	for i, n := range larBits {
+		f.LARc[i] = r.read(n)
+	}
+	for j := range 4 {
+		f.Nc[j] = r.read(7)
+		f.bc[j] = r.read(2)
+		f.Mc[j] = r.read(2)
+		f.xmaxc[j] = r.read(6)
+		for i := range f.xMc[j] {
+			f.xMc[j][i] = r.read(3)
+		}
+	}
+	return nil
+}
+
+// bitWriter writes bits MSB first.
+type bitWriter struct {
+	buf []byte
+	pos uint
+}
+
+func (w *bitWriter) write(v int16, n uint) {
+	for i := n; i > 0; i-- {
+		bit := byte(v>>(i-1)) & 1
+		w.buf[w.pos/8] |= bit << (7 - w.pos%8)
+		w.pos++
+	}
+}
+
+// bitReader reads bits MSB first.
+type bitReader struct {
+	buf []byte
+	pos uint
+}
+
+func (r *bitReader) read(n uint) int16 {
+	var v int16
+	for range n {
+		bit := r.buf[r.pos/8] >> (7 - r.pos%8) & 1
+		v = v<<1 | int16(bit)
+		r.pos++
+	}
+	return v
+}
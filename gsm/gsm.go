@@ -0,0 +1,171 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gsm implements GSM 06.10 full-rate speech codec (RPE-LTP), with RTP payload format defined in RFC 3551.
+package gsm
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	prtp "github.com/pion/rtp"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/rtp"
+)
+
+const (
+	SDPName    = "GSM/8000"
+	SampleRate = 8000
+	// FrameDur is the duration of a single GSM frame.
+	FrameDur = 20 * time.Millisecond
+)
+
+func init() {
+	media.RegisterCodec(rtp.NewAudioCodec(media.CodecInfo{
+		SDPName:     SDPName,
+		SampleRate:  SampleRate,
+		RTPDefType:  prtp.PayloadTypeGSM,
+		RTPIsStatic: true,
+		Priority:    -30,
+		FileExt:     "gsm",
+	}, Decode, Encode))
+}
+
+// Sample is one or more GSM frames, FrameSize bytes each.
+type Sample []byte
+
+func (s Sample) Size() int {
+	return len(s)
+}
+
+func (s Sample) CopyTo(dst []byte) (int, error) {
+	if len(dst) < len(s) {
+		return 0, io.ErrShortBuffer
+	}
+	n := copy(dst, s)
+	return n, nil
+}
+
+// Frames returns the number of complete frames in the sample.
+func (s Sample) Frames() int {
+	return len(s) / FrameSize
+}
+
+// Duration of the sample. It is used for RTP timestamps, since a packet may contain multiple frames.
+func (s Sample) Duration() time.Duration {
+	return time.Duration(s.Frames()) * FrameDur
+}
+
+type Writer = media.WriteCloser[Sample]
+
+type Decoder struct {
+	w   media.PCM16Writer
+	st  *decoderState
+	f   frame
+	buf media.PCM16Sample
+}
+
+func (d *Decoder) String() string {
+	return fmt.Sprintf("GSM(decode) -> %s", d.w)
+}
+
+func (d *Decoder) SampleRate() int {
+	return d.w.SampleRate()
+}
+
+func (d *Decoder) Close() error {
+	return d.w.Close()
+}
+
+func (d *Decoder) WriteSample(in Sample) error {
+	if len(in) == 0 || len(in)%FrameSize != 0 {
+		return fmt.Errorf("gsm: invalid sample size: %d", len(in))
+	}
+	n := in.Frames() * FrameSamples
+	if n > cap(d.buf) {
+		d.buf = make(media.PCM16Sample, n)
+	} else {
+		d.buf = d.buf[:n]
+	}
+	for i := range in.Frames() {
+		if err := d.f.unpack(in[i*FrameSize:]); err != nil {
+			return err
+		}
+		d.st.decode(d.buf[i*FrameSamples:], &d.f)
+	}
+	return d.w.WriteSample(d.buf)
+}
+
+// Decode creates a GSM decoder that writes PCM to w. A sample may contain multiple frames.
+func Decode(w media.PCM16Writer) Writer {
+	switch w.SampleRate() {
+	default:
+		w = media.ResampleWriter(w, SampleRate)
+	case SampleRate:
+	}
+	return &Decoder{w: w, st: newDecoderState()}
+}
+
+type Encoder struct {
+	w   Writer
+	st  encoderState
+	f   frame
+	pcm media.PCM16Sample
+	buf Sample
+}
+
+func (e *Encoder) String() string {
+	return fmt.Sprintf("GSM(encode) -> %s", e.w)
+}
+
+func (e *Encoder) SampleRate() int {
+	return e.w.SampleRate()
+}
+
+func (e *Encoder) Close() error {
+	return e.w.Close()
+}
+
+// WriteSample encodes all complete frames as a single sample. Remaining PCM is buffered.
+func (e *Encoder) WriteSample(in media.PCM16Sample) error {
+	e.pcm = append(e.pcm, in...)
+	frames := len(e.pcm) / FrameSamples
+	if frames == 0 {
+		return nil
+	}
+	n := frames * FrameSize
+	if n > cap(e.buf) {
+		e.buf = make(Sample, n)
+	} else {
+		e.buf = e.buf[:n]
+	}
+	for i := range frames {
+		e.st.encode(&e.f, e.pcm[i*FrameSamples:])
+		e.f.pack(e.buf[i*FrameSize:])
+	}
+	e.pcm = e.pcm[:copy(e.pcm, e.pcm[frames*FrameSamples:])]
+	return e.w.WriteSample(e.buf)
+}
+
+// Encode creates a GSM encoder that writes frames to w.
+func Encode(w Writer) media.PCM16Writer {
+	switch w.SampleRate() {
+	default:
+		panic("unsupported sample rate")
+	case SampleRate:
+	}
+	return &Encoder{w: w}
+}
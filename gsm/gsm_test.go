@@ -0,0 +1,224 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gsm
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/rtp"
+	"github.com/livekit/media-sdk/sdp"
+)
+
+func TestFrame(t *testing.T) {
+	var widths []uint
+	widths = append(widths, larBits[:]...)
+	for range 4 {
+		widths = append(widths, 7, 2, 2, 6)
+		for range 13 {
+			widths = append(widths, 3)
+		}
+	}
+	require.Len(t, widths, numParams)
+	var f frame
+	p := make([]int16, numParams)
+	for i, n := range widths {
+		p[i] = int16(i*37) & (1<<n - 1)
+	}
+	f.setParams(p)
+	require.Equal(t, p, f.params())
+
+	buf := make([]byte, FrameSize)
+	f.pack(buf)
+	require.EqualValues(t, magic, buf[0]>>4)
+
+	var f2 frame
+	require.NoError(t, f2.unpack(buf))
+	require.Equal(t, f, f2)
+
+	buf[0] = 0
+	require.Error(t, f2.unpack(buf))
+	require.Error(t, f2.unpack(buf[:FrameSize-1]))
+}
+
+func TestSilence(t *testing.T) {
+	var frames []Sample
+	enc := Encode(&sampleWriter{frames: &frames})
+	require.NoError(t, enc.WriteSample(make(media.PCM16Sample, 2*FrameSamples)))
+	require.Len(t, frames, 1)
+	sub := []byte{0x50, 0x00, 0x49, 0x24, 0x92, 0x49, 0x24}
+	exp := []byte{0xd8, 0x20, 0xa2, 0xe1, 0x5a}
+	for range 4 {
+		exp = append(exp, sub...)
+	}
+	require.Equal(t, Sample(append(exp, exp...)), frames[0])
+}
+
+type sampleWriter struct {
+	frames *[]Sample
+}
+
+func (w *sampleWriter) String() string  { return "gsm" }
+func (w *sampleWriter) SampleRate() int { return SampleRate }
+func (w *sampleWriter) Close() error    { return nil }
+
+func (w *sampleWriter) WriteSample(s Sample) error {
+	*w.frames = append(*w.frames, slices.Clone(s))
+	return nil
+}
+
+// TestConformance runs test sequences in the format of GSM 06.10 (ETSI EN 300 961) from testdata.
+//
+// Each sequence consists of: NAME.inp - input PCM, NAME.cod - encoded parameters, NAME.out - decoded PCM.
+// All files use 16 bit little-endian words, with 76 parameters for each frame in .cod files.
+// See testdata/README.md for the origin of the sequences.
+func TestConformance(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.inp")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, path := range files {
+		name := strings.TrimSuffix(path, ".inp")
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			inp := readWords(t, name+".inp")
+			cod := readWords(t, name+".cod")
+			out := readWords(t, name+".out")
+			frames := len(inp) / FrameSamples
+			require.Equal(t, frames*numParams, len(cod))
+			require.Equal(t, frames*FrameSamples, len(out))
+
+			var (
+				enc encoderState
+				dec = newDecoderState()
+				f   frame
+				pcm = make([]int16, FrameSamples)
+			)
+			for i := range frames {
+				enc.encode(&f, inp[i*FrameSamples:])
+				require.Equal(t, cod[i*numParams:(i+1)*numParams], f.params(), "frame %d", i)
+
+				f.setParams(cod[i*numParams:])
+				dec.decode(pcm, &f)
+				require.Equal(t, out[i*FrameSamples:(i+1)*FrameSamples], pcm, "frame %d", i)
+			}
+		})
+	}
+}
+
+func readWords(t testing.TB, path string) []int16 {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	out := make([]int16, len(data)/2)
+	for i := range out {
+		out[i] = int16(binary.LittleEndian.Uint16(data[2*i:]))
+	}
+	return out
+}
+
+func speech(n int) media.PCM16Sample {
+	out := make(media.PCM16Sample, n)
+	for i := range out {
+		x := float64(i) / SampleRate
+		// vowel-like signal: harmonics of a varying pitch with an amplitude envelope
+		f0 := 120 + 30*math.Sin(2*math.Pi*2*x)
+		env := 0.5 + 0.5*math.Sin(2*math.Pi*3*x)
+		var v float64
+		for h := 1; h <= 8; h++ {
+			v += math.Sin(2*math.Pi*f0*float64(h)*x) / float64(h)
+		}
+		out[i] = int16(6000 * env * v)
+	}
+	return out
+}
+
+func snr(ref, got media.PCM16Sample) float64 {
+	var sig, noise float64
+	for i := range ref {
+		d := float64(ref[i]) - float64(got[i])
+		sig += float64(ref[i]) * float64(ref[i])
+		noise += d * d
+	}
+	return 10 * math.Log10(sig/noise)
+}
+
+func TestCodec(t *testing.T) {
+	const frames = 50
+	src := speech(frames * FrameSamples)
+
+	var buf rtp.Buffer
+	c := sdp.CodecByName(SDPName).(rtp.AudioCodec)
+	enc := c.EncodeRTP(rtp.NewSeqWriter(&buf).NewStream(3, SampleRate))
+	// input frames don't match the frame size
+	for i := 0; i < len(src); i += 240 {
+		require.NoError(t, enc.WriteSample(src[i:min(i+240, len(src))]))
+	}
+	require.Less(t, len(buf), frames) // some packets contain two frames
+	var total int
+	for _, p := range buf {
+		require.Zero(t, len(p.Payload)%FrameSize)
+		// timestamps advance by the number of frames in previous packets
+		require.Equal(t, uint32(total*FrameSamples), p.Timestamp-buf[0].Timestamp)
+		total += len(p.Payload) / FrameSize
+	}
+	require.Equal(t, frames, total)
+
+	w := &pcmWriter{}
+	dec := Decode(w)
+	for _, p := range buf {
+		require.NoError(t, dec.WriteSample(p.Payload))
+	}
+	got := w.pcm
+	require.Len(t, got, len(src))
+	// skip the first frames while the filters converge
+	require.Greater(t, snr(src[10*FrameSamples:], got[10*FrameSamples:]), 10.0)
+
+	require.Error(t, dec.WriteSample(make(Sample, FrameSize)))
+	require.Error(t, dec.WriteSample(make(Sample, FrameSize+1)))
+}
+
+type pcmWriter struct {
+	pcm media.PCM16Sample
+}
+
+func (w *pcmWriter) String() string  { return "pcm" }
+func (w *pcmWriter) SampleRate() int { return SampleRate }
+func (w *pcmWriter) Close() error    { return nil }
+
+func (w *pcmWriter) WriteSample(s media.PCM16Sample) error {
+	w.pcm = append(w.pcm, s...)
+	return nil
+}
+
+func TestOffer(t *testing.T) {
+	c := rtp.CodecByPayloadType(3)
+	require.NotNil(t, c)
+	require.Equal(t, SDPName, c.Info().SDPName)
+
+	var found bool
+	for _, oc := range sdp.OfferCodecs() {
+		if oc.Codec.Info().SDPName == SDPName {
+			found = true
+			require.EqualValues(t, 3, oc.Type)
+		}
+	}
+	require.True(t, found)
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gsm
+
+import (
+	"math"
+	"math/bits"
+)
+
+// Fixed point arithmetic, as defined in section 5.1 of GSM 06.10.
+// All operations must be bit-exact to pass the conformance tests.
+
+const (
+	minWord = math.MinInt16
+	maxWord = math.MaxInt16
+)
+
+// Tables from section 5.2 of GSM 06.10.
+var (
+	tabA     = [8]int16{20480, 20480, 20480, 20480, 13964, 15360, 8534, 9036}
+	tabB     = [8]int16{0, 0, 2048, -2560, 94, -1792, -341, -1144}
+	tabMIC   = [8]int16{-32, -32, -16, -16, -8, -8, -4, -4}
+	tabMAC   = [8]int16{31, 31, 15, 15, 7, 7, 3, 3}
+	tabINVA  = [8]int16{13107, 13107, 13107, 13107, 19223, 17476, 31454, 29708}
+	tabDLB   = [4]int16{6554, 16384, 26214, 32767}
+	tabQLB   = [4]int16{3277, 11469, 21299, 32767}
+	tabH     = [11]int16{-134, -374, 0, 2054, 5741, 8192, 5741, 2054, 0, -374, -134}
+	tabNRFAC = [8]int16{29128, 26215, 23832, 21846, 20165, 18725, 17476, 16384}
+	tabFAC   = [8]int16{18431, 20479, 22527, 24575, 26623, 28671, 30719, 32767}
+)
+
+func sat(v int32) int16 {
+	if v < minWord {
+		return minWord
+	}
+	if v > maxWord {
+		return maxWord
+	}
+	return int16(v)
+}
+
+func add(a, b int16) int16 {
+	return sat(int32(a) + int32(b))
+}
+
+func sub(a, b int16) int16 {
+	return sat(int32(a) - int32(b))
+}
+
+func mult(a, b int16) int16 {
+	if a == minWord && b == minWord {
+		return maxWord
+	}
+	return int16((int32(a) * int32(b)) >> 15)
+}
+
+func multR(a, b int16) int16 {
+	if a == minWord && b == minWord {
+		return maxWord
+	}
+	return int16((int32(a)*int32(b) + 16384) >> 15)
+}
+
+func abs(a int16) int16 {
+	if a >= 0 {
+		return a
+	}
+	if a == minWord {
+		return maxWord
+	}
+	return -a
+}
+
+func ladd(a, b int32) int32 {
+	v := int64(a) + int64(b)
+	if v < math.MinInt32 {
+		return math.MinInt32
+	}
+	if v > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int32(v)
+}
+
+// norm returns the number of left shifts needed to normalize a.
+func norm(a int32) int16 {
+	if a < 0 {
+		if a <= -1073741824 {
+			return 0
+		}
+		a = ^a
+	}
+	return int16(bits.LeadingZeros32(uint32(a)) - 1)
+}
+
+// div computes num/denum in Q15. It requires 0 <= num <= denum.
+func div(num, denum int16) int16 {
+	if num == 0 {
+		return 0
+	}
+	lnum, ldenum := int32(num), int32(denum)
+	var v int16
+	for range 15 {
+		v <<= 1
+		lnum <<= 1
+		if lnum >= ldenum {
+			lnum -= ldenum
+			v++
+		}
+	}
+	return v
+}
+
+func asl(a int16, n int16) int16 {
+	switch {
+	case n >= 16:
+		return 0
+	case n <= -16:
+		return a >> 15
+	case n < 0:
+		return asr(a, -n)
+	}
+	return a << n
+}
+
+func asr(a int16, n int16) int16 {
+	switch {
+	case n >= 16:
+		return a >> 15
+	case n <= -16:
+		return 0
+	case n < 0:
+		return a << -n
+	}
+	return a >> n
+}
@@ -0,0 +1,250 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ilbc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/rtp"
+)
+
+/*
+#cgo pkg-config: libilbc
+#include <ilbc.h>
+*/
+import "C"
+
+var (
+	errCreate = errors.New("ilbc: cannot create codec state")
+	errInit   = errors.New("ilbc: cannot initialize codec state")
+)
+
+func init() {
+	media.RegisterCodec(newCodec(DefaultMode, 0))
+}
+
+func newCodec(local, remote Mode) *codec {
+	c := &codec{local: local, remote: remote}
+	c.AudioCodec = rtp.NewAudioCodec(media.CodecInfo{
+		SDPName:    SDPName,
+		SampleRate: SampleRate,
+		Priority:   -25,
+		FileExt:    "ilbc",
+	}, c.decode, c.encode)
+	return c
+}
+
+// codec is an iLBC RTP codec configured with the mode negotiated in SDP.
+type codec struct {
+	rtp.AudioCodec
+	local  Mode
+	remote Mode // zero if not negotiated yet
+}
+
+var _ rtp.FmtpCodec = (*codec)(nil)
+
+func (c *codec) mode() Mode {
+	if c.remote == 0 {
+		return c.local
+	}
+	return Negotiate(c.local, c.remote)
+}
+
+func (c *codec) Fmtp() map[string]string {
+	return c.mode().Fmtp()
+}
+
+func (c *codec) WithFmtp(remote map[string]string) rtp.AudioCodec {
+	return newCodec(c.local, ParseMode(remote))
+}
+
+func (c *codec) decode(w media.PCM16Writer) media.WriteCloser[Sample] {
+	return Decode(w, c.mode())
+}
+
+func (c *codec) encode(w media.WriteCloser[Sample]) media.PCM16Writer {
+	return Encode(w, c.mode())
+}
+
+type Writer = media.WriteCloser[Sample]
+
+// Decode creates an iLBC decoder with a given mode that writes PCM to w.
+// The mode must be negotiated, since it cannot be reliably detected from the size of the sample.
+func Decode(w media.PCM16Writer, mode Mode) Writer {
+	switch w.SampleRate() {
+	default:
+		w = media.ResampleWriter(w, SampleRate)
+	case SampleRate:
+	}
+	if mode != Mode20 && mode != Mode30 {
+		panic("unsupported mode")
+	}
+	return &Decoder{w: w, mode: mode}
+}
+
+type Decoder struct {
+	w    media.PCM16Writer
+	dec  *C.IlbcDecoderInstance
+	mode Mode
+	buf  media.PCM16Sample
+}
+
+func (d *Decoder) String() string {
+	return fmt.Sprintf("iLBC(decode,%d) -> %s", d.mode, d.w)
+}
+
+func (d *Decoder) SampleRate() int {
+	return d.w.SampleRate()
+}
+
+func (d *Decoder) init() error {
+	if C.WebRtcIlbcfix_DecoderCreate(&d.dec) != 0 {
+		d.dec = nil
+		return errCreate
+	}
+	if C.WebRtcIlbcfix_DecoderInit(d.dec, C.int16_t(d.mode)) != 0 {
+		C.WebRtcIlbcfix_DecoderFree(d.dec)
+		d.dec = nil
+		return errInit
+	}
+	return nil
+}
+
+func (d *Decoder) WriteSample(in Sample) error {
+	size, samples := d.mode.FrameSize(), d.mode.FrameSamples()
+	if len(in) == 0 || len(in)%size != 0 {
+		return fmt.Errorf("ilbc: invalid sample size for %d ms mode: %d", d.mode, len(in))
+	}
+	if d.dec == nil {
+		if err := d.init(); err != nil {
+			return err
+		}
+	}
+	frames := len(in) / size
+	n := frames * samples
+	if n > cap(d.buf) {
+		d.buf = make(media.PCM16Sample, n)
+	} else {
+		d.buf = d.buf[:n]
+	}
+	for i := range frames {
+		var speechType C.int16_t
+		res := C.WebRtcIlbcfix_Decode(
+			d.dec,
+			(*C.uint8_t)(&in[i*size]),
+			C.size_t(size),
+			(*C.int16_t)(&d.buf[i*samples]),
+			&speechType,
+		)
+		if res < 0 {
+			return fmt.Errorf("ilbc: cannot decode frame")
+		}
+	}
+	return d.w.WriteSample(d.buf)
+}
+
+func (d *Decoder) Close() error {
+	if d.dec != nil {
+		C.WebRtcIlbcfix_DecoderFree(d.dec)
+		d.dec = nil
+	}
+	return d.w.Close()
+}
+
+// Encode creates an iLBC encoder with a given mode that writes frames to w.
+func Encode(w Writer, mode Mode) media.PCM16Writer {
+	switch w.SampleRate() {
+	default:
+		panic("unsupported sample rate")
+	case SampleRate:
+	}
+	if mode != Mode20 && mode != Mode30 {
+		panic("unsupported mode")
+	}
+	return &Encoder{w: w, mode: mode}
+}
+
+type Encoder struct {
+	w    Writer
+	enc  *C.IlbcEncoderInstance
+	mode Mode
+	pcm  media.PCM16Sample
+	buf  Sample
+}
+
+func (e *Encoder) String() string {
+	return fmt.Sprintf("iLBC(encode,%d) -> %s", e.mode, e.w)
+}
+
+func (e *Encoder) SampleRate() int {
+	return e.w.SampleRate()
+}
+
+func (e *Encoder) init() error {
+	if C.WebRtcIlbcfix_EncoderCreate(&e.enc) != 0 {
+		e.enc = nil
+		return errCreate
+	}
+	if C.WebRtcIlbcfix_EncoderInit(e.enc, C.int16_t(e.mode)) != 0 {
+		C.WebRtcIlbcfix_EncoderFree(e.enc)
+		e.enc = nil
+		return errInit
+	}
+	return nil
+}
+
+// WriteSample encodes all complete frames as a single sample. Remaining PCM is buffered.
+func (e *Encoder) WriteSample(in media.PCM16Sample) error {
+	if e.enc == nil {
+		if err := e.init(); err != nil {
+			return err
+		}
+	}
+	e.pcm = append(e.pcm, in...)
+	size, samples := e.mode.FrameSize(), e.mode.FrameSamples()
+	frames := len(e.pcm) / samples
+	if frames == 0 {
+		return nil
+	}
+	n := frames * size
+	if n > cap(e.buf) {
+		e.buf = make(Sample, n)
+	} else {
+		e.buf = e.buf[:n]
+	}
+	for i := range frames {
+		res := C.WebRtcIlbcfix_Encode(
+			e.enc,
+			(*C.int16_t)(&e.pcm[i*samples]),
+			C.size_t(samples),
+			(*C.uint8_t)(&e.buf[i*size]),
+		)
+		if int(res) != size {
+			return fmt.Errorf("ilbc: cannot encode frame")
+		}
+	}
+	e.pcm = e.pcm[:copy(e.pcm, e.pcm[frames*samples:])]
+	return e.w.WriteSample(e.buf)
+}
+
+func (e *Encoder) Close() error {
+	if e.enc != nil {
+		C.WebRtcIlbcfix_EncoderFree(e.enc)
+		e.enc = nil
+	}
+	return e.w.Close()
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build cgo
+
+package ilbc
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	psdp "github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/rtp"
+	"github.com/livekit/media-sdk/sdp"
+)
+
+type sampleWriter struct {
+	samples []Sample
+}
+
+func (w *sampleWriter) String() string  { return "ilbc" }
+func (w *sampleWriter) SampleRate() int { return SampleRate }
+func (w *sampleWriter) Close() error    { return nil }
+
+func (w *sampleWriter) WriteSample(s Sample) error {
+	w.samples = append(w.samples, slices.Clone(s))
+	return nil
+}
+
+type pcmWriter struct {
+	pcm media.PCM16Sample
+}
+
+func (w *pcmWriter) String() string  { return "pcm" }
+func (w *pcmWriter) SampleRate() int { return SampleRate }
+func (w *pcmWriter) Close() error    { return nil }
+
+func (w *pcmWriter) WriteSample(s media.PCM16Sample) error {
+	w.pcm = append(w.pcm, s...)
+	return nil
+}
+
+func sine(n int) media.PCM16Sample {
+	out := make(media.PCM16Sample, n)
+	for i := range out {
+		x := float64(i) / SampleRate
+		out[i] = int16(6000*math.Sin(2*math.Pi*300*x) + 3000*math.Sin(2*math.Pi*900*x))
+	}
+	return out
+}
+
+func rms(pcm media.PCM16Sample) float64 {
+	var sum float64
+	for _, v := range pcm {
+		sum += float64(v) * float64(v)
+	}
+	return math.Sqrt(sum / float64(len(pcm)))
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, mode := range []Mode{Mode20, Mode30} {
+		t.Run(mode.Duration().String(), func(t *testing.T) {
+			src := sine(SampleRate) // 1 second
+			w := &sampleWriter{}
+			enc := Encode(w, mode)
+			// input frames don't match the frame size in 30 ms mode
+			for i := 0; i < len(src); i += 160 {
+				require.NoError(t, enc.WriteSample(src[i:i+160]))
+			}
+			var n int
+			for _, s := range w.samples {
+				require.Equal(t, mode, s.Mode())
+				n += len(s) / mode.FrameSize()
+			}
+			require.Equal(t, len(src)/mode.FrameSamples(), n)
+
+			out := &pcmWriter{}
+			dec := Decode(out, mode)
+			for _, s := range w.samples {
+				require.NoError(t, dec.WriteSample(s))
+			}
+			require.Len(t, out.pcm, n*mode.FrameSamples())
+			require.Error(t, dec.WriteSample(make(Sample, 40)))
+			// frames of the other mode are rejected
+			other := Mode20
+			if mode == Mode20 {
+				other = Mode30
+			}
+			require.Error(t, dec.WriteSample(make(Sample, other.FrameSize())))
+			require.NoError(t, enc.Close())
+			require.NoError(t, dec.Close())
+
+			// the codec is not waveform-exact, so only compare the signal level
+			require.InDelta(t, rms(src[len(src)/2:]), rms(out.pcm[len(src)/2:]), rms(src)*0.3)
+		})
+	}
+}
+
+// TestReference compares the decoder output with reference vectors, if they are present in testdata.
+//
+// For each mode, testdata/ref_<mode>.ilbc contains encoded frames and testdata/ref_<mode>.s16le the decoded PCM,
+// produced by the reference implementation of RFC 3951. The vectors are not redistributed with the package.
+func TestReference(t *testing.T) {
+	for _, mode := range []Mode{Mode20, Mode30} {
+		t.Run(mode.Duration().String(), func(t *testing.T) {
+			path := "testdata/ref_" + mode.Duration().String()
+			data, err := os.ReadFile(path + ".ilbc")
+			if os.IsNotExist(err) {
+				t.Skip("reference vectors not found in testdata")
+			}
+			require.NoError(t, err)
+			ref, err := os.ReadFile(path + ".s16le")
+			require.NoError(t, err)
+
+			out := &pcmWriter{}
+			dec := Decode(out, mode)
+			for i := 0; i+mode.FrameSize() <= len(data); i += mode.FrameSize() {
+				require.NoError(t, dec.WriteSample(data[i:i+mode.FrameSize()]))
+			}
+			exp := make(media.PCM16Sample, len(ref)/2)
+			for i := range exp {
+				exp[i] = int16(binary.LittleEndian.Uint16(ref[2*i:]))
+			}
+			require.Equal(t, exp, out.pcm)
+		})
+	}
+}
+
+func TestSDP(t *testing.T) {
+	_, offer, err := sdp.OfferMedia(12345, sdp.EncryptionNone)
+	require.NoError(t, err)
+	var pt string
+	for _, a := range offer.Attributes {
+		if a.Key == "rtpmap" && strings.HasSuffix(a.Value, " iLBC/8000") {
+			pt, _, _ = strings.Cut(a.Value, " ")
+		}
+	}
+	require.NotEmpty(t, pt)
+	require.Contains(t, offer.Attributes, psdp.Attribute{Key: "fmtp", Value: pt + " mode=20"})
+
+	for _, c := range []struct {
+		name string
+		fmtp string
+		mode Mode
+	}{
+		{"default", "", Mode30},
+		{"mode 20", "mode=20", Mode20},
+		{"mode 30", "mode=30", Mode30},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			attrs := []psdp.Attribute{{Key: "rtpmap", Value: "97 iLBC/8000"}}
+			if c.fmtp != "" {
+				attrs = append(attrs, psdp.Attribute{Key: "fmtp", Value: "97 " + c.fmtp})
+			}
+			m, err := sdp.ParseMedia(&psdp.MediaDescription{
+				MediaName:  psdp.MediaName{Formats: []string{"97"}},
+				Attributes: attrs,
+			})
+			require.NoError(t, err)
+			audio, err := sdp.SelectAudio(*m, true)
+			require.NoError(t, err)
+			require.Equal(t, SDPName, audio.Codec.Info().SDPName)
+			require.Equal(t, c.mode, audio.Codec.(*codec).mode())
+
+			var buf rtp.Buffer
+			enc := audio.Codec.EncodeRTP(rtp.NewSeqWriter(&buf).NewStream(97, SampleRate))
+			for range 3 {
+				require.NoError(t, enc.WriteSample(make(media.PCM16Sample, 160)))
+			}
+			require.Len(t, buf, 480/c.mode.FrameSamples())
+			require.Len(t, buf[0].Payload, c.mode.FrameSize())
+			require.Equal(t, uint32(c.mode.FrameSamples()), buf[1].Timestamp-buf[0].Timestamp)
+		})
+	}
+}
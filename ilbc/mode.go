@@ -0,0 +1,121 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ilbc implements iLBC speech codec (RFC 3951), with RTP payload format defined in RFC 3952.
+package ilbc
+
+import (
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	SDPName    = "iLBC/8000"
+	SampleRate = 8000
+
+	// ParamMode is a format parameter which selects the frame duration.
+	ParamMode = "mode"
+)
+
+// Mode is a frame mode of iLBC, which is also the frame duration in milliseconds.
+type Mode int
+
+const (
+	Mode20 Mode = 20
+	Mode30 Mode = 30
+)
+
+// DefaultMode is the mode offered in SDP.
+const DefaultMode = Mode20
+
+func (m Mode) Duration() time.Duration {
+	return time.Duration(m) * time.Millisecond
+}
+
+// FrameSamples returns the number of PCM samples in a frame.
+func (m Mode) FrameSamples() int {
+	return int(m) * SampleRate / 1000
+}
+
+// FrameSize returns the size of an encoded frame in bytes.
+func (m Mode) FrameSize() int {
+	if m == Mode20 {
+		return 38
+	}
+	return 50
+}
+
+// ParseMode returns the mode from SDP format parameters.
+// As defined in RFC 3952, 30 ms mode is assumed if the parameter is missing or invalid.
+func ParseMode(fmtp map[string]string) Mode {
+	if v, err := strconv.Atoi(fmtp[ParamMode]); err == nil && Mode(v) == Mode20 {
+		return Mode20
+	}
+	return Mode30
+}
+
+// Fmtp returns SDP format parameters for the mode.
+func (m Mode) Fmtp() map[string]string {
+	return map[string]string{ParamMode: strconv.Itoa(int(m))}
+}
+
+// Negotiate returns the mode that both sides must use. If either side requests 30 ms mode, it is used.
+func Negotiate(local, remote Mode) Mode {
+	if local == Mode30 || remote == Mode30 {
+		return Mode30
+	}
+	return Mode20
+}
+
+// Sample is one or more iLBC frames of the same mode.
+type Sample []byte
+
+func (s Sample) Size() int {
+	return len(s)
+}
+
+func (s Sample) CopyTo(dst []byte) (int, error) {
+	if len(dst) < len(s) {
+		return 0, io.ErrShortBuffer
+	}
+	n := copy(dst, s)
+	return n, nil
+}
+
+// Mode detects the mode of the sample from its size. It returns zero if the size is invalid,
+// or if it is ambiguous: a multiple of both frame sizes. Decoders must use the negotiated mode instead.
+func (s Sample) Mode() Mode {
+	if len(s) == 0 {
+		return 0
+	}
+	is20 := len(s)%Mode20.FrameSize() == 0
+	is30 := len(s)%Mode30.FrameSize() == 0
+	switch {
+	case is20 && !is30:
+		return Mode20
+	case is30 && !is20:
+		return Mode30
+	}
+	return 0
+}
+
+// Duration returns the duration of audio in the sample.
+func (s Sample) Duration() time.Duration {
+	m := s.Mode()
+	if m == 0 {
+		return 0
+	}
+	return time.Duration(len(s)/m.FrameSize()) * m.Duration()
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ilbc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMode(t *testing.T) {
+	require.Equal(t, 160, Mode20.FrameSamples())
+	require.Equal(t, 240, Mode30.FrameSamples())
+	require.Equal(t, 38, Mode20.FrameSize())
+	require.Equal(t, 50, Mode30.FrameSize())
+
+	require.Equal(t, Mode30, ParseMode(nil))
+	require.Equal(t, Mode20, ParseMode(map[string]string{ParamMode: "20"}))
+	require.Equal(t, Mode30, ParseMode(map[string]string{ParamMode: "30"}))
+	require.Equal(t, Mode30, ParseMode(map[string]string{ParamMode: "10"}))
+	require.Equal(t, map[string]string{ParamMode: "20"}, Mode20.Fmtp())
+
+	require.Equal(t, Mode20, Negotiate(Mode20, Mode20))
+	require.Equal(t, Mode30, Negotiate(Mode20, Mode30))
+	require.Equal(t, Mode30, Negotiate(Mode30, Mode20))
+}
+
+func TestSampleDuration(t *testing.T) {
+	for _, c := range []struct {
+		size int
+		mode Mode
+		dur  time.Duration
+	}{
+		{0, 0, 0},
+		{10, 0, 0},
+		{38, Mode20, 20 * time.Millisecond},
+		{76, Mode20, 40 * time.Millisecond},
+		{50, Mode30, 30 * time.Millisecond},
+		{100, Mode30, 60 * time.Millisecond},
+		{1900, 0, 0}, // 50 frames in 20 ms mode or 38 frames in 30 ms mode
+	} {
+		s := make(Sample, c.size)
+		require.Equal(t, c.mode, s.Mode(), "size %d", c.size)
+		require.Equal(t, c.dur, s.Duration(), "size %d", c.size)
+	}
+}
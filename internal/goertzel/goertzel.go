@@ -0,0 +1,49 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package goertzel implements the Goertzel algorithm for detecting single frequency components in PCM audio.
+package goertzel
+
+import (
+	"math"
+
+	"github.com/livekit/media-sdk"
+)
+
+// Filter computes power of a single frequency component.
+type Filter struct {
+	coef float64
+}
+
+// New creates a filter for a given frequency.
+func New(freq float64, sampleRate int) Filter {
+	return Filter{coef: 2 * math.Cos(2*math.Pi*freq/float64(sampleRate))}
+}
+
+// Power returns the mean power of the frequency component in the window.
+// For a sine wave with amplitude A it returns A^2/2, the same as mean power of the wave itself.
+func (f Filter) Power(w media.PCM16Sample) float64 {
+	var s1, s2 float64
+	for _, v := range w {
+		s0 := float64(v) + f.coef*s1 - s2
+		s2, s1 = s1, s0
+	}
+	n := float64(len(w))
+	return (s1*s1 + s2*s2 - f.coef*s1*s2) * 2 / (n * n)
+}
+
+// Power returns the mean power of a given frequency in the window. See Filter.Power.
+func Power(w media.PCM16Sample, freq float64, sampleRate int) float64 {
+	return New(freq, sampleRate).Power(w)
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jitter
+
+import (
+	"time"
+)
+
+const (
+	// adaptiveJitterMul is a multiplier applied to the jitter estimate to get the target latency.
+	adaptiveJitterMul = 4
+	// adaptiveShrinkRate controls how fast the latency decreases when jitter goes down.
+	// Latency grows immediately, but shrinks by 1/adaptiveShrinkRate of the difference per packet.
+	adaptiveShrinkRate = 64
+	// adaptiveMaxGap is a max difference between RTP and arrival time deltas that is considered jitter.
+	// Larger gaps are treated as stream discontinuity and reset the estimator.
+	adaptiveMaxGap = 3 * time.Second
+)
+
+// AdaptiveConfig configures adaptive playout delay for the Buffer.
+type AdaptiveConfig struct {
+	// ClockRate is an RTP clock rate of the stream.
+	ClockRate int
+	// MinLatency is a lower bound for the target latency.
+	MinLatency time.Duration
+	// MaxLatency is an upper bound for the target latency.
+	MaxLatency time.Duration
+}
+
+// WithAdaptiveLatency enables adaptive playout delay.
+//
+// The buffer estimates interarrival jitter from RTP timestamps and packet arrival time (RFC 3550, A.8),
+// and adjusts the target latency within configured bounds. Latency passed to NewBuffer is used as initial value.
+func WithAdaptiveLatency(conf AdaptiveConfig) Option {
+	return func(b *Buffer) {
+		if conf.ClockRate <= 0 {
+			return
+		}
+		if conf.MaxLatency < conf.MinLatency {
+			conf.MaxLatency = conf.MinLatency
+		}
+		b.adaptive = &adaptiveLatency{conf: conf}
+		b.latency = b.adaptive.clamp(b.latency)
+	}
+}
+
+type adaptiveLatency struct {
+	conf AdaptiveConfig
+
+	init       bool
+	lastTS     uint32
+	lastArrive time.Time
+	jitter     float64 // in seconds
+}
+
+func (a *adaptiveLatency) clamp(v time.Duration) time.Duration {
+	if v < a.conf.MinLatency {
+		v = a.conf.MinLatency
+	}
+	if v > a.conf.MaxLatency {
+		v = a.conf.MaxLatency
+	}
+	return v
+}
+
+// estimate returns current interarrival jitter estimate.
+func (a *adaptiveLatency) estimate() time.Duration {
+	return time.Duration(a.jitter * float64(time.Second))
+}
+
+// update the jitter estimate with a packet timestamp and its arrival time and returns the new target latency.
+func (a *adaptiveLatency) update(cur time.Duration, ts uint32, arrival time.Time) time.Duration {
+	if !a.init {
+		a.init = true
+		a.lastTS, a.lastArrive = ts, arrival
+		return cur
+	}
+	dts := time.Duration(int32(ts-a.lastTS)) * time.Second / time.Duration(a.conf.ClockRate)
+	darr := arrival.Sub(a.lastArrive)
+	if ts == a.lastTS {
+		// Multiple packets of the same sample. Only the first one is used.
+		return cur
+	}
+	a.lastTS, a.lastArrive = ts, arrival
+	d := darr - dts
+	if d < 0 {
+		d = -d
+	}
+	if d > adaptiveMaxGap {
+		// discontinuity in the stream
+		return cur
+	}
+	a.jitter += (d.Seconds() - a.jitter) / 16
+
+	target := a.clamp(time.Duration(adaptiveJitterMul * a.jitter * float64(time.Second)))
+	if target >= cur {
+		return target
+	}
+	return cur - (cur-target)/adaptiveShrinkRate
+}
@@ -16,6 +16,7 @@ package jitter
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/frostbyte73/core"
@@ -36,6 +37,7 @@ type Buffer struct {
 	logger       logger.Logger
 	onPacket     PacketFunc
 	onPacketLoss func()
+	adaptive     *adaptiveLatency
 
 	mu     sync.Mutex
 	closed core.Fuse
@@ -46,6 +48,7 @@ type Buffer struct {
 	tail        *packet
 
 	stats *BufferStats
+	lost  atomic.Uint64 // same as stats.PacketsLost, but can be read from callbacks
 	timer *time.Timer
 
 	pool *packet
@@ -61,6 +64,9 @@ type BufferStats struct {
 	PacketsDropped uint64 // packets dropped (incomplete)
 	PacketsPopped  uint64 // packets sent to handler
 	SamplesPopped  uint64 // samples sent to handler
+
+	TargetLatency  time.Duration // current target latency
+	JitterEstimate time.Duration // interarrival jitter estimate (adaptive mode only)
 }
 
 type PacketFunc func(packets []ExtPacket)
@@ -76,12 +82,12 @@ func NewBuffer(
 		latency:      latency,
 		logger:       logger.LogRLogger(logr.Discard()),
 		stats:        &BufferStats{},
-		timer:        time.NewTimer(latency),
 		onPacket:     fnc,
 	}
 	for _, opt := range opts {
 		opt(b)
 	}
+	b.timer = time.NewTimer(b.latency)
 
 	go func() {
 		for {
@@ -105,6 +111,9 @@ func WithLogger(logger logger.Logger) Option {
 	}
 }
 
+// WithPacketLossHandler sets a handler called when packets are lost or dropped.
+// For gaps in the sequence, it is called before the packet following the gap, see PacketsLost.
+// The handler is called with the buffer locked, so it must not call methods of the buffer other than PacketsLost.
 func WithPacketLossHandler(handler func()) Option {
 	return func(b *Buffer) {
 		b.onPacketLoss = handler
@@ -116,10 +125,23 @@ func (b *Buffer) WithLogger(logger logger.Logger) *Buffer {
 	return b
 }
 
+// UpdateLatency sets the target latency of the buffer.
+// In adaptive mode, the value is clamped to configured bounds and will be adjusted further by the buffer.
 func (b *Buffer) UpdateLatency(latency time.Duration) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	if b.adaptive != nil {
+		latency = b.adaptive.clamp(latency)
+	}
+	b.setLatency(latency)
+}
+
+// setLatency updates the target latency and re-arms the timer for the head packet.
+func (b *Buffer) setLatency(latency time.Duration) {
+	if latency == b.latency {
+		return
+	}
 	b.latency = latency
 	if b.head != nil {
 		b.timer.Reset(time.Until(b.head.extPacket.ReceivedAt.Add(latency)))
@@ -149,14 +171,30 @@ func (b *Buffer) Stats() *BufferStats {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	return &BufferStats{
+	st := &BufferStats{
 		PacketsPushed:  b.stats.PacketsPushed,
 		PaddingPushed:  b.stats.PaddingPushed,
 		PacketsLost:    b.stats.PacketsLost,
 		PacketsDropped: b.stats.PacketsDropped,
 		PacketsPopped:  b.stats.PacketsPopped,
 		SamplesPopped:  b.stats.SamplesPopped,
+		TargetLatency:  b.latency,
+	}
+	if b.adaptive != nil {
+		st.JitterEstimate = b.adaptive.estimate()
 	}
+	return st
+}
+
+// PacketsLost returns the number of lost packets, same as BufferStats.PacketsLost.
+// Unlike Stats, it is safe to call from the buffer callbacks.
+func (b *Buffer) PacketsLost() uint64 {
+	return b.lost.Load()
+}
+
+func (b *Buffer) addLost(n uint16) {
+	b.stats.PacketsLost += uint64(n)
+	b.lost.Store(b.stats.PacketsLost)
 }
 
 func (s *BufferStats) PacketLoss() float64 {
@@ -174,6 +212,7 @@ func (b *Buffer) Close() {
 
 // push adds a packet to the buffer
 func (b *Buffer) push(pkt *rtp.Packet) {
+	receivedAt := time.Now()
 	b.stats.PacketsPushed++
 	if pkt.Padding {
 		b.stats.PaddingPushed++
@@ -182,6 +221,11 @@ func (b *Buffer) push(pkt *rtp.Packet) {
 		}
 	}
 
+	if b.adaptive != nil && !pkt.Padding {
+		// late packets must be accounted as well, so that latency can grow
+		b.setLatency(b.adaptive.update(b.latency, pkt.Timestamp, receivedAt))
+	}
+
 	if b.initialized && before(pkt.SequenceNumber, b.prevSN) {
 		// packet expired
 		if !pkt.Padding {
@@ -193,7 +237,7 @@ func (b *Buffer) push(pkt *rtp.Packet) {
 		return
 	}
 
-	p := b.newPacket(pkt)
+	p := b.newPacket(pkt, receivedAt)
 
 	discont := !b.initialized || !withinRange(pkt.SequenceNumber, b.prevSN)
 
@@ -267,7 +311,6 @@ func (b *Buffer) popReady() {
 
 	b.dropIncompleteExpired(expiry)
 
-	loss := false
 	for b.head != nil &&
 		b.head.isComplete() {
 
@@ -275,8 +318,11 @@ func (b *Buffer) popReady() {
 			// normal
 		} else if b.head.extPacket.ReceivedAt.Before(expiry) {
 			// max latency reached
-			loss = true
-			b.stats.PacketsLost += uint64(b.head.extPacket.SequenceNumber - b.prevSN - 1)
+			b.addLost(b.head.extPacket.SequenceNumber - b.prevSN - 1)
+			// notify before the packet following the gap
+			if b.onPacketLoss != nil {
+				b.onPacketLoss()
+			}
 		} else {
 			break
 		}
@@ -286,10 +332,6 @@ func (b *Buffer) popReady() {
 		}
 	}
 
-	if loss && b.onPacketLoss != nil {
-		b.onPacketLoss()
-	}
-
 	if b.head != nil {
 		b.timer.Reset(time.Until(b.head.extPacket.ReceivedAt.Add(b.latency)))
 	}
@@ -301,7 +343,7 @@ func (b *Buffer) dropIncompleteExpired(expiry time.Time) {
 
 	for b.head != nil && !b.head.isComplete() && b.head.extPacket.ReceivedAt.Before(expiry) {
 		if b.initialized && !b.head.discont {
-			b.stats.PacketsLost += uint64(b.head.extPacket.SequenceNumber - b.prevSN - 1)
+			b.addLost(b.head.extPacket.SequenceNumber - b.prevSN - 1)
 		}
 
 		b.free(b.popHead())
@@ -310,3 +310,99 @@ func (d *testDepacketizer) IsPartitionHead(payload []byte) bool {
 func (d *testDepacketizer) IsPartitionTail(marker bool, _ []byte) bool {
 	return marker
 }
+
+func TestAdaptiveLatency(t *testing.T) {
+	const (
+		clockRate = 8000
+		frameDur  = 20 * time.Millisecond
+		frameTS   = clockRate / 50
+	)
+	a := &adaptiveLatency{conf: AdaptiveConfig{
+		ClockRate:  clockRate,
+		MinLatency: 20 * time.Millisecond,
+		MaxLatency: 200 * time.Millisecond,
+	}}
+	latency := a.clamp(60 * time.Millisecond)
+	now := time.Now()
+	ts := uint32(rand.Uint32())
+
+	// no jitter - latency slowly decreases to the minimum
+	for range 1000 {
+		latency = a.update(latency, ts, now)
+		ts += frameTS
+		now = now.Add(frameDur)
+	}
+	require.Equal(t, time.Duration(0), a.estimate())
+	require.InDelta(t, 20*time.Millisecond, latency, float64(time.Millisecond))
+
+	// packets arrive in bursts - latency grows
+	for i := range 200 {
+		var delay time.Duration
+		if i%2 == 0 {
+			delay = 40 * time.Millisecond
+		}
+		latency = a.update(latency, ts, now.Add(delay))
+		ts += frameTS
+		now = now.Add(frameDur)
+	}
+	require.InDelta(t, 40*time.Millisecond, a.estimate(), float64(time.Millisecond))
+	require.Equal(t, 160*time.Millisecond, latency.Round(time.Millisecond))
+
+	// large jitter - latency is capped
+	for i := range 200 {
+		var delay time.Duration
+		if i%2 == 0 {
+			delay = time.Second
+		}
+		latency = a.update(latency, ts, now.Add(delay))
+		ts += frameTS
+		now = now.Add(frameDur)
+	}
+	require.Equal(t, 200*time.Millisecond, latency)
+}
+
+func TestAdaptiveLatencyStats(t *testing.T) {
+	out := make(chan []ExtPacket, 100)
+	b := NewBuffer(&testDepacketizer{}, testBufferLatency, chanFunc(t, out), WithAdaptiveLatency(AdaptiveConfig{
+		ClockRate:  8000,
+		MinLatency: 20 * time.Millisecond,
+		MaxLatency: 100 * time.Millisecond,
+	}))
+	defer b.Close()
+
+	stats := b.Stats()
+	require.Equal(t, 100*time.Millisecond, stats.TargetLatency)
+
+	b.UpdateLatency(time.Millisecond)
+	stats = b.Stats()
+	require.Equal(t, 20*time.Millisecond, stats.TargetLatency)
+}
+
+func TestAdaptiveLatencyTimer(t *testing.T) {
+	out := make(chan []ExtPacket, 100)
+	b := NewBuffer(&testDepacketizer{}, testBufferLatency, chanFunc(t, out), WithAdaptiveLatency(AdaptiveConfig{
+		ClockRate:  8000,
+		MinLatency: 20 * time.Millisecond,
+		MaxLatency: testBufferLatency,
+	}))
+	defer b.Close()
+	s := newTestStream()
+
+	b.Push(s.gen(true, true))
+	checkSample(t, out, 1)
+
+	// packet loss
+	_ = s.gen(true, true)
+	b.Push(s.gen(true, true))
+	checkSample(t, out, 0)
+
+	// lower latency must re-arm the timer for the packet waiting in the buffer
+	b.UpdateLatency(0)
+	select {
+	case sample := <-out:
+		require.Len(t, sample, 1)
+		require.False(t, sample[0].ReceivedAt.IsZero())
+	case <-time.After(testBufferLatency / 2):
+		t.Fatal("timer was not re-armed")
+	}
+}
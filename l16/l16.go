@@ -0,0 +1,288 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package l16 implements uncompressed 16 bit linear PCM RTP payload (L16), as defined in RFC 3551.
+//
+// Codecs are registered for each supported sample rate, in mono and stereo.
+// They are disabled by default because of the high bandwidth, and can be enabled with media.CodecSetEnabled.
+//
+// A 20 ms frame exceeds the MTU at 44.1 kHz and 48 kHz, even in mono. The encoder splits such frames
+// into multiple RTP packets of at most MaxPayloadSize bytes, each with a shorter duration.
+package l16
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/rtp"
+)
+
+const (
+	// PayloadTypeStereo is a static payload type for L16/44100/2.
+	PayloadTypeStereo = 10
+	// PayloadTypeMono is a static payload type for L16/44100.
+	PayloadTypeMono = 11
+)
+
+// MaxPayloadSize is the max size of the payload of a single RTP packet.
+// It leaves room for RTP header, header extensions and SRTP authentication tag within rtp.MTUSize.
+const MaxPayloadSize = rtp.MTUSize - 100
+
+// SampleRates are sample rates of registered L16 codecs.
+var SampleRates = []int{8000, 16000, 44100, 48000}
+
+func init() {
+	for _, rate := range SampleRates {
+		for _, channels := range []int{1, 2} {
+			media.RegisterCodec(newCodec(rate, channels))
+		}
+	}
+}
+
+// SDPName returns an SDP name of L16 codec with a given sample rate and number of channels.
+func SDPName(sampleRate, channels int) string {
+	name := "L16/" + strconv.Itoa(sampleRate)
+	if channels > 1 {
+		name += "/" + strconv.Itoa(channels)
+	}
+	return name
+}
+
+func newCodec(sampleRate, channels int) rtp.AudioCodec {
+	info := media.CodecInfo{
+		SDPName:    SDPName(sampleRate, channels),
+		SampleRate: sampleRate,
+		Priority:   -1,
+		Disabled:   true,
+		FileExt:    "l16",
+	}
+	if sampleRate == 8000 {
+		// better than G.711, but narrowband
+		info.Priority = -8
+	}
+	if channels > 1 {
+		info.Priority--
+	}
+	if sampleRate == 44100 {
+		info.RTPIsStatic = true
+		info.RTPDefType = PayloadTypeMono
+		if channels == 2 {
+			info.RTPDefType = PayloadTypeStereo
+		}
+	}
+	return &codec{
+		AudioCodec: rtp.NewAudioCodec(info, func(w media.PCM16Writer) media.WriteCloser[Sample] {
+			return Decode(w, sampleRate, channels)
+		}, func(w media.WriteCloser[Sample]) media.PCM16Writer {
+			return Encode(w, channels)
+		}),
+		sampleRate: sampleRate,
+		channels:   channels,
+	}
+}
+
+type codec struct {
+	rtp.AudioCodec
+	sampleRate int
+	channels   int
+}
+
+// EncodeRTP creates an encoder that sends each packet with its own duration,
+// since frames might be split into multiple packets to fit the MTU.
+func (c *codec) EncodeRTP(w *rtp.Stream) media.PCM16Writer {
+	return Encode(&streamOut{s: w, sampleRate: c.sampleRate, channels: c.channels}, c.channels)
+}
+
+// streamOut writes L16 payloads to RTP, incrementing the timestamp by the number of samples in the payload.
+type streamOut struct {
+	s          *rtp.Stream
+	sampleRate int
+	channels   int
+}
+
+func (s *streamOut) String() string {
+	return fmt.Sprintf("RTP(%d)", s.sampleRate)
+}
+
+func (s *streamOut) SampleRate() int {
+	return s.sampleRate
+}
+
+func (s *streamOut) Close() error {
+	return nil
+}
+
+func (s *streamOut) WriteSample(sample Sample) error {
+	return s.s.WritePayloadWithDur(sample, false, uint32(len(sample)/(2*s.channels)))
+}
+
+// Sample is L16 audio: 16 bit signed samples in network byte order, interleaved if there are multiple channels.
+type Sample []byte
+
+func (s Sample) Size() int {
+	return len(s)
+}
+
+func (s Sample) CopyTo(dst []byte) (int, error) {
+	if len(dst) < len(s) {
+		return 0, io.ErrShortBuffer
+	}
+	n := copy(dst, s)
+	return n, nil
+}
+
+func (s Sample) Decode() media.PCM16Sample {
+	out := make(media.PCM16Sample, len(s)/2)
+	DecodeTo(out, s)
+	return out
+}
+
+func (s *Sample) Encode(data media.PCM16Sample) {
+	out := make(Sample, len(data)*2)
+	EncodeTo(out, data)
+	*s = out
+}
+
+// EncodeTo converts PCM to network byte order. Size of out must be at least twice the size of in.
+func EncodeTo(out []byte, in media.PCM16Sample) {
+	for i, v := range in {
+		binary.BigEndian.PutUint16(out[2*i:], uint16(v))
+	}
+}
+
+// DecodeTo converts samples in network byte order to PCM. A trailing odd byte is ignored.
+func DecodeTo(out media.PCM16Sample, in []byte) {
+	for i := range len(in) / 2 {
+		out[i] = int16(binary.BigEndian.Uint16(in[2*i:]))
+	}
+}
+
+// decodeMono converts stereo samples in network byte order to mono PCM.
+func decodeMono(out media.PCM16Sample, in []byte) {
+	for i := range len(in) / 4 {
+		l := int16(binary.BigEndian.Uint16(in[4*i:]))
+		r := int16(binary.BigEndian.Uint16(in[4*i+2:]))
+		out[i] = int16((int32(l) + int32(r)) / 2)
+	}
+}
+
+type Writer = media.WriteCloser[Sample]
+
+// Decode creates an L16 decoder that writes mono PCM to w. Stereo audio is mixed down.
+func Decode(w media.PCM16Writer, sampleRate, channels int) Writer {
+	if channels != 1 && channels != 2 {
+		panic("unsupported number of channels")
+	}
+	return &Decoder{
+		w:          media.ResampleWriter(w, sampleRate),
+		sampleRate: sampleRate,
+		channels:   channels,
+	}
+}
+
+type Decoder struct {
+	w          media.PCM16Writer
+	sampleRate int
+	channels   int
+	buf        media.PCM16Sample
+}
+
+func (d *Decoder) String() string {
+	return fmt.Sprintf("L16(decode,%d,%d) -> %s", d.sampleRate, d.channels, d.w)
+}
+
+func (d *Decoder) SampleRate() int {
+	return d.w.SampleRate()
+}
+
+func (d *Decoder) Close() error {
+	return d.w.Close()
+}
+
+func (d *Decoder) WriteSample(in Sample) error {
+	n := len(in) / (2 * d.channels)
+	if n > cap(d.buf) {
+		d.buf = make(media.PCM16Sample, n)
+	} else {
+		d.buf = d.buf[:n]
+	}
+	if d.channels == 2 {
+		decodeMono(d.buf, in)
+	} else {
+		DecodeTo(d.buf, in)
+	}
+	return d.w.WriteSample(d.buf)
+}
+
+// Encode creates an L16 encoder that accepts mono PCM at the sample rate of w.
+// Mono audio is duplicated to both channels for stereo.
+// Samples larger than MaxPayloadSize are split into multiple samples of equal duration.
+func Encode(w Writer, channels int) media.PCM16Writer {
+	if channels != 1 && channels != 2 {
+		panic("unsupported number of channels")
+	}
+	return &Encoder{w: w, channels: channels}
+}
+
+type Encoder struct {
+	w        Writer
+	channels int
+	buf      Sample
+}
+
+func (e *Encoder) String() string {
+	return fmt.Sprintf("L16(encode,%d,%d) -> %s", e.w.SampleRate(), e.channels, e.w)
+}
+
+func (e *Encoder) SampleRate() int {
+	return e.w.SampleRate()
+}
+
+func (e *Encoder) Close() error {
+	return e.w.Close()
+}
+
+func (e *Encoder) WriteSample(in media.PCM16Sample) error {
+	n := len(in) * 2 * e.channels
+	if n > cap(e.buf) {
+		e.buf = make(Sample, n)
+	} else {
+		e.buf = e.buf[:n]
+	}
+	if e.channels == 2 {
+		for i, v := range in {
+			binary.BigEndian.PutUint16(e.buf[4*i:], uint16(v))
+			binary.BigEndian.PutUint16(e.buf[4*i+2:], uint16(v))
+		}
+	} else {
+		EncodeTo(e.buf, in)
+	}
+	if len(e.buf) <= MaxPayloadSize {
+		return e.w.WriteSample(e.buf)
+	}
+	frame := 2 * e.channels
+	packets := (len(e.buf) + MaxPayloadSize - 1) / MaxPayloadSize
+	size := (len(in) + packets - 1) / packets * frame
+	for data := e.buf; len(data) > 0; {
+		n := min(size, len(data))
+		if err := e.w.WriteSample(data[:n]); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package l16
+
+import (
+	"math"
+	"testing"
+
+	psdp "github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+	_ "github.com/livekit/media-sdk/g711"
+	"github.com/livekit/media-sdk/rtp"
+	"github.com/livekit/media-sdk/sdp"
+)
+
+func TestSample(t *testing.T) {
+	pcm := media.PCM16Sample{0, 1, -1, math.MaxInt16, math.MinInt16, 0x1234}
+	var s Sample
+	s.Encode(pcm)
+	require.Equal(t, Sample{
+		0x00, 0x00,
+		0x00, 0x01,
+		0xff, 0xff,
+		0x7f, 0xff,
+		0x80, 0x00,
+		0x12, 0x34,
+	}, s)
+	require.Equal(t, pcm, s.Decode())
+	require.Equal(t, pcm[:2], Sample{0, 0, 0, 1, 0xff}.Decode())
+}
+
+func TestCodecs(t *testing.T) {
+	require.Equal(t, "L16/44100", SDPName(44100, 1))
+	require.Equal(t, "L16/48000/2", SDPName(48000, 2))
+
+	mono := rtp.CodecByPayloadType(PayloadTypeMono)
+	require.NotNil(t, mono)
+	require.Equal(t, "L16/44100", mono.Info().SDPName)
+	stereo := rtp.CodecByPayloadType(PayloadTypeStereo)
+	require.NotNil(t, stereo)
+	require.Equal(t, "L16/44100/2", stereo.Info().SDPName)
+
+	// disabled by default
+	require.False(t, media.CodecEnabled(mono))
+	require.Nil(t, sdp.CodecByName("L16/16000"))
+	m, err := sdp.ParseMedia(&psdp.MediaDescription{MediaName: psdp.MediaName{Formats: []string{"11", "0"}}})
+	require.NoError(t, err)
+	audio, err := sdp.SelectAudio(*m, true)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, audio.Type)
+
+	media.CodecSetEnabled("L16/16000", true)
+	defer media.CodecSetEnabled("L16/16000", false)
+	c, ok := sdp.CodecByName("L16/16000/1").(rtp.AudioCodec)
+	require.True(t, ok)
+	require.Equal(t, 16000, c.Info().SampleRate)
+	require.Equal(t, 16000, c.Info().RTPClockRate)
+	require.False(t, c.Info().RTPIsStatic)
+}
+
+func TestRTP(t *testing.T) {
+	for _, rate := range SampleRates {
+		for _, channels := range []int{1, 2} {
+			t.Run(SDPName(rate, channels), func(t *testing.T) {
+				media.CodecSetEnabled(SDPName(rate, channels), true)
+				defer media.CodecSetEnabled(SDPName(rate, channels), false)
+				c := sdp.CodecByName(SDPName(rate, channels)).(rtp.AudioCodec)
+
+				frame := rate / rtp.DefFramesPerSec
+				pcm := make(media.PCM16Sample, frame)
+				for i := range pcm {
+					pcm[i] = int16(10000 * math.Sin(2*math.Pi*440*float64(i)/float64(rate)))
+				}
+
+				var buf rtp.Buffer
+				enc := c.EncodeRTP(rtp.NewSeqWriter(&buf).NewStream(96, rate))
+				require.Equal(t, rate, enc.SampleRate())
+				for range 3 {
+					require.NoError(t, enc.WriteSample(pcm))
+				}
+				// frames are split to fit the MTU
+				packets := (2*frame*channels + MaxPayloadSize - 1) / MaxPayloadSize
+				if rate >= 44100 {
+					require.Greater(t, packets, 1)
+				}
+				require.Len(t, buf, 3*packets)
+				var got media.PCM16Sample
+				for i, p := range buf {
+					require.LessOrEqual(t, len(p.Payload), MaxPayloadSize)
+					require.Equal(t, uint32(len(got)/channels), p.Timestamp-buf[0].Timestamp, "packet %d", i)
+					got = append(got, Sample(p.Payload).Decode()...)
+				}
+				require.Len(t, got, 3*frame*channels)
+				for i := range pcm {
+					for ch := range channels {
+						require.Equal(t, pcm[i], got[i*channels+ch])
+					}
+				}
+
+				var frames []media.PCM16Sample
+				dec := c.DecodeRTP(media.NewPCM16FrameWriter(&frames, rate), 96)
+				for _, p := range buf {
+					require.NoError(t, dec.HandleRTP(&p.Header, p.Payload))
+				}
+				var total int
+				for _, f := range frames {
+					total += len(f)
+				}
+				require.Equal(t, 3*frame, total)
+			})
+		}
+	}
+}
+
+func TestStereoDownmix(t *testing.T) {
+	var frames []media.PCM16Sample
+	d := Decode(media.NewPCM16FrameWriter(&frames, 8000), 8000, 2)
+	require.NoError(t, d.WriteSample(Sample{
+		0x7f, 0xff, 0x7f, 0xff, // no overflow
+		0x00, 0x10, 0x00, 0x20,
+		0x80, 0x00, 0x80, 0x00,
+	}))
+	require.Equal(t, []media.PCM16Sample{{math.MaxInt16, 0x18, math.MinInt16}}, frames)
+
+	// decoding to a different sample rate
+	frames = nil
+	d = Decode(media.NewPCM16FrameWriter(&frames, 16000), 8000, 1)
+	require.Equal(t, 8000, d.SampleRate())
+	require.NoError(t, d.WriteSample(make(Sample, 320)))
+	require.Len(t, frames, 1)
+	require.Len(t, frames[0], 320)
+}
@@ -58,6 +58,27 @@ type WriteCloser[T any] interface {
 	Close() error
 }
 
+// LossConcealer is an optional interface for Writer that can conceal lost frames.
+type LossConcealer[T any] interface {
+	// ConcealLoss generates a replacement for a given number of lost frames.
+	// The next frame after the gap is passed as a hint, if available. It is still written separately with WriteSample.
+	ConcealLoss(frames int, next T) error
+}
+
+// VoiceActivity is a voice activity metadata of an audio frame.
+type VoiceActivity struct {
+	// Voice is set if the frame contains speech.
+	Voice bool
+	// Level is an audio level of the frame in -dBov, from 0 (loudest) to 127 (silence), as defined in RFC 6464.
+	Level uint8
+}
+
+// VoiceActivityWriter is an optional interface for Writer that uses voice activity metadata.
+type VoiceActivityWriter interface {
+	// SetVoiceActivity is called before each frame is written, with the metadata of that frame.
+	SetVoiceActivity(v VoiceActivity)
+}
+
 type writeCloser[T any] struct {
 	Writer[T]
 }
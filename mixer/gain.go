@@ -0,0 +1,240 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixer
+
+import (
+	"math"
+	"time"
+
+	msdk "github.com/livekit/media-sdk"
+)
+
+const (
+	DefaultDuckingGain      = -20.0 // dB
+	DefaultDuckingThreshold = -40.0 // dBFS
+	DefaultDuckingHold      = 300 * time.Millisecond
+)
+
+// DuckingConfig configures ducking: lowering the volume of selected inputs while other inputs are active.
+type DuckingConfig struct {
+	// Gain applied to ducked inputs, in dB. Default is DefaultDuckingGain.
+	Gain float64
+	// Threshold is the level in dBFS above which an input is considered active. Default is DefaultDuckingThreshold.
+	Threshold float64
+	// Hold is the time for which ducking stays after other inputs become inactive. Default is DefaultDuckingHold.
+	Hold time.Duration
+}
+
+// SetDucking enables ducking for the mixer. Inputs marked with Input.SetDucked are lowered
+// while any of the other inputs are active. Setting nil disables ducking.
+func (m *Mixer) SetDucking(conf *DuckingConfig) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if conf == nil {
+		m.ducking = nil
+		return
+	}
+	c := *conf
+	if c.Gain == 0 {
+		c.Gain = DefaultDuckingGain
+	}
+	if c.Threshold == 0 {
+		c.Threshold = DefaultDuckingThreshold
+	}
+	if c.Hold == 0 {
+		c.Hold = DefaultDuckingHold
+	}
+	m.ducking = &c
+}
+
+// updateDucking updates ducking state based on activity of non-ducked inputs in the current mix.
+func (m *Mixer) updateDucking(active bool) bool {
+	if m.ducking == nil {
+		m.duckRemains = 0
+		return false
+	}
+	if active {
+		m.duckRemains = int(time.Duration(m.sampleRate) * m.ducking.Hold / time.Second)
+		return true
+	}
+	if m.duckRemains <= 0 {
+		return false
+	}
+	m.duckRemains -= m.frameSize
+	return true
+}
+
+// isActive checks if the RMS level of the sample is above the threshold (in dBFS).
+func isActive(sample msdk.PCM16Sample, threshold float64) bool {
+	if len(sample) == 0 {
+		return false
+	}
+	var sum float64
+	for _, v := range sample {
+		sum += float64(v) * float64(v)
+	}
+	lvl := 0x8000 * dbToGain(threshold)
+	return sum/float64(len(sample)) > lvl*lvl
+}
+
+func dbToGain(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+type inputGain struct {
+	gainDB float64
+	muted  bool
+	pan    float64
+	ducked bool
+
+	cur    []float64 // gain applied to each output channel at the end of the last mix
+	target []float64
+}
+
+func newInputGain(channels int) inputGain {
+	g := inputGain{
+		cur:    make([]float64, channels),
+		target: make([]float64, channels),
+	}
+	for c := range g.cur {
+		g.cur[c] = 1
+	}
+	return g
+}
+
+// updateTarget calculates target gain for each output channel. Duck is a linear gain applied to ducked inputs.
+func (g *inputGain) updateTarget(duck float64) {
+	v := dbToGain(g.gainDB)
+	if g.muted {
+		v = 0
+	}
+	if g.ducked {
+		v *= duck
+	}
+	for c := range g.target {
+		g.target[c] = v
+	}
+	if len(g.target) == 2 && g.pan != 0 {
+		// Pan attenuates the opposite channel, keeping the level unchanged in the center.
+		g.target[0] *= math.Cos(max(g.pan, 0) * math.Pi / 2)
+		g.target[1] *= math.Cos(max(-g.pan, 0) * math.Pi / 2)
+	}
+}
+
+// apply the gain to interleaved samples, ramping linearly from the previous gain across the frame.
+func (g *inputGain) apply(buf []int32) {
+	channels := len(g.cur)
+	unity := true
+	for c := range g.cur {
+		if g.cur[c] != 1 || g.target[c] != 1 {
+			unity = false
+			break
+		}
+	}
+	if unity {
+		return
+	}
+	frames := len(buf) / channels
+	for f := 0; f < frames; f++ {
+		t := float64(f+1) / float64(frames)
+		for c := 0; c < channels; c++ {
+			v := g.cur[c] + (g.target[c]-g.cur[c])*t
+			buf[f*channels+c] = int32(math.Round(float64(buf[f*channels+c]) * v))
+		}
+	}
+}
+
+// applyGain updates the gain and applies it to the current contribution of the input.
+func (i *Input) applyGain(duck float64) {
+	i.gain.updateTarget(duck)
+	if i.mixed {
+		i.gain.apply(i.contrib)
+	}
+	copy(i.gain.cur, i.gain.target)
+}
+
+// SetGain sets the gain of the input in dB. Changes are applied smoothly across the next mixed frame.
+func (i *Input) SetGain(db float64) {
+	if i == nil {
+		return
+	}
+	i.m.mu.Lock()
+	defer i.m.mu.Unlock()
+	i.gain.gainDB = db
+}
+
+// Gain returns the gain of the input in dB.
+func (i *Input) Gain() float64 {
+	if i == nil {
+		return 0
+	}
+	i.m.mu.Lock()
+	defer i.m.mu.Unlock()
+	return i.gain.gainDB
+}
+
+// SetMute mutes or unmutes the input. Gain setting is preserved while the input is muted.
+func (i *Input) SetMute(muted bool) {
+	if i == nil {
+		return
+	}
+	i.m.mu.Lock()
+	defer i.m.mu.Unlock()
+	i.gain.muted = muted
+}
+
+// Muted checks if the input is muted.
+func (i *Input) Muted() bool {
+	if i == nil {
+		return false
+	}
+	i.m.mu.Lock()
+	defer i.m.mu.Unlock()
+	return i.gain.muted
+}
+
+// SetPan sets the position of the input in the stereo output, from -1 (left) to 1 (right).
+// It has no effect if the mixer output is not stereo.
+func (i *Input) SetPan(pan float64) {
+	if i == nil {
+		return
+	}
+	i.m.mu.Lock()
+	defer i.m.mu.Unlock()
+	i.gain.pan = min(max(pan, -1), 1)
+}
+
+// Pan returns the position of the input in the stereo output.
+func (i *Input) Pan() float64 {
+	if i == nil {
+		return 0
+	}
+	i.m.mu.Lock()
+	defer i.m.mu.Unlock()
+	return i.gain.pan
+}
+
+// SetDucked marks the input to be lowered while other inputs are active. See Mixer.SetDucking.
+func (i *Input) SetDucked(ducked bool) {
+	if i == nil {
+		return
+	}
+	i.m.mu.Lock()
+	defer i.m.mu.Unlock()
+	i.gain.ducked = ducked
+}
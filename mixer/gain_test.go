@@ -0,0 +1,116 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixer
+
+import (
+	"testing"
+	"time"
+
+	msdk "github.com/livekit/media-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputGain(t *testing.T) {
+	t.Run("gain ramps across the frame", func(t *testing.T) {
+		m := newTestMixer(t)
+		inp := m.NewInput()
+		defer inp.Close()
+		inp.buffering = false
+
+		inp.SetGain(-6.0206) // x0.5
+		require.InDelta(t, -6.0206, inp.Gain(), 1e-9)
+		inp.WriteSample([]int16{1000, 1000, 1000, 1000, 1000})
+		m.Expect(msdk.PCM16Sample{900, 800, 700, 600, 500})
+		inp.WriteSample([]int16{1000, 1000, 1000, 1000, 1000})
+		m.Expect(msdk.PCM16Sample{500, 500, 500, 500, 500})
+	})
+
+	t.Run("mute keeps the gain", func(t *testing.T) {
+		m := newTestMixer(t)
+		inp := m.NewInput()
+		defer inp.Close()
+		inp.buffering = false
+
+		inp.SetMute(true)
+		require.True(t, inp.Muted())
+		inp.WriteSample([]int16{1000, 1000, 1000, 1000, 1000})
+		m.Expect(msdk.PCM16Sample{800, 600, 400, 200, 0})
+		inp.WriteSample([]int16{1000, 1000, 1000, 1000, 1000})
+		m.Expect(msdk.PCM16Sample{0, 0, 0, 0, 0})
+
+		inp.SetMute(false)
+		inp.WriteSample([]int16{1000, 1000, 1000, 1000, 1000})
+		m.Expect(msdk.PCM16Sample{200, 400, 600, 800, 1000})
+	})
+
+	t.Run("pan", func(t *testing.T) {
+		m := &testMixer{t: t}
+		m.Mixer = newMixer(newTestWriter(&m.sample, 8000), 2, 2, nil, DefaultInputBufferFrames)
+		inp := m.NewInputWithChannels(1)
+		defer inp.Close()
+		inp.buffering = false
+
+		inp.SetPan(2)
+		require.Equal(t, 1.0, inp.Pan())
+		inp.WriteSample([]int16{1000, 1000})
+		m.Expect(msdk.PCM16Sample{500, 1000, 0, 1000})
+		inp.WriteSample([]int16{1000, 1000})
+		m.Expect(msdk.PCM16Sample{0, 1000, 0, 1000})
+
+		inp.SetPan(-0.5)
+		inp.WriteSample([]int16{1000, 1000})
+		m.Expect(msdk.PCM16Sample{500, 854, 1000, 707})
+	})
+}
+
+func TestMixerDucking(t *testing.T) {
+	m := newTestMixer(t)
+	m.SetDucking(&DuckingConfig{Gain: -6.0206, Hold: 625 * time.Microsecond}) // hold for one frame
+
+	music := m.NewInput()
+	defer music.Close()
+	music.buffering = false
+	music.SetDucked(true)
+
+	voice := m.NewInput()
+	defer voice.Close()
+	voice.buffering = false
+
+	// quiet voice input doesn't trigger ducking
+	music.WriteSample([]int16{1000, 1000, 1000, 1000, 1000})
+	voice.WriteSample([]int16{1, -1, 1, -1, 1})
+	m.Expect(msdk.PCM16Sample{1001, 999, 1001, 999, 1001})
+
+	// active voice lowers the music
+	music.WriteSample([]int16{1000, 1000, 1000, 1000, 1000})
+	voice.WriteSample([]int16{2000, 2000, 2000, 2000, 2000})
+	m.Expect(msdk.PCM16Sample{2900, 2800, 2700, 2600, 2500})
+	music.WriteSample([]int16{1000, 1000, 1000, 1000, 1000})
+	voice.WriteSample([]int16{2000, 2000, 2000, 2000, 2000})
+	m.Expect(msdk.PCM16Sample{2500, 2500, 2500, 2500, 2500})
+
+	// music stays lowered during the hold time and then restores
+	music.WriteSample([]int16{1000, 1000, 1000, 1000, 1000})
+	m.Expect(msdk.PCM16Sample{500, 500, 500, 500, 500})
+	music.WriteSample([]int16{1000, 1000, 1000, 1000, 1000})
+	m.Expect(msdk.PCM16Sample{600, 700, 800, 900, 1000})
+
+	// disabled ducking
+	m.SetDucking(nil)
+	voice.buffering = false // restarted after starving
+	music.WriteSample([]int16{1000, 1000, 1000, 1000, 1000})
+	voice.WriteSample([]int16{2000, 2000, 2000, 2000, 2000})
+	m.Expect(msdk.PCM16Sample{3000, 3000, 3000, 3000, 3000})
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixer
+
+import (
+	"math"
+	"time"
+
+	msdk "github.com/livekit/media-sdk"
+)
+
+const (
+	DefaultLimiterThreshold = -1.0 // dBFS
+	DefaultLimiterRelease   = 50 * time.Millisecond
+	DefaultLimiterLookahead = 5 * time.Millisecond
+)
+
+// LimiterConfig configures a look-ahead limiter on the mixer output.
+type LimiterConfig struct {
+	// Threshold is the max output level in dBFS. Default is DefaultLimiterThreshold.
+	Threshold float64
+	// Release is the time constant for the gain to recover after the peak. Default is DefaultLimiterRelease.
+	Release time.Duration
+	// Lookahead is the time the limiter starts reducing the gain before the peak. It delays the output by the same amount.
+	// Default is DefaultLimiterLookahead.
+	Lookahead time.Duration
+}
+
+// SetLimiter sets a limiter for the mixer output and mix-minus outputs.
+// NewMixer enables the limiter with default settings. Setting nil disables it, causing hard clipping on overflow.
+func (m *Mixer) SetLimiter(conf *LimiterConfig) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if conf == nil {
+		m.limiter = nil
+	} else {
+		c := *conf
+		if c.Threshold == 0 {
+			c.Threshold = DefaultLimiterThreshold
+		}
+		if c.Release <= 0 {
+			c.Release = DefaultLimiterRelease
+		}
+		if c.Lookahead < 0 {
+			c.Lookahead = 0
+		} else if c.Lookahead == 0 {
+			c.Lookahead = DefaultLimiterLookahead
+		}
+		m.limiter = &c
+	}
+	m.bus = m.newLimiter()
+	for _, inp := range m.inputs {
+		inp.minusLim = nil
+		if inp.minus != nil {
+			inp.minusLim = m.newLimiter()
+		}
+	}
+}
+
+func (m *Mixer) newLimiter() *limiter {
+	if m.limiter == nil {
+		return nil
+	}
+	return newLimiter(*m.limiter, m.sampleRate, m.channels)
+}
+
+func newLimiter(conf LimiterConfig, sampleRate, channels int) *limiter {
+	look := int(time.Duration(sampleRate) * conf.Lookahead / time.Second)
+	l := &limiter{
+		channels: channels,
+		thr:      0x7FFF * dbToGain(min(conf.Threshold, 0)),
+		release:  1 - math.Exp(-1/(conf.Release.Seconds()*float64(sampleRate))),
+		look:     look,
+		buf:      make([]int32, (look+1)*channels),
+		req:      make([]float64, look+1),
+		gain:     1,
+	}
+	for i := range l.req {
+		l.req[i] = 1
+	}
+	return l
+}
+
+// limiter is a look-ahead peak limiter. It delays the signal by the look-ahead time, and ramps the gain down
+// linearly before each peak, so that the peak never exceeds the threshold. Gain recovers exponentially after the peak.
+type limiter struct {
+	channels int
+	thr      float64 // threshold in sample units
+	release  float64 // release coefficient per sample
+	look     int     // look-ahead in samples (per channel)
+
+	buf   []int32   // delay line, interleaved
+	req   []float64 // gain required for each sample in the delay line
+	pos   int       // position of the last written sample in the delay line
+	overs int       // number of samples in the delay line that require gain reduction
+	gain  float64   // current gain
+
+	minGain float64 // min gain applied during the last process call
+}
+
+// process applies the limiter to interleaved samples from in and writes the result to out.
+func (l *limiter) process(in []int32, out msdk.PCM16Sample) {
+	n := len(l.req)
+	l.minGain = 1
+	for f := 0; f < len(in)/l.channels; f++ {
+		// Add new sample to the delay line, replacing the oldest one, which was written out in the previous iteration.
+		l.pos = (l.pos + 1) % n
+		if l.req[l.pos] < 1 {
+			l.overs--
+		}
+		src := in[f*l.channels : (f+1)*l.channels]
+		copy(l.buf[l.pos*l.channels:], src)
+		var peak int32
+		for _, v := range src {
+			peak = max(peak, v, -v)
+		}
+		l.req[l.pos] = 1
+		if float64(peak) > l.thr {
+			l.req[l.pos] = l.thr / float64(peak)
+			l.overs++
+		}
+
+		// Output the oldest sample in the delay line.
+		o := (l.pos + 1) % n
+		target := 1.0
+		if l.overs > 0 {
+			for d := 0; d < n; d++ {
+				r := l.req[(o+d)%n]
+				if r < 1 {
+					// Ramp towards the gain required by the peak, to reach it exactly at the peak.
+					target = min(target, r+(1-r)*float64(d)/float64(n))
+				}
+			}
+		}
+		if target < l.gain {
+			l.gain = target
+		} else {
+			l.gain += (target - l.gain) * l.release
+		}
+		l.minGain = min(l.minGain, l.gain)
+		dst := out[f*l.channels : (f+1)*l.channels]
+		for c, v := range l.buf[o*l.channels : (o+1)*l.channels] {
+			dst[c] = clip16(int32(math.Round(float64(v) * l.gain)))
+		}
+	}
+}
+
+// gainReduction returns max gain reduction during the last process call, in dB.
+func (l *limiter) gainReduction() float64 {
+	if l.minGain >= 1 {
+		return 0
+	}
+	return -20 * math.Log10(l.minGain)
+}
+
+// limit converts the mix result to the output, using the limiter, if it is set.
+func limit(l *limiter, in []int32, out msdk.PCM16Sample) {
+	if l == nil {
+		for i, v := range in {
+			out[i] = clip16(v)
+		}
+		return
+	}
+	l.process(in, out)
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mixer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	msdk "github.com/livekit/media-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	const (
+		rate = 8000
+		look = 8
+	)
+	conf := LimiterConfig{Threshold: -6.0206, Release: 10 * time.Millisecond, Lookahead: look * time.Second / rate}
+	thr := int16(0x7FFF / 2)
+
+	t.Run("below threshold", func(t *testing.T) {
+		l := newLimiter(conf, rate, 1)
+		in := []int32{100, 200, 300, 400, 500, 600, 700, 800, 900, 1000}
+		out := make(msdk.PCM16Sample, len(in))
+		l.process(in, out)
+		// delayed by look-ahead
+		require.Equal(t, msdk.PCM16Sample{0, 0, 0, 0, 0, 0, 0, 0, 100, 200}, out)
+		require.Zero(t, l.gainReduction())
+	})
+
+	t.Run("peak", func(t *testing.T) {
+		l := newLimiter(conf, rate, 2)
+		in := make([]int32, 2*64)
+		for i := range in {
+			in[i] = 10000
+		}
+		// single peak in one of the channels
+		in[2*20+1] = 40000
+		out := make(msdk.PCM16Sample, len(in))
+		l.process(in, out)
+		require.InDelta(t, 20*math.Log10(40000/float64(thr)), l.gainReduction(), 0.01)
+
+		peak := 20 + look
+		require.InDelta(t, thr, out[2*peak+1], 1)
+		// gain ramps down before the peak
+		for i := peak - look + 1; i <= peak; i++ {
+			require.Less(t, out[2*i], out[2*(i-1)], "sample %d", i)
+		}
+		// and recovers after it
+		for i := peak + 1; i < len(out)/2; i++ {
+			require.Greater(t, out[2*i], out[2*(i-1)], "sample %d", i)
+		}
+	})
+
+	t.Run("output never exceeds threshold", func(t *testing.T) {
+		l := newLimiter(conf, rate, 1)
+		in := make([]int32, rate/10)
+		for i := range in {
+			in[i] = int32(3 * 0x7FFF * math.Sin(2*math.Pi*440*float64(i)/rate))
+		}
+		out := make(msdk.PCM16Sample, len(in))
+		l.process(in, out)
+		for _, v := range out {
+			require.LessOrEqual(t, int(math.Abs(float64(v))), int(thr)+1)
+		}
+	})
+}
+
+func TestMixerLimiter(t *testing.T) {
+	m := newTestMixer(t)
+	m.SetLimiter(&LimiterConfig{Lookahead: -1}) // no look-ahead
+
+	one := m.NewInput()
+	defer one.Close()
+	one.buffering = false
+	two := m.NewInput()
+	defer two.Close()
+	two.buffering = false
+
+	one.WriteSample([]int16{100, 200, 300, 400, 500})
+	two.WriteSample([]int16{100, 200, 300, 400, 500})
+	m.Expect(msdk.PCM16Sample{200, 400, 600, 800, 1000})
+	require.Zero(t, m.stats.LimitedMixes.Load())
+	require.Zero(t, m.stats.GainReduction.Load())
+
+	one.WriteSample([]int16{0x7FFF, 0x7FFF, 0, 0, 0})
+	two.WriteSample([]int16{0x7FFF, 0x7FFF, 0, 0, 0})
+	m.mixOnce()
+	thr := int16(0x7FFF * dbToGain(DefaultLimiterThreshold))
+	require.InDelta(t, thr, m.sample[0], 1)
+	require.InDelta(t, thr, m.sample[1], 1)
+	require.EqualValues(t, 1, m.stats.LimitedMixes.Load())
+	require.InDelta(t, 702, m.stats.GainReduction.Load(), 1) // ~6 dB overflow + 1 dB threshold
+}
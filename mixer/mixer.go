@@ -16,6 +16,7 @@ package mixer
 
 import (
 	"fmt"
+	"math"
 	"slices"
 	"sync"
 	"sync/atomic"
@@ -49,27 +50,60 @@ type Stats struct {
 
 	OutputSamples atomic.Uint64
 	OutputFrames  atomic.Uint64
+
+	// MixMinusSamples and MixMinusFrames count samples and frames written to mix-minus outputs of the inputs.
+	// They are not included in OutputSamples and OutputFrames.
+	MixMinusSamples atomic.Uint64
+	MixMinusFrames  atomic.Uint64
+
+	// LimitedMixes is the number of mixes where the limiter reduced the gain of the output.
+	LimitedMixes atomic.Uint64
+	// GainReduction is the max gain reduction applied by the limiter to the output in the last mix, in 1/100 dB.
+	GainReduction atomic.Uint64
 }
 
 type Input struct {
 	m          *Mixer
 	sampleRate int
+	channels   int
 	mu         sync.Mutex
 	buf        *ring.Buffer[int16]
 	buffering  bool
+
+	// fields below are guarded by Mixer.mu
+	minus    msdk.Writer[msdk.PCM16Sample] // mix-minus output
+	minusLim *limiter                      // limiter for mix-minus output
+	contrib  []int32                       // contribution of this input to the current mix
+	mixed    bool                          // input contributed to the current mix
+	gain     inputGain
+}
+
+type mixOutput struct {
+	w   msdk.Writer[msdk.PCM16Sample]
+	out msdk.PCM16Sample
 }
 
 type Mixer struct {
 	out        msdk.Writer[msdk.PCM16Sample]
 	sampleRate int
+	channels   int
+	frameSize  int // number of samples per channel in a single mix
 
 	mu     sync.Mutex
 	inputs []*Input
 
 	tickerDur time.Duration
 	ticker    *time.Ticker
-	mixBuf    []int32          // mix result buffer
+	mixBuf    []int32          // mix result buffer, interleaved
 	mixTmp    msdk.PCM16Sample // temp buffer for reading input buffers
+	minusOut  []mixOutput      // mix-minus outputs of the current mix
+
+	limiter  *LimiterConfig
+	bus      *limiter // limiter for the main output
+	minusTmp []int32  // temp buffer for mix-minus outputs
+
+	ducking     *DuckingConfig
+	duckRemains int // number of samples left before ducking is released
 
 	lastMixEndTs time.Time
 	stopped      core.Fuse
@@ -85,13 +119,18 @@ type Mixer struct {
 	stats *Stats
 }
 
+// NewMixer creates a mixer that writes interleaved PCM with a given number of channels to out.
+//
+// Inputs may have a different number of channels. Inputs with fewer channels are upmixed (mono is copied to all channels),
+// while inputs with more channels are downmixed by averaging.
 func NewMixer(out msdk.Writer[msdk.PCM16Sample], bufferDur time.Duration, st *Stats, channels int, inputBufferFrames int) (*Mixer, error) {
-	if channels != 1 {
-		return nil, fmt.Errorf("only mono mixing is supported")
+	if channels < 1 {
+		return nil, fmt.Errorf("invalid number of channels: %d", channels)
 	}
 
 	mixSize := int(time.Duration(out.SampleRate()) * bufferDur / time.Second)
-	m := newMixer(out, mixSize, st, inputBufferFrames)
+	m := newMixer(out, channels, mixSize, st, inputBufferFrames)
+	m.SetLimiter(&LimiterConfig{})
 	m.tickerDur = bufferDur
 	m.ticker = time.NewTicker(bufferDur)
 
@@ -100,15 +139,18 @@ func NewMixer(out msdk.Writer[msdk.PCM16Sample], bufferDur time.Duration, st *St
 	return m, nil
 }
 
-func newMixer(out msdk.Writer[msdk.PCM16Sample], mixSize int, st *Stats, inputBufferFrames int) *Mixer {
+func newMixer(out msdk.Writer[msdk.PCM16Sample], channels int, mixSize int, st *Stats, inputBufferFrames int) *Mixer {
 	if st == nil {
 		st = new(Stats)
 	}
 	return &Mixer{
 		out:               out,
 		sampleRate:        out.SampleRate(),
-		mixBuf:            make([]int32, mixSize),
-		mixTmp:            make(msdk.PCM16Sample, mixSize),
+		channels:          channels,
+		frameSize:         mixSize,
+		mixBuf:            make([]int32, mixSize*channels),
+		mixTmp:            make(msdk.PCM16Sample, mixSize*channels),
+		minusTmp:          make([]int32, mixSize*channels),
 		stats:             st,
 		inputBufferFrames: inputBufferFrames,
 		inputBufferMin:    inputBufferFrames/2 + 1,
@@ -118,10 +160,16 @@ func newMixer(out msdk.Writer[msdk.PCM16Sample], mixSize int, st *Stats, inputBu
 func (m *Mixer) mixInputs() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	// Keep at least half of the samples buffered.
-	bufMin := m.inputBufferMin * len(m.mixBuf)
+	active := false
 	for _, inp := range m.inputs {
-		n, _ := inp.readSample(bufMin, m.mixTmp[:len(m.mixBuf)])
+		inp.mixed = false
+		// Keep at least half of the samples buffered.
+		bufMin := m.inputBufferMin * m.frameSize * inp.channels
+		size := m.frameSize * inp.channels
+		if cap(m.mixTmp) < size {
+			m.mixTmp = make(msdk.PCM16Sample, size)
+		}
+		n, _ := inp.readSample(bufMin, m.mixTmp[:size])
 		if n == 0 {
 			continue
 		}
@@ -130,10 +178,78 @@ func (m *Mixer) mixInputs() {
 		m.stats.MixedSamples.Add(uint64(n))
 
 		m.mixTmp = m.mixTmp[:n]
-		for j, v := range m.mixTmp {
-			// Add the samples. This can potentially lead to overflow, but is unlikely and dividing by the source
-			// count would cause the volume to drop every time somebody joins
-			m.mixBuf[j] += int32(v)
+		clear(inp.contrib)
+		m.mixChannels(inp.contrib, m.mixTmp, inp.channels)
+		inp.mixed = true
+		if m.ducking != nil && !inp.gain.ducked && !active {
+			active = isActive(m.mixTmp, m.ducking.Threshold)
+		}
+	}
+	duck := 1.0
+	if m.updateDucking(active) {
+		duck = dbToGain(m.ducking.Gain)
+	}
+
+	for _, inp := range m.inputs {
+		// Gain is updated even if there's no input to avoid ramping when the input restarts.
+		inp.applyGain(duck)
+		if !inp.mixed {
+			continue
+		}
+		// Add the samples. This can potentially lead to overflow, which is handled by the limiter on the output.
+		// Dividing by the source count would cause the volume to drop every time somebody joins.
+		for j, v := range inp.contrib {
+			m.mixBuf[j] += v
+		}
+	}
+
+	// Mix-minus outputs are computed from the shared sum by subtracting input's own contribution.
+	m.minusOut = m.minusOut[:0]
+	for _, inp := range m.inputs {
+		if inp.minus == nil {
+			continue
+		}
+		copy(m.minusTmp, m.mixBuf)
+		if inp.mixed {
+			for j, v := range inp.contrib {
+				m.minusTmp[j] -= v
+			}
+		}
+		out := make(msdk.PCM16Sample, len(m.mixBuf))
+		limit(inp.minusLim, m.minusTmp, out)
+		m.minusOut = append(m.minusOut, mixOutput{w: inp.minus, out: out})
+	}
+}
+
+// mixChannels adds interleaved samples with a given number of channels to the mix buffer.
+func (m *Mixer) mixChannels(mixBuf []int32, in msdk.PCM16Sample, channels int) {
+	switch {
+	case channels == m.channels:
+		for j, v := range in {
+			mixBuf[j] += int32(v)
+		}
+	case channels < m.channels:
+		// upmix: output channel c takes input channel c % channels, thus mono is copied to all channels
+		for f := 0; f < len(in)/channels; f++ {
+			src := in[f*channels : (f+1)*channels]
+			dst := mixBuf[f*m.channels : (f+1)*m.channels]
+			for c := range dst {
+				dst[c] += int32(src[c%channels])
+			}
+		}
+	default:
+		// downmix: output channel c is an average of input channels k, where k % m.channels == c
+		for f := 0; f < len(in)/channels; f++ {
+			src := in[f*channels : (f+1)*channels]
+			dst := mixBuf[f*m.channels : (f+1)*m.channels]
+			for c := range dst {
+				var sum, cnt int32
+				for k := c; k < channels; k += m.channels {
+					sum += int32(src[k])
+					cnt++
+				}
+				dst[c] += sum / cnt
+			}
 		}
 	}
 }
@@ -152,20 +268,44 @@ func (m *Mixer) mixOnce() {
 
 	// TODO: if we can guarantee that WriteSample won't store the sample, we can avoid allocation
 	out := make(msdk.PCM16Sample, len(m.mixBuf))
-	for i, v := range m.mixBuf {
-		if v > 0x7FFF {
-			v = 0x7FFF
-		}
-		if v < -0x7FFF {
-			v = -0x7FFF
-		}
-		out[i] = int16(v)
-	}
+	m.limitOutput(out)
 
 	m.stats.OutputFrames.Add(1)
 	m.stats.OutputSamples.Add(uint64(len(out)))
 
 	_ = m.out.WriteSample(out)
+
+	for _, o := range m.minusOut {
+		m.stats.MixMinusFrames.Add(1)
+		m.stats.MixMinusSamples.Add(uint64(len(o.out)))
+
+		_ = o.w.WriteSample(o.out)
+	}
+	clear(m.minusOut)
+}
+
+func (m *Mixer) limitOutput(out msdk.PCM16Sample) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	limit(m.bus, m.mixBuf, out)
+	var reduction float64
+	if m.bus != nil {
+		reduction = m.bus.gainReduction()
+	}
+	if reduction > 0 {
+		m.stats.LimitedMixes.Add(1)
+	}
+	m.stats.GainReduction.Store(uint64(math.Round(reduction * 100)))
+}
+
+func clip16(v int32) int16 {
+	if v > 0x7FFF {
+		v = 0x7FFF
+	}
+	if v < -0x7FFF {
+		v = -0x7FFF
+	}
+	return int16(v)
 }
 
 func (m *Mixer) mixUpdate() {
@@ -221,10 +361,19 @@ func (m *Mixer) Stop() {
 	m.stopped.Break()
 }
 
+// NewInput creates a new mixer input with the same number of channels as the mixer.
 func (m *Mixer) NewInput() *Input {
 	if m == nil {
 		return nil
 	}
+	return m.NewInputWithChannels(m.channels)
+}
+
+// NewInputWithChannels creates a new mixer input that accepts interleaved samples with a given number of channels.
+func (m *Mixer) NewInputWithChannels(channels int) *Input {
+	if m == nil || channels < 1 {
+		return nil
+	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if m.stopped.IsBroken() {
@@ -237,7 +386,10 @@ func (m *Mixer) NewInput() *Input {
 	inp := &Input{
 		m:          m,
 		sampleRate: m.sampleRate,
-		buf:        ring.NewBuffer[int16](len(m.mixBuf) * m.inputBufferFrames),
+		channels:   channels,
+		buf:        ring.NewBuffer[int16](m.frameSize * channels * m.inputBufferFrames),
+		contrib:    make([]int32, len(m.mixBuf)),
+		gain:       newInputGain(m.channels),
 		buffering:  true, // buffer some data initially
 	}
 	m.inputs = append(m.inputs, inp)
@@ -266,6 +418,11 @@ func (m *Mixer) SampleRate() int {
 	return m.sampleRate
 }
 
+// Channels returns the number of channels in the mixer output.
+func (m *Mixer) Channels() int {
+	return m.channels
+}
+
 func (i *Input) readSample(bufMin int, out msdk.PCM16Sample) (int, error) {
 	i.mu.Lock()
 	defer i.mu.Unlock()
@@ -284,6 +441,23 @@ func (i *Input) readSample(bufMin int, out msdk.PCM16Sample) (int, error) {
 	return n, err
 }
 
+// SetMixMinus sets a writer that receives a mix of all inputs except this one (N-1 mix).
+//
+// The writer gets a sample on each mix, with the same size and number of channels as the main output.
+// The writer is not closed when the input is removed. Setting nil disables the output.
+func (i *Input) SetMixMinus(w msdk.Writer[msdk.PCM16Sample]) {
+	if i == nil {
+		return
+	}
+	i.m.mu.Lock()
+	defer i.m.mu.Unlock()
+	i.minus = w
+	i.minusLim = nil
+	if w != nil {
+		i.minusLim = i.m.newLimiter()
+	}
+}
+
 func (i *Input) String() string {
 	return fmt.Sprintf("MixInput(%d) -> %s", i.sampleRate, i.m.String())
 }
@@ -292,6 +466,11 @@ func (i *Input) SampleRate() int {
 	return i.sampleRate
 }
 
+// Channels returns the number of channels expected by the input.
+func (i *Input) Channels() int {
+	return i.channels
+}
+
 func (i *Input) Close() error {
 	if i == nil {
 		return nil
@@ -301,6 +480,9 @@ func (i *Input) Close() error {
 }
 
 func (i *Input) WriteSample(sample msdk.PCM16Sample) error {
+	if len(sample)%i.channels != 0 {
+		return fmt.Errorf("sample size %d is not a multiple of %d channels", len(sample), i.channels)
+	}
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
@@ -65,7 +65,7 @@ type testMixer struct {
 func newTestMixer(t testing.TB) *testMixer {
 	m := &testMixer{t: t}
 
-	m.Mixer = newMixer(newTestWriter(&m.sample, 8000), 5, nil, DefaultInputBufferFrames)
+	m.Mixer = newMixer(newTestWriter(&m.sample, 8000), 1, 5, nil, DefaultInputBufferFrames)
 	return m
 }
 
@@ -220,3 +220,110 @@ func TestMixer(t *testing.T) {
 		m.CheckSampleN(steps)
 	})
 }
+
+func TestMixerStereo(t *testing.T) {
+	newStereoMixer := func(t testing.TB) *testMixer {
+		m := &testMixer{t: t}
+		m.Mixer = newMixer(newTestWriter(&m.sample, 8000), 2, 3, nil, DefaultInputBufferFrames)
+		return m
+	}
+
+	t.Run("stereo input", func(t *testing.T) {
+		m := newStereoMixer(t)
+		inp := m.NewInput()
+		defer inp.Close()
+		require.Equal(t, 2, inp.Channels())
+		inp.buffering = false
+
+		require.NoError(t, inp.WriteSample([]int16{1, -1, 2, -2, 3, -3}))
+		m.Expect(msdk.PCM16Sample{1, -1, 2, -2, 3, -3})
+	})
+
+	t.Run("mono input is upmixed", func(t *testing.T) {
+		m := newStereoMixer(t)
+		mono := m.NewInputWithChannels(1)
+		defer mono.Close()
+		mono.buffering = false
+
+		stereo := m.NewInput()
+		defer stereo.Close()
+		stereo.buffering = false
+
+		require.NoError(t, mono.WriteSample([]int16{10, 20, 30}))
+		require.NoError(t, stereo.WriteSample([]int16{1, 2, 3, 4, 5, 6}))
+		m.Expect(msdk.PCM16Sample{11, 12, 23, 24, 35, 36})
+	})
+
+	t.Run("stereo input is downmixed", func(t *testing.T) {
+		m := newTestMixer(t)
+		inp := m.NewInputWithChannels(2)
+		defer inp.Close()
+		inp.buffering = false
+
+		require.NoError(t, inp.WriteSample([]int16{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}))
+		m.Expect(msdk.PCM16Sample{15, 35, 55, 75, 95})
+	})
+
+	t.Run("buffers whole frames", func(t *testing.T) {
+		m := newStereoMixer(t)
+		inp := m.NewInputWithChannels(1)
+		defer inp.Close()
+
+		stereo := m.NewInput()
+		defer stereo.Close()
+		require.Error(t, stereo.WriteSample([]int16{1, 2, 3}))
+
+		for i := 0; i < inputBufferMin-1; i++ {
+			require.NoError(t, inp.WriteSample([]int16{1, 2, 3}))
+			m.Expect(msdk.PCM16Sample{0, 0, 0, 0, 0, 0})
+		}
+		require.NoError(t, inp.WriteSample([]int16{1, 2, 3}))
+		m.Expect(msdk.PCM16Sample{1, 1, 2, 2, 3, 3})
+	})
+}
+
+func TestMixerMixMinus(t *testing.T) {
+	m := newTestMixer(t)
+	var outs [3]msdk.PCM16Sample
+	var inps [3]*Input
+	for i := range inps {
+		inps[i] = m.NewInput()
+		defer inps[i].Close()
+		inps[i].buffering = false
+		inps[i].SetMixMinus(newTestWriter(&outs[i], 8000))
+	}
+	inps[0].WriteSample([]int16{1, 2, 3, 4, 5})
+	inps[1].WriteSample([]int16{10, 20, 30, 40, 50})
+	inps[2].WriteSample([]int16{100, 200, 300, 400, 500})
+
+	m.Expect(msdk.PCM16Sample{111, 222, 333, 444, 555})
+	require.Equal(t, msdk.PCM16Sample{110, 220, 330, 440, 550}, outs[0])
+	require.Equal(t, msdk.PCM16Sample{101, 202, 303, 404, 505}, outs[1])
+	require.Equal(t, msdk.PCM16Sample{11, 22, 33, 44, 55}, outs[2])
+	require.EqualValues(t, 1, m.stats.OutputFrames.Load())
+	require.EqualValues(t, 5, m.stats.OutputSamples.Load())
+	require.EqualValues(t, 3, m.stats.MixMinusFrames.Load())
+	require.EqualValues(t, 3*5, m.stats.MixMinusSamples.Load())
+
+	// silent input gets the full mix
+	inps[0].WriteSample([]int16{1, 2, 3, 4, 5})
+	inps[1].WriteSample([]int16{10, 20, 30, 40, 50})
+	m.Expect(msdk.PCM16Sample{11, 22, 33, 44, 55})
+	require.Equal(t, msdk.PCM16Sample{10, 20, 30, 40, 50}, outs[0])
+	require.Equal(t, msdk.PCM16Sample{1, 2, 3, 4, 5}, outs[1])
+	require.Equal(t, msdk.PCM16Sample{11, 22, 33, 44, 55}, outs[2])
+
+	// clipped sum doesn't affect other outputs
+	inps[0].WriteSample([]int16{0x7FFF, 0, 0, 0, 0})
+	inps[1].WriteSample([]int16{0x7FFF, 0, 0, 0, 0})
+	inps[2].WriteSample([]int16{1, 0, 0, 0, 0})
+	m.Expect(msdk.PCM16Sample{0x7FFF, 0, 0, 0, 0})
+	require.Equal(t, msdk.PCM16Sample{0x7FFF, 0, 0, 0, 0}, outs[0])
+	require.Equal(t, msdk.PCM16Sample{0x7FFF, 0, 0, 0, 0}, outs[2])
+
+	// disabled output is no longer written
+	inps[2].SetMixMinus(nil)
+	outs[2] = nil
+	m.mixOnce()
+	require.Nil(t, outs[2])
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ogg implements streaming Ogg container (RFC 3533), with Opus (RFC 7845) and Vorbis audio.
+package ogg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	pageHeaderSize = 27
+	maxSegments    = 255
+	maxSegmentSize = 255
+
+	flagContinued = 0x01
+	flagBOS       = 0x02
+	flagEOS       = 0x04
+)
+
+var (
+	ErrInvalidPage = errors.New("ogg: invalid page")
+	ErrChecksum    = errors.New("ogg: checksum mismatch")
+)
+
+// Codec is an audio codec of Ogg stream.
+type Codec string
+
+const (
+	CodecOpus   Codec = "opus"
+	CodecVorbis Codec = "vorbis"
+)
+
+var crcTable [256]uint32
+
+func init() {
+	for i := range crcTable {
+		r := uint32(i) << 24
+		for range 8 {
+			if r&0x80000000 != 0 {
+				r = r<<1 ^ 0x04c11db7
+			} else {
+				r <<= 1
+			}
+		}
+		crcTable[i] = r
+	}
+}
+
+// crc computes Ogg checksum, which is a non-reflected CRC-32 with zero initial value.
+func crc(crc uint32, data []byte) uint32 {
+	for _, b := range data {
+		crc = crc<<8 ^ crcTable[byte(crc>>24)^b]
+	}
+	return crc
+}
+
+type page struct {
+	flags   byte
+	granule int64
+	serial  uint32
+	seq     uint32
+	segs    []byte // lacing values
+	data    []byte
+}
+
+// appendTo encodes the page and appends it to b.
+func (p *page) appendTo(b []byte) []byte {
+	start := len(b)
+	b = append(b, "OggS"...)
+	b = append(b, 0, p.flags)
+	b = binary.LittleEndian.AppendUint64(b, uint64(p.granule))
+	b = binary.LittleEndian.AppendUint32(b, p.serial)
+	b = binary.LittleEndian.AppendUint32(b, p.seq)
+	b = binary.LittleEndian.AppendUint32(b, 0) // checksum, set below
+	b = append(b, byte(len(p.segs)))
+	b = append(b, p.segs...)
+	b = append(b, p.data...)
+	binary.LittleEndian.PutUint32(b[start+22:], crc(0, b[start:]))
+	return b
+}
+
+// read reads and verifies the next page. It returns io.EOF only if there are no more pages.
+func (p *page) read(r io.Reader) error {
+	var hdr [pageHeaderSize + maxSegments]byte
+	if _, err := io.ReadFull(r, hdr[:pageHeaderSize]); err != nil {
+		return err
+	}
+	if string(hdr[:4]) != "OggS" || hdr[4] != 0 {
+		return ErrInvalidPage
+	}
+	p.flags = hdr[5]
+	p.granule = int64(binary.LittleEndian.Uint64(hdr[6:]))
+	p.serial = binary.LittleEndian.Uint32(hdr[14:])
+	p.seq = binary.LittleEndian.Uint32(hdr[18:])
+	sum := binary.LittleEndian.Uint32(hdr[22:])
+	nsegs := int(hdr[26])
+	if _, err := io.ReadFull(r, hdr[pageHeaderSize:pageHeaderSize+nsegs]); err != nil {
+		return noEOF(err)
+	}
+	p.segs = append(p.segs[:0], hdr[pageHeaderSize:pageHeaderSize+nsegs]...)
+	size := 0
+	for _, v := range p.segs {
+		size += int(v)
+	}
+	if cap(p.data) < size {
+		p.data = make([]byte, size)
+	}
+	p.data = p.data[:size]
+	if _, err := io.ReadFull(r, p.data); err != nil {
+		return noEOF(err)
+	}
+	clear(hdr[22:26])
+	if crc(crc(0, hdr[:pageHeaderSize+nsegs]), p.data) != sum {
+		return ErrChecksum
+	}
+	return nil
+}
+
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// Detect returns the codec of the Ogg stream by peeking at its first packet. The reader is not advanced.
+func Detect(r *bufio.Reader) (Codec, error) {
+	hdr, err := r.Peek(pageHeaderSize)
+	if err != nil {
+		return "", noEOF(err)
+	}
+	if string(hdr[:4]) != "OggS" {
+		return "", ErrInvalidPage
+	}
+	nsegs := int(hdr[26])
+	const magicSize = 8
+	data, err := r.Peek(pageHeaderSize + nsegs + magicSize)
+	if err != nil {
+		return "", noEOF(err)
+	}
+	magic := data[pageHeaderSize+nsegs:]
+	switch {
+	case bytes.Equal(magic, []byte(opusHeadMagic)):
+		return CodecOpus, nil
+	case bytes.Equal(magic[:7], []byte("\x01vorbis")):
+		return CodecVorbis, nil
+	}
+	return "", fmt.Errorf("ogg: unsupported codec")
+}
@@ -0,0 +1,222 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogg
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/opus"
+	"github.com/livekit/media-sdk/res/testdata"
+)
+
+type nopCloser struct {
+	bytes.Buffer
+}
+
+func (*nopCloser) Close() error { return nil }
+
+func packet(n int, b byte) []byte {
+	return bytes.Repeat([]byte{b}, n)
+}
+
+func TestCRC(t *testing.T) {
+	// CRC-32/MPEG-2 without the final inversion and with zero initial value
+	require.Equal(t, uint32(0x89a1897f), crc(0, []byte("123456789")))
+}
+
+func TestStream(t *testing.T) {
+	packets := [][]byte{
+		packet(10, 1),
+		packet(255, 2), // requires a zero-size terminating segment
+		packet(0, 3),
+		packet(2*maxSegments*maxSegmentSize, 4), // spans three pages
+		packet(300, 5),
+	}
+	var buf bytes.Buffer
+	w := NewWriter(&buf, 42)
+	for i, p := range packets {
+		require.NoError(t, w.WritePacket(p, int64(i+1)))
+	}
+	require.NoError(t, w.Close())
+	require.Error(t, w.WritePacket(packets[0], 10))
+
+	data := buf.Bytes()
+	r := NewReader(bytes.NewReader(data))
+	for _, p := range packets {
+		got, err := r.ReadPacket()
+		require.NoError(t, err)
+		require.Equal(t, p, got)
+	}
+	_, err := r.ReadPacket()
+	require.Equal(t, io.EOF, err)
+	require.EqualValues(t, len(packets), r.Granule())
+
+	var pages []page
+	pr := bytes.NewReader(data)
+	for {
+		var p page
+		err := p.read(pr)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.EqualValues(t, 42, p.serial)
+		require.EqualValues(t, len(pages), p.seq)
+		pages = append(pages, p)
+	}
+	require.Len(t, pages, 3)
+	require.Equal(t, byte(flagBOS), pages[0].flags)
+	require.EqualValues(t, 3, pages[0].granule)
+	require.Equal(t, byte(flagContinued), pages[1].flags)
+	require.EqualValues(t, -1, pages[1].granule) // no packets finish on the page
+	require.Equal(t, byte(flagContinued|flagEOS), pages[2].flags)
+	require.EqualValues(t, 5, pages[2].granule)
+
+	bad := bytes.Clone(data)
+	bad[len(bad)-1] ^= 0xff
+	r = NewReader(bytes.NewReader(bad))
+	_, err = r.ReadPacket()
+	require.NoError(t, err)
+	for err == nil {
+		_, err = r.ReadPacket()
+	}
+	require.ErrorIs(t, err, ErrChecksum)
+
+	r = NewReader(bytes.NewReader(data[:len(data)-10]))
+	_, err = r.ReadPacket()
+	for err == nil {
+		_, err = r.ReadPacket()
+	}
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestOpus(t *testing.T) {
+	_, err := NewOpusWriter(&nopCloser{}, 44100, 1)
+	require.Error(t, err)
+
+	const packets = 120
+	var buf nopCloser
+	w, err := NewOpusWriter(&buf, 16000, 2)
+	require.NoError(t, err)
+	for i := range packets {
+		// SILK NB, 20 ms, single frame
+		require.NoError(t, w.WriteSample(opus.Sample{1 << 3, byte(i)}))
+	}
+	require.Error(t, w.WriteSample(nil))
+	require.NoError(t, w.Close())
+
+	data := buf.Bytes()
+	codec, err := Detect(bufio.NewReader(bytes.NewReader(data)))
+	require.NoError(t, err)
+	require.Equal(t, CodecOpus, codec)
+
+	r, err := NewOpusReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, 2, r.Channels())
+	require.Equal(t, 16000, r.SampleRate())
+	require.Equal(t, OpusPreSkip, r.PreSkip())
+
+	pkt := make(opus.Sample, 2)
+	for i := range packets {
+		if i == 1 {
+			// packet is kept until it's read with a large enough buffer
+			_, err = r.ReadSample(pkt[:1])
+			require.ErrorIs(t, err, io.ErrShortBuffer)
+		}
+		n, err := r.ReadSample(pkt)
+		require.NoError(t, err)
+		require.Equal(t, opus.Sample{1 << 3, byte(i)}, pkt[:n])
+	}
+	_, err = r.ReadSample(pkt)
+	require.Equal(t, io.EOF, err)
+	require.EqualValues(t, OpusPreSkip+packets*960, r.Granule())
+
+	_, err = NewOpusReader(bytes.NewReader(testdata.TestAudioOgg))
+	require.ErrorIs(t, err, ErrNotOpus)
+	_, err = NewOpusReader(bytes.NewReader(data[:30]))
+	require.Error(t, err)
+}
+
+func TestVorbis(t *testing.T) {
+	codec, err := Detect(bufio.NewReader(bytes.NewReader(testdata.TestAudioOgg)))
+	require.NoError(t, err)
+	require.Equal(t, CodecVorbis, codec)
+
+	r, err := NewVorbisReader(bytes.NewReader(testdata.TestAudioOgg))
+	require.NoError(t, err)
+	require.Equal(t, 48000, r.SampleRate())
+	require.Equal(t, 1, r.Channels())
+	var total int
+	buf := make(media.PCM16Sample, 960)
+	for {
+		n, err := r.ReadSample(buf)
+		total += n
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+	}
+	require.NotZero(t, total)
+
+	_, err = NewVorbisReader(bytes.NewReader(nil))
+	require.Error(t, err)
+
+	// corrupted audio packets must not panic
+	bad := bytes.Clone(testdata.TestAudioOgg)
+	for i := len(bad) / 2; i < len(bad); i += 97 {
+		bad[i] ^= 0x5a
+	}
+	r, err = NewVorbisReader(bytes.NewReader(bad))
+	require.NoError(t, err)
+	for err == nil {
+		_, err = r.ReadSample(buf)
+	}
+	require.Error(t, err)
+}
+
+func TestDumpWriter(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "dump")
+	var frames sampleWriter
+	w := media.DumpWriter[opus.Sample](OpusFileExt, name, &frames)
+	require.NoError(t, w.WriteSample(opus.Sample{1 << 3, 0}))
+	require.NoError(t, w.Close())
+	require.Len(t, frames, 1)
+
+	f, err := os.Open(name + "_ar48000.opus")
+	require.NoError(t, err)
+	defer f.Close()
+	r, err := NewOpusReader(f)
+	require.NoError(t, err)
+	require.Equal(t, 1, r.Channels())
+}
+
+type sampleWriter []opus.Sample
+
+func (w *sampleWriter) String() string  { return "opus" }
+func (w *sampleWriter) SampleRate() int { return opus.MaxSampleRate }
+func (w *sampleWriter) Close() error    { return nil }
+
+func (w *sampleWriter) WriteSample(s opus.Sample) error {
+	*w = append(*w, s)
+	return nil
+}
@@ -0,0 +1,266 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogg
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"time"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/opus"
+)
+
+const (
+	// OpusFileExt is the file extension of Ogg Opus files.
+	OpusFileExt = "opus"
+	// OpusSampleRate is the rate of granule positions in Ogg Opus, regardless of the input sample rate.
+	OpusSampleRate = 48000
+	// OpusPreSkip is the number of samples the decoder skips at the start of the stream.
+	// It matches the lookahead of libopus encoder (6.5 ms).
+	OpusPreSkip = 312
+
+	opusHeadMagic = "OpusHead"
+	opusTagsMagic = "OpusTags"
+	opusHeadSize  = 19
+	opusVendor    = "LiveKit"
+
+	// opusPageDur is the max duration of packets buffered before writing a page.
+	opusPageDur = time.Second
+)
+
+var ErrNotOpus = errors.New("ogg: not an Opus stream")
+
+func init() {
+	media.RegisterFileWriter(OpusFileExt, func(w io.WriteCloser, sampleRate int) media.WriteCloser[opus.Sample] {
+		o, err := NewOpusWriter(w, sampleRate, 1)
+		if err != nil {
+			_ = w.Close()
+			panic(err)
+		}
+		return o
+	})
+}
+
+var _ media.WriteCloser[opus.Sample] = (*OpusWriter)(nil)
+
+// NewOpusWriter creates an Ogg Opus file writer. Headers are written immediately.
+//
+// The sample rate is the rate of the original input, which is only informational for the decoder.
+// Granule positions are always derived from packet durations at 48 kHz.
+func NewOpusWriter(w io.WriteCloser, sampleRate, channels int) (*OpusWriter, error) {
+	switch sampleRate {
+	case 8000, 12000, 16000, 24000, 48000:
+	default:
+		return nil, fmt.Errorf("ogg: unsupported opus sample rate: %d", sampleRate)
+	}
+	if channels != 1 && channels != 2 {
+		return nil, fmt.Errorf("ogg: unsupported number of channels: %d", channels)
+	}
+	o := &OpusWriter{
+		w:          w,
+		s:          NewWriter(w, rand.Uint32()),
+		sampleRate: sampleRate,
+		channels:   channels,
+		granule:    OpusPreSkip, // granule positions include samples skipped by the decoder
+	}
+	if err := o.writeHeaders(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// OpusWriter writes Opus packets to an Ogg file. See NewOpusWriter.
+type OpusWriter struct {
+	w          io.WriteCloser
+	s          *Writer
+	sampleRate int
+	channels   int
+	granule    int64
+	pending    time.Duration
+	closed     bool
+}
+
+func (o *OpusWriter) writeHeaders() error {
+	head := make([]byte, 0, opusHeadSize)
+	head = append(head, opusHeadMagic...)
+	head = append(head, 1, byte(o.channels))
+	head = binary.LittleEndian.AppendUint16(head, OpusPreSkip)
+	head = binary.LittleEndian.AppendUint32(head, uint32(o.sampleRate))
+	head = binary.LittleEndian.AppendUint16(head, 0) // output gain
+	head = append(head, 0)                           // channel mapping family
+	if err := o.s.WritePacket(head, 0); err != nil {
+		return err
+	}
+	// headers must be on separate pages
+	if err := o.s.Flush(); err != nil {
+		return err
+	}
+	tags := make([]byte, 0, 8+4+len(opusVendor)+4)
+	tags = append(tags, opusTagsMagic...)
+	tags = binary.LittleEndian.AppendUint32(tags, uint32(len(opusVendor)))
+	tags = append(tags, opusVendor...)
+	tags = binary.LittleEndian.AppendUint32(tags, 0) // comments
+	if err := o.s.WritePacket(tags, 0); err != nil {
+		return err
+	}
+	return o.s.Flush()
+}
+
+func (o *OpusWriter) String() string {
+	return fmt.Sprintf("OggOpus(%d)", o.channels)
+}
+
+func (o *OpusWriter) SampleRate() int {
+	return o.sampleRate
+}
+
+// Channels returns the number of channels declared in the stream header.
+func (o *OpusWriter) Channels() int {
+	return o.channels
+}
+
+// WriteSample adds the packet to the current page. Pages are written when they cover about a second of audio.
+func (o *OpusWriter) WriteSample(sample opus.Sample) error {
+	if o.closed {
+		return errClosed
+	}
+	dur := sample.Duration()
+	if dur == 0 {
+		return fmt.Errorf("ogg: invalid opus packet")
+	}
+	o.granule += int64(dur * OpusSampleRate / time.Second)
+	if err := o.s.WritePacket(sample, o.granule); err != nil {
+		return err
+	}
+	o.pending += dur
+	if o.pending >= opusPageDur {
+		return o.Flush()
+	}
+	return nil
+}
+
+// Flush writes buffered packets as a page.
+func (o *OpusWriter) Flush() error {
+	o.pending = 0
+	return o.s.Flush()
+}
+
+// Close writes the last page and closes the underlying writer.
+func (o *OpusWriter) Close() error {
+	if o.closed {
+		return nil
+	}
+	o.closed = true
+	err := o.s.Close()
+	if err2 := o.w.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+var _ media.Reader[opus.Sample] = (*OpusReader)(nil)
+
+// NewOpusReader creates a streaming reader of Opus packets from an Ogg file. Headers are read immediately.
+func NewOpusReader(r io.Reader) (*OpusReader, error) {
+	o := &OpusReader{r: NewReader(r)}
+	if err := o.readHeaders(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// OpusReader reads Opus packets from an Ogg file. See NewOpusReader.
+type OpusReader struct {
+	r          *Reader
+	channels   int
+	preSkip    int
+	sampleRate int
+	pending    []byte // packet that didn't fit into the buffer
+}
+
+func (o *OpusReader) readHeaders() error {
+	head, err := o.r.ReadPacket()
+	if err != nil {
+		return noEOF(err)
+	}
+	if len(head) < opusHeadSize || string(head[:8]) != opusHeadMagic {
+		return ErrNotOpus
+	}
+	if head[8]>>4 != 0 {
+		return fmt.Errorf("ogg: unsupported opus version: %d", head[8])
+	}
+	o.channels = int(head[9])
+	o.preSkip = int(binary.LittleEndian.Uint16(head[10:]))
+	o.sampleRate = int(binary.LittleEndian.Uint32(head[12:]))
+	if mapping := head[18]; mapping != 0 {
+		return fmt.Errorf("ogg: unsupported opus channel mapping: %d", mapping)
+	}
+	if o.channels != 1 && o.channels != 2 {
+		return fmt.Errorf("ogg: invalid number of channels: %d", o.channels)
+	}
+	tags, err := o.r.ReadPacket()
+	if err != nil {
+		return noEOF(err)
+	}
+	if len(tags) < 8 || string(tags[:8]) != opusTagsMagic {
+		return fmt.Errorf("ogg: missing opus tags")
+	}
+	return nil
+}
+
+// Channels returns the number of channels in the stream.
+func (o *OpusReader) Channels() int {
+	return o.channels
+}
+
+// PreSkip returns the number of 48 kHz samples that must be discarded from the start of the decoded audio.
+func (o *OpusReader) PreSkip() int {
+	return o.preSkip
+}
+
+// SampleRate returns the sample rate of the original input, or zero if it is unknown.
+// Opus packets are always decoded at 48 kHz.
+func (o *OpusReader) SampleRate() int {
+	return o.sampleRate
+}
+
+// Granule returns the granule position of the last page read, in 48 kHz samples.
+func (o *OpusReader) Granule() int64 {
+	return o.r.Granule()
+}
+
+// ReadSample reads the next packet into buf. It returns io.EOF at the end of the stream.
+//
+// If buf is too small, io.ErrShortBuffer is returned and the packet is kept, so the call can be retried with a larger buffer.
+func (o *OpusReader) ReadSample(buf opus.Sample) (int, error) {
+	p := o.pending
+	for len(p) == 0 {
+		var err error
+		p, err = o.r.ReadPacket()
+		if err != nil {
+			return 0, err
+		}
+	}
+	if len(buf) < len(p) {
+		o.pending = p
+		return 0, io.ErrShortBuffer
+	}
+	o.pending = nil
+	return copy(buf, p), nil
+}
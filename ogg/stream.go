@@ -0,0 +1,205 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ogg
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+var errClosed = errors.New("ogg: stream closed")
+
+// NewWriter creates a writer of a single logical Ogg stream with a given serial number.
+// Packets are buffered until the page is full or Flush is called.
+func NewWriter(w io.Writer, serial uint32) *Writer {
+	return &Writer{w: w, serial: serial, page: page{granule: -1}}
+}
+
+// Writer splits packets into pages of a logical Ogg stream. See NewWriter.
+type Writer struct {
+	w       io.Writer
+	serial  uint32
+	seq     uint32
+	page    page
+	granule int64 // position of the last packet
+	closed  bool
+	buf     []byte
+}
+
+// WritePacket adds a packet to the stream. Granule is the position of the stream at the end of the packet.
+func (w *Writer) WritePacket(data []byte, granule int64) error {
+	if w.closed {
+		return errClosed
+	}
+	w.granule = granule
+	for {
+		n := min(len(data), maxSegmentSize)
+		w.page.segs = append(w.page.segs, byte(n))
+		w.page.data = append(w.page.data, data[:n]...)
+		data = data[n:]
+		last := n < maxSegmentSize
+		if last {
+			w.page.granule = granule
+		}
+		if len(w.page.segs) == maxSegments {
+			if err := w.writePage(0); err != nil {
+				return err
+			}
+			if !last {
+				w.page.flags |= flagContinued
+			}
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// Flush writes buffered packets as a page.
+func (w *Writer) Flush() error {
+	if w.closed {
+		return errClosed
+	}
+	if len(w.page.segs) == 0 {
+		return nil
+	}
+	return w.writePage(0)
+}
+
+func (w *Writer) writePage(flags byte) error {
+	p := &w.page
+	p.flags |= flags
+	if w.seq == 0 {
+		p.flags |= flagBOS
+	}
+	p.serial = w.serial
+	p.seq = w.seq
+	w.seq++
+	w.buf = p.appendTo(w.buf[:0])
+	_, err := w.w.Write(w.buf)
+	p.flags = 0
+	p.granule = -1 // until a packet finishes on the page
+	p.segs = p.segs[:0]
+	p.data = p.data[:0]
+	return err
+}
+
+// Close writes buffered packets and marks the end of the stream. It doesn't close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if len(w.page.segs) == 0 {
+		// empty end-of-stream page
+		w.page.granule = w.granule
+	}
+	return w.writePage(flagEOS)
+}
+
+// NewReader creates a reader of packets of the first logical stream in the Ogg file. Other streams are skipped.
+func NewReader(r io.Reader) *Reader {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Reader{r: br}
+}
+
+// Reader reads packets of a logical Ogg stream. See NewReader.
+type Reader struct {
+	r       *bufio.Reader
+	serial  uint32
+	started bool
+	page    page
+	seg     int    // next segment of the page
+	off     int    // offset of the next segment in page data
+	packet  []byte // packet being assembled
+	partial bool   // the packet continues on the next page
+	granule int64
+	eos     bool
+}
+
+// Granule returns the granule position of the last page read.
+func (r *Reader) Granule() int64 {
+	return r.granule
+}
+
+func (r *Reader) nextPage() error {
+	for {
+		if r.eos {
+			return io.EOF
+		}
+		if err := r.page.read(r.r); err != nil {
+			return err
+		}
+		if !r.started {
+			if r.page.flags&flagBOS == 0 {
+				return ErrInvalidPage
+			}
+			r.started = true
+			r.serial = r.page.serial
+		} else if r.page.serial != r.serial {
+			continue
+		}
+		r.seg, r.off = 0, 0
+		if r.page.flags&flagContinued == 0 {
+			// previous packet was not completed, drop it
+			r.partial = false
+			r.packet = r.packet[:0]
+		} else if !r.partial {
+			// skip the continuation of a packet we didn't see
+			for r.seg < len(r.page.segs) {
+				n := int(r.page.segs[r.seg])
+				r.seg++
+				r.off += n
+				if n < maxSegmentSize {
+					break
+				}
+			}
+		}
+		r.granule = r.page.granule
+		r.eos = r.page.flags&flagEOS != 0
+		return nil
+	}
+}
+
+// ReadPacket returns the next packet of the stream. The packet is only valid until the next call.
+// It returns io.EOF at the end of the stream.
+func (r *Reader) ReadPacket() ([]byte, error) {
+	if !r.partial {
+		r.packet = r.packet[:0]
+	}
+	for {
+		for r.seg < len(r.page.segs) {
+			n := int(r.page.segs[r.seg])
+			r.packet = append(r.packet, r.page.data[r.off:r.off+n]...)
+			r.seg++
+			r.off += n
+			if n < maxSegmentSize {
+				r.partial = false
+				return r.packet, nil
+			}
+			r.partial = true
+		}
+		if err := r.nextPage(); err != nil {
+			if err == io.EOF && r.partial {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+	}
+}
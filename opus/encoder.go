@@ -0,0 +1,240 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opus
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"unsafe"
+
+	"gopkg.in/hraban/opus.v2"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/media-sdk"
+)
+
+/*
+#cgo pkg-config: opus
+#include <opus.h>
+
+// opus_encoder_ctl is variadic, so it cannot be called from Go directly.
+static int lk_opus_encoder_set(OpusEncoder *st, int request, opus_int32 value) {
+	return opus_encoder_ctl(st, request, value);
+}
+*/
+import "C"
+
+// maxPacketSize is a recommended size of the output buffer of the encoder.
+const maxPacketSize = 4000
+
+// NewEncoder creates an Opus encoder that writes encoded frames to w.
+//
+// Input samples are buffered and encoded in frames of the configured duration,
+// so the size of input samples doesn't have to match the frame size.
+// Options can be changed at runtime, concurrently with WriteSample.
+func NewEncoder(w Writer, channels int, opts *EncoderOptions, logger logger.Logger) (*Encoder, error) {
+	if channels != 1 && channels != 2 {
+		return nil, fmt.Errorf("opus encoder only supports mono or stereo input")
+	}
+	var o EncoderOptions
+	if opts != nil {
+		o = *opts
+	}
+	e := &Encoder{
+		w:        w,
+		channels: channels,
+		mem:      make([]byte, C.opus_encoder_get_size(C.int(channels))),
+		buf:      make(Sample, maxPacketSize),
+		logger:   logger,
+	}
+	e.st = (*C.OpusEncoder)(unsafe.Pointer(&e.mem[0]))
+	if err := e.setOptions(o, true); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Encoder encodes PCM to Opus. See NewEncoder.
+type Encoder struct {
+	mu       sync.Mutex
+	w        Writer
+	channels int
+	mem      []byte // encoder state is allocated on Go heap
+	st       *C.OpusEncoder
+	opts     EncoderOptions
+	frame    int // frame size in samples, including all channels
+	pcm      media.PCM16Sample
+	buf      Sample
+	logger   logger.Logger
+}
+
+func (e *Encoder) String() string {
+	return fmt.Sprintf("OPUS(encode) -> %s", e.w)
+}
+
+func (e *Encoder) SampleRate() int {
+	return e.w.SampleRate()
+}
+
+// Options returns current options of the encoder.
+func (e *Encoder) Options() EncoderOptions {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.opts
+}
+
+// SetOptions changes options of the encoder. Buffered samples are kept, but the encoder state may be reset.
+func (e *Encoder) SetOptions(opts EncoderOptions) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	// Encoder must be reset to change the application, or to restore the default complexity.
+	reset := opts.Application != e.opts.Application || (opts.Complexity == 0 && e.opts.Complexity != 0)
+	return e.setOptions(opts, reset)
+}
+
+// SetBitrate changes the bitrate of the encoder. Zero selects the bitrate automatically.
+func (e *Encoder) SetBitrate(bitrate int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	opts := e.opts
+	opts.Bitrate = bitrate
+	return e.setOptions(opts, false)
+}
+
+// SetPacketLoss changes the expected packet loss percentage, for example, based on RTCP reports.
+func (e *Encoder) SetPacketLoss(perc int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	opts := e.opts
+	opts.PacketLoss = perc
+	return e.setOptions(opts, false)
+}
+
+func (e *Encoder) setOptions(o EncoderOptions, reset bool) error {
+	o.setDefaults()
+	if err := o.validate(); err != nil {
+		return err
+	}
+	if reset {
+		rate := C.opus_int32(e.w.SampleRate())
+		if res := C.opus_encoder_init(e.st, rate, C.int(e.channels), opusApplication(o.Application)); res != C.OPUS_OK {
+			return opus.Error(res)
+		}
+	}
+	bitrate := C.opus_int32(C.OPUS_AUTO)
+	if o.Bitrate > 0 {
+		bitrate = C.opus_int32(o.Bitrate)
+	}
+	vbr := 1
+	if o.CBR {
+		vbr = 0
+	}
+	fec := 0
+	if o.InbandFEC {
+		fec = 1
+	}
+	dtx := 0
+	if o.DTX {
+		dtx = 1
+	}
+	for _, ctl := range []struct {
+		req C.int
+		val C.opus_int32
+		set bool
+	}{
+		{C.OPUS_SET_BITRATE_REQUEST, bitrate, true},
+		{C.OPUS_SET_VBR_REQUEST, C.opus_int32(vbr), true},
+		{C.OPUS_SET_COMPLEXITY_REQUEST, C.opus_int32(o.Complexity), o.Complexity > 0},
+		{C.OPUS_SET_INBAND_FEC_REQUEST, C.opus_int32(fec), true},
+		{C.OPUS_SET_PACKET_LOSS_PERC_REQUEST, C.opus_int32(o.PacketLoss), true},
+		{C.OPUS_SET_DTX_REQUEST, C.opus_int32(dtx), true},
+		{C.OPUS_SET_MAX_BANDWIDTH_REQUEST, opusBandwidth(o.MaxBandwidth), true},
+	} {
+		if !ctl.set {
+			continue
+		}
+		if res := C.lk_opus_encoder_set(e.st, ctl.req, ctl.val); res != C.OPUS_OK {
+			return opus.Error(res)
+		}
+	}
+	e.opts = o
+	e.frame = int(time.Duration(e.w.SampleRate())*o.FrameDur/time.Second) * e.channels
+	return nil
+}
+
+func (e *Encoder) WriteSample(in media.PCM16Sample) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(in)%e.channels != 0 {
+		return fmt.Errorf("opus: input length must be a multiple of channels")
+	}
+	e.pcm = append(e.pcm, in...)
+	var last error
+	for len(e.pcm) >= e.frame {
+		if err := e.encodeFrame(e.pcm[:e.frame]); err != nil {
+			last = err
+		}
+		e.pcm = e.pcm[:copy(e.pcm, e.pcm[e.frame:])]
+	}
+	return last
+}
+
+// encodeFrame encodes and writes a single frame.
+// With DTX enabled, frames of silence are still written, so the receiver can keep the timing.
+func (e *Encoder) encodeFrame(pcm media.PCM16Sample) error {
+	n := C.opus_encode(
+		e.st,
+		(*C.opus_int16)(&pcm[0]),
+		C.int(len(pcm)/e.channels),
+		(*C.uchar)(&e.buf[0]),
+		C.opus_int32(len(e.buf)),
+	)
+	if n < 0 {
+		return opus.Error(n)
+	}
+	return e.w.WriteSample(e.buf[:n])
+}
+
+func (e *Encoder) Close() error {
+	return e.w.Close()
+}
+
+func opusApplication(app Application) C.int {
+	switch app {
+	case AppAudio:
+		return C.OPUS_APPLICATION_AUDIO
+	case AppLowDelay:
+		return C.OPUS_APPLICATION_RESTRICTED_LOWDELAY
+	default:
+		return C.OPUS_APPLICATION_VOIP
+	}
+}
+
+func opusBandwidth(bw Bandwidth) C.opus_int32 {
+	switch bw {
+	case Narrowband:
+		return C.OPUS_BANDWIDTH_NARROWBAND
+	case Mediumband:
+		return C.OPUS_BANDWIDTH_MEDIUMBAND
+	case Wideband:
+		return C.OPUS_BANDWIDTH_WIDEBAND
+	case SuperWideband:
+		return C.OPUS_BANDWIDTH_SUPERWIDEBAND
+	default:
+		return C.OPUS_BANDWIDTH_FULLBAND
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build cgo
+
+package opus
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/media-sdk"
+)
+
+type sampleWriter struct {
+	rate    int
+	samples []Sample
+}
+
+func (w *sampleWriter) String() string  { return "samples" }
+func (w *sampleWriter) SampleRate() int { return w.rate }
+func (w *sampleWriter) Close() error    { return nil }
+
+func (w *sampleWriter) WriteSample(s Sample) error {
+	w.samples = append(w.samples, slices.Clone(s))
+	return nil
+}
+
+func sine(rate int, dur time.Duration) media.PCM16Sample {
+	out := make(media.PCM16Sample, int(time.Duration(rate)*dur/time.Second))
+	for i := range out {
+		out[i] = int16(8000 * math.Sin(2*math.Pi*440*float64(i)/float64(rate)))
+	}
+	return out
+}
+
+func TestEncoderFrameDur(t *testing.T) {
+	const rate = 48000
+	pcm := sine(rate, 240*time.Millisecond)
+	for _, dur := range []time.Duration{10, 20, 40, 60} {
+		dur *= time.Millisecond
+		t.Run(dur.String(), func(t *testing.T) {
+			w := &sampleWriter{rate: rate}
+			enc, err := NewEncoder(w, 1, &EncoderOptions{FrameDur: dur}, logger.GetLogger())
+			require.NoError(t, err)
+			// input frames don't match the frame size
+			for i := 0; i < len(pcm); i += rate / 50 {
+				require.NoError(t, enc.WriteSample(pcm[i:i+rate/50]))
+			}
+			require.Len(t, w.samples, int(240*time.Millisecond/dur))
+			for _, s := range w.samples {
+				require.Equal(t, dur, s.Duration())
+			}
+
+			// decoder accepts long frames
+			var frames []media.PCM16Sample
+			dec, err := Decode(media.NewPCM16FrameWriter(&frames, rate), 1, logger.GetLogger())
+			require.NoError(t, err)
+			for _, s := range w.samples {
+				require.NoError(t, dec.WriteSample(s))
+			}
+			var n int
+			for _, f := range frames {
+				n += len(f)
+			}
+			require.Equal(t, len(pcm), n)
+		})
+	}
+}
+
+func TestEncoderOptionsRuntime(t *testing.T) {
+	const rate = 48000
+	pcm := sine(rate, time.Second)
+	size := func(enc *Encoder, w *sampleWriter) int {
+		w.samples = nil
+		require.NoError(t, enc.WriteSample(pcm))
+		var n int
+		for _, s := range w.samples {
+			n += len(s)
+		}
+		return n
+	}
+	w := &sampleWriter{rate: rate}
+	enc, err := NewEncoder(w, 1, &EncoderOptions{Bitrate: 64000, CBR: true}, logger.GetLogger())
+	require.NoError(t, err)
+	high := size(enc, w)
+	require.InDelta(t, 64000/8, high, 64000/8*0.1)
+
+	require.NoError(t, enc.SetBitrate(16000))
+	low := size(enc, w)
+	require.InDelta(t, 16000/8, low, 16000/8*0.1)
+
+	require.NoError(t, enc.SetPacketLoss(20))
+	require.Equal(t, 20, enc.Options().PacketLoss)
+
+	opts := enc.Options()
+	opts.Application = AppAudio
+	opts.FrameDur = 60 * time.Millisecond
+	opts.Complexity = 5
+	opts.InbandFEC = true
+	opts.DTX = true
+	require.NoError(t, enc.SetOptions(opts))
+	size(enc, w)
+	require.Equal(t, 60*time.Millisecond, w.samples[0].Duration())
+
+	require.Error(t, enc.SetOptions(EncoderOptions{FrameDur: 30 * time.Millisecond}))
+	require.Error(t, enc.SetBitrate(100))
+	require.Equal(t, opts.FrameDur, enc.Options().FrameDur)
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/livekit/media-sdk/rtp"
+)
+
+// Application is the intended application of the encoder.
+type Application int
+
+const (
+	// AppVoIP is optimized for voice.
+	AppVoIP Application = iota
+	// AppAudio is optimized for fidelity of non-voice signals, like music.
+	AppAudio
+	// AppLowDelay minimizes the coding delay, disabling voice-optimized modes.
+	AppLowDelay
+)
+
+func (a Application) String() string {
+	switch a {
+	case AppVoIP:
+		return "voip"
+	case AppAudio:
+		return "audio"
+	case AppLowDelay:
+		return "lowdelay"
+	default:
+		return fmt.Sprintf("Application(%d)", int(a))
+	}
+}
+
+const (
+	// MaxComplexity is the max computational complexity of the encoder.
+	MaxComplexity = 10
+	// DefaultFrameDur is a default duration of an encoded frame.
+	DefaultFrameDur = rtp.DefFrameDur
+)
+
+// fecPacketLoss is the expected packet loss percentage used when in-band FEC is enabled without setting the loss.
+// The encoder only adds FEC data if the expected loss is non-zero.
+const fecPacketLoss = 10
+
+// EncoderOptions configures the encoder. Zero values are replaced with defaults.
+type EncoderOptions struct {
+	// Application mode of the encoder.
+	Application Application
+	// Bitrate in bits per second. Zero lets the encoder select the bitrate automatically.
+	Bitrate int
+	// CBR enables constant bitrate. Variable bitrate is used by default.
+	CBR bool
+	// Complexity of the encoder from 1 to MaxComplexity. Zero uses the default of libopus.
+	Complexity int
+	// InbandFEC enables in-band forward error correction.
+	InbandFEC bool
+	// PacketLoss is the expected packet loss percentage. Higher values make the encoder more robust to the loss.
+	PacketLoss int
+	// DTX enables discontinuous transmission.
+	DTX bool
+	// MaxBandwidth limits the audio bandwidth. Zero allows the full band.
+	MaxBandwidth Bandwidth
+	// FrameDur is the duration of encoded frames: 10, 20, 40 or 60 ms. Default is DefaultFrameDur.
+	FrameDur time.Duration
+}
+
+func (o *EncoderOptions) setDefaults() {
+	if o.FrameDur == 0 {
+		o.FrameDur = DefaultFrameDur
+	}
+	if o.MaxBandwidth == 0 {
+		o.MaxBandwidth = Fullband
+	}
+	if o.InbandFEC && o.PacketLoss == 0 {
+		o.PacketLoss = fecPacketLoss
+	}
+}
+
+func (o *EncoderOptions) validate() error {
+	switch o.Application {
+	case AppVoIP, AppAudio, AppLowDelay:
+	default:
+		return fmt.Errorf("opus: invalid application: %v", o.Application)
+	}
+	if o.Bitrate != 0 && (o.Bitrate < MinBitrate || o.Bitrate > MaxBitrate) {
+		return fmt.Errorf("opus: invalid bitrate: %d", o.Bitrate)
+	}
+	if o.Complexity < 0 || o.Complexity > MaxComplexity {
+		return fmt.Errorf("opus: invalid complexity: %d", o.Complexity)
+	}
+	if o.PacketLoss < 0 || o.PacketLoss > 100 {
+		return fmt.Errorf("opus: invalid packet loss: %d", o.PacketLoss)
+	}
+	switch o.MaxBandwidth {
+	case Narrowband, Mediumband, Wideband, SuperWideband, Fullband:
+	default:
+		return fmt.Errorf("opus: invalid bandwidth: %d", o.MaxBandwidth)
+	}
+	switch o.FrameDur {
+	case 10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 60 * time.Millisecond:
+	default:
+		return fmt.Errorf("opus: invalid frame duration: %v", o.FrameDur)
+	}
+	return nil
+}
+
+// EncoderOptions returns encoder options for sending to the receiver with these parameters.
+func (p Params) EncoderOptions() EncoderOptions {
+	o := EncoderOptions{
+		Bitrate:      p.MaxAverageBitrate,
+		InbandFEC:    p.UseInbandFEC,
+		DTX:          p.UseDTX,
+		MaxBandwidth: p.MaxBandwidth(),
+	}
+	switch p.PTime {
+	case 10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 60 * time.Millisecond:
+		o.FrameDur = p.PTime
+	}
+	o.setDefaults()
+	return o
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk/rtp"
+)
+
+func TestSampleDuration(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		data Sample
+		exp  time.Duration
+	}{
+		{"empty", nil, 0},
+		{"silk 10ms", Sample{0 << 3}, 10 * time.Millisecond},
+		{"silk 60ms", Sample{3 << 3}, 60 * time.Millisecond},
+		{"hybrid 20ms", Sample{13 << 3}, 20 * time.Millisecond},
+		{"celt 2.5ms", Sample{16 << 3}, 2500 * time.Microsecond},
+		{"celt 20ms", Sample{31 << 3}, 20 * time.Millisecond},
+		{"two frames", Sample{1<<3 | 1}, 40 * time.Millisecond},
+		{"two frames vbr", Sample{31<<3 | 2}, 40 * time.Millisecond},
+		{"arbitrary frames", Sample{31<<3 | 3, 6}, 120 * time.Millisecond},
+		{"too long", Sample{3<<3 | 3, 3}, 0},
+		{"truncated", Sample{31<<3 | 3}, 0},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.exp, c.data.Duration())
+		})
+	}
+}
+
+func TestStreamTimestamps(t *testing.T) {
+	var buf rtp.Buffer
+	w := rtp.NewMediaStreamOut[Sample](rtp.NewSeqWriter(&buf).NewStream(101, MaxSampleRate), MaxSampleRate)
+	for _, s := range []Sample{
+		{1 << 3},  // 20 ms
+		{3 << 3},  // 60 ms
+		{31 << 3}, // 20 ms
+		{},        // unknown, default
+		{1 << 3},
+	} {
+		require.NoError(t, w.WriteSample(s))
+	}
+	var ts []uint32
+	for _, p := range buf {
+		ts = append(ts, p.Timestamp)
+	}
+	require.Equal(t, []uint32{0, 960, 3840, 4800, 5760}, ts)
+}
+
+func TestEncoderOptions(t *testing.T) {
+	var o EncoderOptions
+	o.setDefaults()
+	require.NoError(t, o.validate())
+	require.Equal(t, EncoderOptions{FrameDur: 20 * time.Millisecond, MaxBandwidth: Fullband}, o)
+
+	o = EncoderOptions{InbandFEC: true, FrameDur: 60 * time.Millisecond}
+	o.setDefaults()
+	require.NoError(t, o.validate())
+	require.Equal(t, fecPacketLoss, o.PacketLoss)
+
+	for _, o := range []EncoderOptions{
+		{Application: 5},
+		{Bitrate: 1000},
+		{Bitrate: 1000000},
+		{Complexity: 11},
+		{PacketLoss: -1},
+		{PacketLoss: 101},
+		{MaxBandwidth: 1},
+		{FrameDur: 30 * time.Millisecond},
+	} {
+		o.setDefaults()
+		require.Error(t, o.validate(), "%+v", o)
+	}
+
+	require.Equal(t, EncoderOptions{
+		Bitrate:      24000,
+		InbandFEC:    true,
+		PacketLoss:   fecPacketLoss,
+		DTX:          true,
+		MaxBandwidth: Wideband,
+		FrameDur:     40 * time.Millisecond,
+	}, Params{
+		MaxPlaybackRate:   16000,
+		UseInbandFEC:      true,
+		UseDTX:            true,
+		MaxAverageBitrate: 24000,
+		PTime:             40 * time.Millisecond,
+	}.EncoderOptions())
+
+	// unsupported ptime is ignored
+	require.Equal(t, 20*time.Millisecond, Params{PTime: 30 * time.Millisecond}.EncoderOptions().FrameDur)
+}
@@ -35,18 +35,84 @@ import (
 */
 import "C"
 
-type Sample []byte
+const SDPName = "opus/48000/2"
 
-func (s Sample) Size() int {
-	return len(s)
+// maxConcealDur is a max duration of the gap that is filled by the concealment.
+const maxConcealDur = 100 * time.Millisecond
+
+func init() {
+	media.RegisterCodec(newCodec(DefaultParams, Params{}))
+}
+
+func newCodec(local, remote Params) *codec {
+	c := &codec{local: local, remote: remote}
+	c.AudioCodec = rtp.NewAudioCodec(media.CodecInfo{
+		SDPName:    SDPName,
+		SampleRate: MaxSampleRate,
+		Priority:   10,
+		FileExt:    "opus",
+	}, c.decode, c.encode)
+	return c
+}
+
+// codec is an Opus RTP codec configured with format parameters negotiated in SDP.
+type codec struct {
+	rtp.AudioCodec
+	local  Params
+	remote Params
 }
 
-func (s Sample) CopyTo(dst []byte) (int, error) {
-	if len(dst) < len(s) {
-		return 0, io.ErrShortBuffer
+var _ rtp.FmtpCodec = (*codec)(nil)
+
+func (c *codec) Fmtp() map[string]string {
+	return c.local.Fmtp()
+}
+
+func (c *codec) WithFmtp(remote map[string]string) rtp.AudioCodec {
+	return newCodec(c.local, ParseParams(remote))
+}
+
+func (c *codec) decode(w media.PCM16Writer) media.WriteCloser[Sample] {
+	return &decoder{
+		w:              w,
+		targetChannels: 1,
+		lastChannels:   1,
+		noFEC:          !c.local.UseInbandFEC,
+		logger:         logger.GetLogger(),
+	}
+}
+
+func (c *codec) encode(w media.WriteCloser[Sample]) media.PCM16Writer {
+	log := logger.GetLogger()
+	opts := c.remote.EncoderOptions()
+	enc, err := NewEncoder(w, 1, &opts, log)
+	if err != nil {
+		log.Errorw("cannot create opus encoder", err)
+		return &failedEncoder{w: w, err: err}
 	}
-	n := copy(dst, s)
-	return n, nil
+	return enc
+}
+
+// failedEncoder returns an error of the encoder creation on each write.
+type failedEncoder struct {
+	w   Writer
+	err error
+}
+
+func (e *failedEncoder) String() string {
+	return fmt.Sprintf("OPUS(encode, %v) -> %s", e.err, e.w)
+}
+
+func (e *failedEncoder) SampleRate() int {
+	return e.w.SampleRate()
+}
+
+func (e *failedEncoder) WriteSample(media.PCM16Sample) error {
+	return e.err
+}
+
+func (e *failedEncoder) Close() error {
+	return e.w.Close()
 }
 
 type Writer = media.WriteCloser[Sample]
@@ -64,19 +130,13 @@ func Decode(w media.PCM16Writer, targetChannels int, logger logger.Logger) (Writ
 	}, nil
 }
 
+// Encode creates an encoder with default options. See NewEncoder.
 func Encode(w Writer, channels int, logger logger.Logger) (media.PCM16Writer, error) {
-	enc, err := opus.NewEncoder(w.SampleRate(), channels, opus.AppVoIP)
-	if err != nil {
-		return nil, err
-	}
-	return &encoder{
-		w:      w,
-		enc:    enc,
-		buf:    make([]byte, w.SampleRate()/rtp.DefFramesPerSec*channels),
-		logger: logger,
-	}, nil
+	return NewEncoder(w, channels, nil, logger)
 }
 
+var _ media.LossConcealer[Sample] = (*decoder)(nil)
+
 type decoder struct {
 	w      media.PCM16Writer
 	dec    *opus.Decoder
@@ -86,6 +146,7 @@ type decoder struct {
 
 	targetChannels int
 	lastChannels   int
+	noFEC          bool // do not recover lost frames from in-band FEC data
 
 	successiveErrorCount int
 }
@@ -116,28 +177,68 @@ func (d *decoder) WriteSample(in Sample) error {
 	}
 	d.successiveErrorCount = 0
 
-	returnData := d.buf[:n*channels]
+	return d.writeDecoded(d.buf[:n*channels], channels)
+}
+
+// ConcealLoss generates a replacement for lost frames with Opus PLC.
+// If the next frame is available, it is used to recover the last lost frame from in-band FEC data.
+func (d *decoder) ConcealLoss(frames int, next Sample) error {
+	if d.dec == nil || frames <= 0 {
+		return nil // no signal yet
+	}
+	n, err := d.dec.LastPacketDuration()
+	if err != nil || n <= 0 {
+		return err
+	}
+	if maxFrames := d.w.SampleRate() * int(maxConcealDur/time.Millisecond) / 1000 / n; frames > maxFrames {
+		frames = max(maxFrames, 1)
+	}
+	channels := d.lastChannels
+	if len(d.buf) < n*channels {
+		d.buf = make(media.PCM16Sample, n*channels)
+	}
+	pcm := d.buf[: n*channels : n*channels] // decoder uses buffer capacity to determine the duration
+	for i := range frames {
+		if i == frames-1 && !d.noFEC && len(next) != 0 && d.nextChannels(next) == channels {
+			err = d.dec.DecodeFEC(next, pcm)
+		} else {
+			err = d.dec.DecodePLC(pcm)
+		}
+		if err != nil {
+			return err
+		}
+		if err = d.writeDecoded(pcm, channels); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *decoder) writeDecoded(data media.PCM16Sample, channels int) error {
 	if channels < d.targetChannels {
-		n2 := len(returnData) * 2
+		n2 := len(data) * 2
 		if len(d.buf2) < n2 {
 			d.buf2 = make(media.PCM16Sample, n2)
 		}
-		media.MonoToStereo(d.buf2, returnData)
-		returnData = d.buf2[:n2]
+		media.MonoToStereo(d.buf2, data)
+		data = d.buf2[:n2]
 	} else if channels > d.targetChannels {
-		n2 := len(returnData) / 2
+		n2 := len(data) / 2
 		if len(d.buf2) < n2 {
 			d.buf2 = make(media.PCM16Sample, n2)
 		}
-		media.StereoToMono(d.buf2, returnData)
-		returnData = d.buf2[:n2]
+		media.StereoToMono(d.buf2, data)
+		data = d.buf2[:n2]
 	}
+	return d.w.WriteSample(data)
+}
 
-	return d.w.WriteSample(returnData)
+func (d *decoder) nextChannels(in Sample) int {
+	return int(C.opus_packet_get_nb_channels((*C.uchar)(&in[0])))
 }
 
 func (d *decoder) resetForSample(in Sample) (int, error) {
-	channels := int(C.opus_packet_get_nb_channels((*C.uchar)(&in[0])))
+	channels := d.nextChannels(in)
 
 	if d.dec == nil || d.lastChannels != channels {
 		dec, err := opus.NewDecoder(d.w.SampleRate(), channels)
@@ -147,7 +248,7 @@ func (d *decoder) resetForSample(in Sample) (int, error) {
 		}
 		d.dec = dec
 
-		d.buf = make([]int16, d.w.SampleRate()/rtp.DefFramesPerSec*channels)
+		d.buf = make([]int16, d.w.SampleRate()*int(MaxPacketDur/time.Millisecond)/1000*channels)
 		d.lastChannels = channels
 	}
 
@@ -158,33 +259,18 @@ func (d *decoder) Close() error {
 	return d.w.Close()
 }
 
-type encoder struct {
-	w      Writer
-	enc    *opus.Encoder
-	buf    Sample
-	logger logger.Logger
-}
-
-func (e *encoder) String() string {
-	return fmt.Sprintf("OPUS(encode) -> %s", e.w)
-}
-
-func (e *encoder) SampleRate() int {
-	return e.w.SampleRate()
+func NewWebmWriter(w io.WriteCloser, sampleRate int, channels int, sampleDur time.Duration) media.WriteCloser[Sample] {
+	return webm.NewWriter[Sample](w, webm.CodecOpus, channels, sampleRate, sampleDur)
 }
 
-func (e *encoder) WriteSample(in media.PCM16Sample) error {
-	n, err := e.enc.Encode(in, e.buf)
-	if err != nil {
-		return err
+// NewWebmReader creates a reader of Opus packets from a WebM track.
+func NewWebmReader(r *webm.Reader, track uint64) (*webm.TrackReader[Sample], error) {
+	t, ok := r.Track(track)
+	if !ok {
+		return nil, webm.ErrNoTrack
 	}
-	return e.w.WriteSample(e.buf[:n])
-}
-
-func (e *encoder) Close() error {
-	return e.w.Close()
-}
-
-func NewWebmWriter(w io.WriteCloser, sampleRate int, channels int, sampleDur time.Duration) media.WriteCloser[Sample] {
-	return webm.NewWriter[Sample](w, "A_OPUS", channels, sampleRate, sampleDur)
+	if t.CodecID != webm.CodecOpus {
+		return nil, fmt.Errorf("%w: %s", webm.ErrUnknownFormat, t.CodecID)
+	}
+	return webm.NewTrackReader[Sample](r, track)
 }
@@ -0,0 +1,127 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opus
+
+import (
+	"strconv"
+	"time"
+)
+
+// Format parameter names, as defined in RFC 7587.
+const (
+	ParamMaxPlaybackRate   = "maxplaybackrate"
+	ParamUseInbandFEC      = "useinbandfec"
+	ParamUseDTX            = "usedtx"
+	ParamMaxAverageBitrate = "maxaveragebitrate"
+	ParamPTime             = "ptime"
+)
+
+// Limits of Opus parameters.
+const (
+	MinBitrate = 6000
+	MaxBitrate = 510000
+	// MaxSampleRate is the max sample rate of Opus, which is also its RTP clock rate.
+	MaxSampleRate = 48000
+)
+
+// Params are format parameters of the Opus RTP payload, as defined in RFC 7587.
+//
+// Parameters describe the receiver: each side declares what it prefers to receive,
+// so the parameters of the remote side configure the local encoder. Zero values mean defaults.
+//
+// Stereo parameters are not supported, since RTP audio is mono. Without them, the remote side sends mono by default,
+// and stereo packets are mixed down by the decoder.
+type Params struct {
+	// MaxPlaybackRate is the max sample rate the receiver is able to render.
+	MaxPlaybackRate int
+	// UseInbandFEC is set if the receiver is able to use in-band forward error correction.
+	UseInbandFEC bool
+	// UseDTX is set if the receiver prefers discontinuous transmission.
+	UseDTX bool
+	// MaxAverageBitrate is the max average bitrate in bits per second the receiver wants to receive.
+	MaxAverageBitrate int
+	// PTime is the preferred duration of media in a packet.
+	PTime time.Duration
+}
+
+// DefaultParams are format parameters offered for Opus.
+var DefaultParams = Params{
+	UseInbandFEC: true,
+}
+
+// ParseParams parses format parameters from SDP. Unknown and invalid parameters are ignored.
+func ParseParams(fmtp map[string]string) Params {
+	var p Params
+	if v, err := strconv.Atoi(fmtp[ParamMaxPlaybackRate]); err == nil && v > 0 {
+		p.MaxPlaybackRate = min(v, MaxSampleRate)
+	}
+	p.UseInbandFEC = fmtp[ParamUseInbandFEC] == "1"
+	p.UseDTX = fmtp[ParamUseDTX] == "1"
+	if v, err := strconv.Atoi(fmtp[ParamMaxAverageBitrate]); err == nil && v > 0 {
+		p.MaxAverageBitrate = max(min(v, MaxBitrate), MinBitrate)
+	}
+	if v, err := strconv.Atoi(fmtp[ParamPTime]); err == nil && v > 0 {
+		p.PTime = time.Duration(v) * time.Millisecond
+	}
+	return p
+}
+
+// Fmtp returns format parameters for SDP. Parameters with default values are omitted.
+func (p Params) Fmtp() map[string]string {
+	fmtp := make(map[string]string)
+	if p.MaxPlaybackRate > 0 && p.MaxPlaybackRate < MaxSampleRate {
+		fmtp[ParamMaxPlaybackRate] = strconv.Itoa(p.MaxPlaybackRate)
+	}
+	if p.UseInbandFEC {
+		fmtp[ParamUseInbandFEC] = "1"
+	}
+	if p.UseDTX {
+		fmtp[ParamUseDTX] = "1"
+	}
+	if p.MaxAverageBitrate > 0 {
+		fmtp[ParamMaxAverageBitrate] = strconv.Itoa(p.MaxAverageBitrate)
+	}
+	if p.PTime > 0 {
+		fmtp[ParamPTime] = strconv.Itoa(int(p.PTime / time.Millisecond))
+	}
+	return fmtp
+}
+
+// Bandwidth is an audio bandwidth of Opus.
+type Bandwidth int
+
+const (
+	Narrowband    Bandwidth = 4000
+	Mediumband    Bandwidth = 6000
+	Wideband      Bandwidth = 8000
+	SuperWideband Bandwidth = 12000
+	Fullband      Bandwidth = 20000
+)
+
+// MaxBandwidth returns the max audio bandwidth useful for the receiver, based on MaxPlaybackRate.
+func (p Params) MaxBandwidth() Bandwidth {
+	switch rate := p.MaxPlaybackRate; {
+	case rate <= 0 || rate > 24000:
+		return Fullband
+	case rate > 16000:
+		return SuperWideband
+	case rate > 12000:
+		return Wideband
+	case rate > 8000:
+		return Mediumband
+	default:
+		return Narrowband
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParams(t *testing.T) {
+	p := ParseParams(map[string]string{
+		"maxplaybackrate":   "16000",
+		"stereo":            "1",
+		"sprop-stereo":      "0",
+		"useinbandfec":      "1",
+		"usedtx":            "1",
+		"maxaveragebitrate": "24000",
+		"ptime":             "40",
+		"unknown":           "x",
+	})
+	require.Equal(t, Params{
+		MaxPlaybackRate:   16000,
+		UseInbandFEC:      true,
+		UseDTX:            true,
+		MaxAverageBitrate: 24000,
+		PTime:             40 * time.Millisecond,
+	}, p)
+	require.Equal(t, Wideband, p.MaxBandwidth())
+	require.Equal(t, map[string]string{
+		"maxplaybackrate":   "16000",
+		"useinbandfec":      "1",
+		"usedtx":            "1",
+		"maxaveragebitrate": "24000",
+		"ptime":             "40",
+	}, p.Fmtp())
+
+	// defaults and invalid values
+	p = ParseParams(map[string]string{
+		"maxplaybackrate":   "96000",
+		"maxaveragebitrate": "100",
+		"ptime":             "abc",
+	})
+	require.Equal(t, Params{MaxPlaybackRate: 48000, MaxAverageBitrate: MinBitrate}, p)
+	require.Equal(t, Fullband, p.MaxBandwidth())
+	require.Equal(t, map[string]string{"maxaveragebitrate": "6000"}, p.Fmtp())
+
+	require.Equal(t, Params{}, ParseParams(nil))
+	require.Empty(t, Params{}.Fmtp())
+	require.Equal(t, map[string]string{"useinbandfec": "1"}, DefaultParams.Fmtp())
+
+	for rate, bw := range map[int]Bandwidth{
+		8000:  Narrowband,
+		12000: Mediumband,
+		16000: Wideband,
+		24000: SuperWideband,
+		48000: Fullband,
+	} {
+		require.Equal(t, bw, Params{MaxPlaybackRate: rate}.MaxBandwidth(), "rate %d", rate)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opus
+
+import (
+	"io"
+	"time"
+)
+
+// MaxPacketDur is the max duration of audio in a single Opus packet.
+const MaxPacketDur = 120 * time.Millisecond
+
+type Sample []byte
+
+func (s Sample) Size() int {
+	return len(s)
+}
+
+func (s Sample) CopyTo(dst []byte) (int, error) {
+	if len(dst) < len(s) {
+		return 0, io.ErrShortBuffer
+	}
+	n := copy(dst, s)
+	return n, nil
+}
+
+// frameDurs is a duration of a single frame for each TOC configuration, in 1/10 ms (RFC 6716, section 3.1).
+var frameDurs = [32]uint16{
+	// SILK-only
+	100, 200, 400, 600,
+	100, 200, 400, 600,
+	100, 200, 400, 600,
+	// Hybrid
+	100, 200,
+	100, 200,
+	// CELT-only
+	25, 50, 100, 200,
+	25, 50, 100, 200,
+	25, 50, 100, 200,
+	25, 50, 100, 200,
+}
+
+// Duration returns the duration of audio in the packet, as encoded in the TOC byte.
+// It returns zero for an empty or malformed packet.
+func (s Sample) Duration() time.Duration {
+	if len(s) == 0 {
+		return 0
+	}
+	toc := s[0]
+	frames := 1
+	switch toc & 0x3 {
+	case 1, 2:
+		frames = 2
+	case 3:
+		if len(s) < 2 {
+			return 0
+		}
+		frames = int(s[1] & 0x3f)
+	}
+	dur := time.Duration(frames) * time.Duration(frameDurs[toc>>3]) * 100 * time.Microsecond
+	if dur > MaxPacketDur {
+		return 0
+	}
+	return dur
+}
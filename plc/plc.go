@@ -0,0 +1,338 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plc implements packet loss concealment for PCM audio.
+package plc
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/livekit/media-sdk"
+)
+
+// Parameters from ITU-T G.711 Appendix I, defined for 8 kHz.
+const (
+	pitchMin     = 40  // minimal pitch period (200 Hz)
+	pitchMax     = 120 // maximal pitch period (66 Hz)
+	corrLen      = 160 // length of the correlation window
+	corrMinPower = 250 // minimal power of the correlation window
+	frameSize    = 80  // size of the processing frame (10 ms)
+	eOverlapIncr = 32  // overlap increment for each erased frame
+	decimation   = 2   // decimation factor for the coarse pitch search
+
+	attenFactor = 0.2 // attenuation per erased frame
+
+	// maxEraseFrames is a number of frames after which the output is silenced.
+	maxEraseFrames = 6
+
+	// MaxConcealDur is a max duration of the gap that is filled by the concealment.
+	MaxConcealDur = 100 * time.Millisecond
+)
+
+var _ media.LossConcealer[media.PCM16Sample] = (*Writer)(nil)
+
+// NewWriter creates a PCM writer that conceals lost frames with pitch waveform replication,
+// as described in ITU-T G.711 Appendix I.
+//
+// The algorithm is defined for 8 kHz, other sample rates are supported by scaling its parameters.
+// Output is delayed by 1/4 of max pitch period (3.75 ms), which is required to smooth the start of the erasure.
+func NewWriter(w media.PCM16Writer) *Writer {
+	rate := w.SampleRate()
+	if rate <= 0 {
+		panic("invalid sample rate")
+	}
+	scale := func(v int) int {
+		return v * rate / 8000
+	}
+	c := &Writer{
+		w:            w,
+		pitchMin:     scale(pitchMin),
+		pitchMax:     scale(pitchMax),
+		corrLen:      scale(corrLen),
+		frameSize:    scale(frameSize),
+		eOverlapIncr: scale(eOverlapIncr),
+		corrMinPower: float64(scale(corrMinPower)),
+	}
+	c.overlapMax = c.pitchMax / 4
+	c.history = make([]int16, c.pitchMax*3+c.overlapMax)
+	c.pitchBuf = make([]float64, len(c.history))
+	c.lastQ = make([]float64, c.overlapMax)
+	c.tmp = make(media.PCM16Sample, c.overlapMax)
+	return c
+}
+
+// Writer conceals lost PCM frames.
+type Writer struct {
+	w media.PCM16Writer
+
+	pitchMin     int
+	pitchMax     int
+	corrLen      int
+	frameSize    int
+	eOverlapIncr int
+	overlapMax   int
+	corrMinPower float64
+
+	history  []int16   // history of the signal; last overlapMax samples are not written yet
+	pitchBuf []float64 // history copy used for synthesis
+	lastQ    []float64 // saved last 1/4 of the pitch period
+	tmp      media.PCM16Sample
+
+	eraseCnt   int // number of erased frames
+	pitch      int // detected pitch period
+	pitchBLen  int // length of the pitch buffer used for synthesis
+	pitchStart int // start of the pitch buffer
+	pOffset    int // current offset in the pitch buffer
+	pOverlap   int // overlap length, 1/4 of the pitch
+
+	lastFrame int               // size of the last written frame
+	pending   media.PCM16Sample // samples not yet processed
+	out       media.PCM16Sample // output buffer
+}
+
+func (c *Writer) String() string {
+	return fmt.Sprintf("PLC(%d) -> %s", c.w.SampleRate(), c.w)
+}
+
+func (c *Writer) SampleRate() int {
+	return c.w.SampleRate()
+}
+
+func (c *Writer) Close() error {
+	return c.w.Close()
+}
+
+// WriteSample writes a good frame.
+func (c *Writer) WriteSample(in media.PCM16Sample) error {
+	if len(in) == 0 {
+		return nil
+	}
+	c.lastFrame = len(in)
+	c.pending = append(c.pending, in...)
+	c.out = c.out[:0]
+	for len(c.pending) >= c.frameSize {
+		c.out = append(c.out, c.pending[:c.frameSize]...)
+		c.addToHistory(c.out[len(c.out)-c.frameSize:])
+		c.pending = c.pending[:copy(c.pending, c.pending[c.frameSize:])]
+	}
+	if len(c.out) == 0 {
+		return nil
+	}
+	return c.w.WriteSample(c.out)
+}
+
+// ConcealLoss generates a replacement for lost frames. Frame size is assumed to be the same as the last written frame.
+//
+// The signal is synthesized in 10 ms chunks, thus frame sizes which are not a multiple of 10 ms are rounded up.
+func (c *Writer) ConcealLoss(frames int, _ media.PCM16Sample) error {
+	if c.lastFrame == 0 || frames <= 0 {
+		return nil // no signal yet
+	}
+	if maxFrames := int(time.Duration(c.SampleRate())*MaxConcealDur/time.Second) / c.lastFrame; frames > maxFrames {
+		frames = max(maxFrames, 1)
+	}
+	chunks := (c.lastFrame + c.frameSize - 1) / c.frameSize
+	for range frames {
+		c.out = c.out[:0]
+		for range chunks {
+			start := len(c.out)
+			c.out = append(c.out, make(media.PCM16Sample, c.frameSize)...)
+			c.doErasure(c.out[start:])
+		}
+		if err := c.w.WriteSample(c.out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addToHistory processes a good frame in place.
+func (c *Writer) addToHistory(s media.PCM16Sample) {
+	if c.eraseCnt != 0 {
+		// Longer erasures require longer overlaps to smooth the transition between the synthetic and real signal.
+		olen := min(c.pOverlap+(c.eraseCnt-1)*c.eOverlapIncr, c.frameSize)
+		buf := make(media.PCM16Sample, olen)
+		c.getSynth(buf)
+		c.overlapAddAtEnd(s, buf)
+		c.eraseCnt = 0
+	}
+	c.saveSpeech(s)
+}
+
+// doErasure synthesizes a lost frame.
+func (c *Writer) doErasure(out media.PCM16Sample) {
+	switch {
+	case c.eraseCnt == 0:
+		// Start of the erasure. Find the pitch in the history and start replicating the last pitch period.
+		for i, v := range c.history {
+			c.pitchBuf[i] = float64(v)
+		}
+		end := len(c.pitchBuf)
+		c.pitch = c.findPitch()
+		c.pOverlap = c.pitch / 4
+		copy(c.lastQ, c.pitchBuf[end-c.pOverlap:end])
+		c.pOffset = 0
+		c.pitchBLen = c.pitch
+		c.pitchStart = end - c.pitchBLen
+		overlapAdd(c.lastQ[:c.pOverlap], c.pitchBuf[c.pitchStart-c.pOverlap:], c.pitchBuf[end-c.pOverlap:end])
+		// Update the last 1/4 of the wavelength in the history, since it was not written yet.
+		for i, v := range c.pitchBuf[end-c.pOverlap : end] {
+			c.history[len(c.history)-c.pOverlap+i] = int16(v)
+		}
+		c.getSynth(out)
+	case c.eraseCnt == 1 || c.eraseCnt == 2:
+		// Add another pitch period to the buffer to avoid buzzy sound.
+		tmp := c.tmp[:c.pOverlap]
+		saveOffset := c.pOffset
+		c.getSynth(tmp)
+		c.pOffset = saveOffset
+		for c.pOffset > c.pitch {
+			c.pOffset -= c.pitch
+		}
+		end := len(c.pitchBuf)
+		c.pitchBLen += c.pitch
+		c.pitchStart = end - c.pitchBLen
+		overlapAdd(c.lastQ[:c.pOverlap], c.pitchBuf[c.pitchStart-c.pOverlap:], c.pitchBuf[end-c.pOverlap:end])
+		c.getSynth(out)
+		overlapAddInt(tmp, out[:c.pOverlap], out[:c.pOverlap])
+		c.scale(out)
+	case c.eraseCnt >= maxEraseFrames:
+		out.Clear()
+	default:
+		c.getSynth(out)
+		c.scale(out)
+	}
+	c.eraseCnt++
+	c.saveSpeech(out)
+}
+
+// saveSpeech adds the frame to the history and replaces it with a delayed frame.
+func (c *Writer) saveSpeech(s media.PCM16Sample) {
+	h := c.history
+	copy(h, h[c.frameSize:])
+	copy(h[len(h)-c.frameSize:], s)
+	copy(s, h[len(h)-c.frameSize-c.overlapMax:])
+}
+
+// getSynth reads synthesized signal from the pitch buffer.
+func (c *Writer) getSynth(out media.PCM16Sample) {
+	for len(out) > 0 {
+		cnt := min(c.pitchBLen-c.pOffset, len(out))
+		for i, v := range c.pitchBuf[c.pitchStart+c.pOffset : c.pitchStart+c.pOffset+cnt] {
+			out[i] = clamp16(v)
+		}
+		c.pOffset += cnt
+		if c.pOffset == c.pitchBLen {
+			c.pOffset = 0
+		}
+		out = out[cnt:]
+	}
+}
+
+// scale attenuates the synthesized signal linearly, by attenFactor per frame.
+func (c *Writer) scale(out media.PCM16Sample) {
+	g := 1 - float64(c.eraseCnt-1)*attenFactor
+	incr := attenFactor / float64(len(out))
+	for i, v := range out {
+		out[i] = clamp16(float64(v) * max(g, 0))
+		g -= incr
+	}
+}
+
+// overlapAddAtEnd mixes synthesized signal f into the start of the good frame s.
+func (c *Writer) overlapAddAtEnd(s, f media.PCM16Sample) {
+	gain := max(1-float64(c.eraseCnt-1)*attenFactor, 0)
+	incr := 1 / float64(len(f))
+	incrg := incr * gain
+	lw := (1 - incr) * gain
+	rw := incr
+	for i := range f {
+		s[i] = clamp16(lw*float64(f[i]) + rw*float64(s[i]))
+		lw -= incrg
+		rw += incr
+	}
+}
+
+// findPitch estimates the pitch period with normalized cross-correlation of the last corrLen samples.
+func (c *Writer) findPitch() int {
+	end := len(c.pitchBuf)
+	l := c.pitchBuf[end-c.corrLen:]
+	r := c.pitchBuf[end-c.corrLen-c.pitchMax:]
+	diff := c.pitchMax - c.pitchMin
+
+	search := func(from, to, step int, greaterOrEqual bool) int {
+		rp := r[from:]
+		var energy float64
+		for i := 0; i < c.corrLen; i += step {
+			energy += rp[i] * rp[i]
+		}
+		best, bestCorr := from, math.Inf(-1)
+		for j := from; ; {
+			var corr float64
+			for i := 0; i < c.corrLen; i += step {
+				corr += rp[i] * l[i]
+			}
+			corr /= math.Sqrt(max(energy, c.corrMinPower))
+			if corr > bestCorr || (greaterOrEqual && corr == bestCorr) {
+				best, bestCorr = j, corr
+			}
+			if j+step > to {
+				break
+			}
+			energy -= rp[0] * rp[0]
+			energy += rp[c.corrLen] * rp[c.corrLen]
+			rp = rp[step:]
+			j += step
+		}
+		return best
+	}
+	// coarse search on the decimated signal
+	best := search(0, diff, decimation, true)
+	// fine search around the best match
+	best = search(max(best-(decimation-1), 0), min(best+(decimation-1), diff), 1, false)
+	return c.pitchMax - best
+}
+
+// overlapAdd cross-fades l into r and writes the result to o.
+func overlapAdd(l, r, o []float64) {
+	incr := 1 / float64(len(l))
+	lw, rw := 1-incr, incr
+	for i := range l {
+		o[i] = math.Max(math.Min(lw*l[i]+rw*r[i], math.MaxInt16), math.MinInt16)
+		lw -= incr
+		rw += incr
+	}
+}
+
+// overlapAddInt cross-fades l into r and writes the result to o.
+func overlapAddInt(l, r, o media.PCM16Sample) {
+	incr := 1 / float64(len(l))
+	lw, rw := 1-incr, incr
+	for i := range l {
+		o[i] = clamp16(lw*float64(l[i]) + rw*float64(r[i]))
+		lw -= incr
+		rw += incr
+	}
+}
+
+func clamp16(v float64) int16 {
+	if v > math.MaxInt16 {
+		return math.MaxInt16
+	} else if v < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(v)
+}
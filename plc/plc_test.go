@@ -0,0 +1,105 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plc
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+)
+
+func genSine(buf media.PCM16Sample, off, rate int, freq float64) {
+	for i := range buf {
+		buf[i] = int16(8000 * math.Sin(2*math.Pi*freq*float64(off+i)/float64(rate)))
+	}
+}
+
+func maxAbs(buf media.PCM16Sample) int {
+	v := 0
+	for _, s := range buf {
+		v = max(v, int(math.Abs(float64(s))))
+	}
+	return v
+}
+
+func TestPLC(t *testing.T) {
+	for _, rate := range []int{8000, 16000, 48000} {
+		t.Run(strconv.Itoa(rate), func(t *testing.T) {
+			const freq = 200
+			frame := rate / 50
+			var frames []media.PCM16Sample
+			w := NewWriter(media.NewPCM16FrameWriter(&frames, rate))
+
+			buf := make(media.PCM16Sample, frame)
+			off := 0
+			for range 10 {
+				genSine(buf, off, rate, freq)
+				off += frame
+				require.NoError(t, w.WriteSample(buf))
+			}
+			require.Len(t, frames, 10)
+
+			// short loss is replaced with a similar signal
+			require.NoError(t, w.ConcealLoss(1, nil))
+			require.Len(t, frames, 11)
+			last := frames[len(frames)-1]
+			require.Len(t, last, frame)
+			require.Greater(t, maxAbs(last), 4000)
+
+			// signal is continuous: no large jumps between samples
+			var all media.PCM16Sample
+			for _, f := range frames {
+				all = append(all, f...)
+			}
+			maxStep := int(2*math.Pi*freq/float64(rate)*8000) * 3
+			for i := 1; i < len(all); i++ {
+				require.LessOrEqual(t, int(math.Abs(float64(all[i])-float64(all[i-1]))), maxStep, "sample %d", i)
+			}
+
+			// long loss fades out to silence
+			require.NoError(t, w.ConcealLoss(4, nil))
+			require.Len(t, frames, 15)
+			require.Zero(t, maxAbs(frames[len(frames)-1]))
+
+			// signal recovers after the loss
+			off += 5 * frame
+			for range 2 {
+				genSine(buf, off, rate, freq)
+				off += frame
+				require.NoError(t, w.WriteSample(buf))
+			}
+			require.Greater(t, maxAbs(frames[len(frames)-1]), 4000)
+		})
+	}
+}
+
+func TestPLCNoSignal(t *testing.T) {
+	var frames []media.PCM16Sample
+	w := NewWriter(media.NewPCM16FrameWriter(&frames, 8000))
+	require.NoError(t, w.ConcealLoss(3, nil))
+	require.Empty(t, frames)
+}
+
+func TestPLCMaxLoss(t *testing.T) {
+	var frames []media.PCM16Sample
+	w := NewWriter(media.NewPCM16FrameWriter(&frames, 8000))
+	require.NoError(t, w.WriteSample(make(media.PCM16Sample, 160)))
+	require.NoError(t, w.ConcealLoss(100, nil))
+	require.Len(t, frames, 1+5)
+}
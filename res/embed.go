@@ -20,9 +20,8 @@ import (
 	"fmt"
 	"io"
 
-	"github.com/jfreymuth/oggvorbis"
-
 	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/ogg"
 )
 
 //go:embed enter_pin.ogg
@@ -36,9 +35,11 @@ var WrongPinOgg []byte
 
 const SampleRate = 48000
 
+// ReadOggAudioFile decodes an embedded Ogg Vorbis file to frames. It panics on any error.
+// See ogg.NewVorbisReader for a streaming decoder.
 func ReadOggAudioFile(data []byte, sampleRate int, channels int) []media.PCM16Sample {
 	perFrame := sampleRate / media.DefFramesPerSec
-	r, err := oggvorbis.NewReader(bytes.NewReader(data))
+	r, err := ogg.NewVorbisReader(bytes.NewReader(data))
 	if err != nil {
 		panic(err)
 	}
@@ -51,16 +52,10 @@ func ReadOggAudioFile(data []byte, sampleRate int, channels int) []media.PCM16Sa
 	// Frames in the source file may be shorter,
 	// so we collect all samples and split them to frames again.
 	var samples media.PCM16Sample
-	buf := make([]float32, perFrame)
+	buf := make(media.PCM16Sample, perFrame)
 	for {
-		n, err := r.Read(buf)
-		if n != 0 {
-			frame := make(media.PCM16Sample, n)
-			for i := range frame {
-				frame[i] = int16(buf[i] * 0x7fff)
-			}
-			samples = append(samples, frame...)
-		}
+		n, err := r.ReadSample(buf)
+		samples = append(samples, buf[:n]...)
 		if err == io.EOF {
 			break
 		} else if err != nil {
@@ -20,6 +20,7 @@ import (
 	"sync/atomic"
 
 	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/plc"
 )
 
 var (
@@ -31,6 +32,18 @@ var (
 	codecByType [0xff]media.Codec
 )
 
+// PLCWriter is a PCM writer that can conceal lost frames.
+type PLCWriter interface {
+	media.PCM16Writer
+	media.LossConcealer[media.PCM16Sample]
+}
+
+// NewPLC creates a packet loss concealment stage for decoded audio.
+// It is used by DecodeRTP for codecs that cannot conceal the loss natively. Setting it to nil disables the concealment.
+var NewPLC = func(w media.PCM16Writer) PLCWriter {
+	return plc.NewWriter(w)
+}
+
 func init() {
 	media.OnRegister(func(c media.Codec) {
 		info := c.Info()
@@ -50,6 +63,15 @@ type AudioCodec interface {
 	DecodeRTP(w media.Writer[media.PCM16Sample], typ byte) Handler
 }
 
+// FmtpCodec is an optional interface for AudioCodec that has format parameters (fmtp) in SDP.
+type FmtpCodec interface {
+	AudioCodec
+	// Fmtp returns local format parameters of the codec, sent in SDP offers and answers.
+	Fmtp() map[string]string
+	// WithFmtp returns the codec configured with format parameters received from the remote side.
+	WithFmtp(remote map[string]string) AudioCodec
+}
+
 type AudioEncoder[S BytesFrame] interface {
 	AudioCodec
 	Decode(writer media.PCM16Writer) media.WriteCloser[S]
@@ -106,8 +128,24 @@ func (c *audioCodec[S]) EncodeRTP(w *Stream) media.PCM16Writer {
 	return c.encode(s)
 }
 
+// DecodeRTP creates an RTP handler that decodes the payload and writes PCM to w.
+//
+// The handler conceals packets lost in the jitter buffer (see HandleJitter). Decoders implementing media.LossConcealer
+// are used to conceal the loss natively, otherwise the decoded audio goes through a PLC stage created with NewPLC.
 func (c *audioCodec[S]) DecodeRTP(w media.Writer[media.PCM16Sample], typ byte) Handler {
-	s := c.decode(media.NopCloser(w))
+	// PLC stage can only be inserted after the decoder is created, once we know it cannot conceal the loss itself
+	out := &pcmSwitch{WriteCloser: media.NopCloser(w)}
+	s := c.decode(out)
+	var conceal func(frames int, next S) error
+	if dec, ok := s.(media.LossConcealer[S]); ok {
+		conceal = dec.ConcealLoss
+	} else if NewPLC != nil {
+		pcm := NewPLC(out.WriteCloser)
+		out.WriteCloser = pcm
+		conceal = func(frames int, _ S) error {
+			return pcm.ConcealLoss(frames, nil)
+		}
+	}
 	if mediaDumpToFile {
 		id := mediaID.Add(1)
 		name := fmt.Sprintf("sip_rtp_in_%d", id)
@@ -117,5 +155,23 @@ func (c *audioCodec[S]) DecodeRTP(w media.Writer[media.PCM16Sample], typ byte) H
 		}
 		s = media.DumpWriter[S](ext, name, media.NopCloser(s))
 	}
+	if conceal != nil {
+		s = &concealWriter[S]{WriteCloser: s, conceal: conceal}
+	}
 	return NewMediaStreamIn(s)
 }
+
+// concealWriter attaches loss concealment to a writer.
+type concealWriter[S BytesFrame] struct {
+	media.WriteCloser[S]
+	conceal func(frames int, next S) error
+}
+
+func (w *concealWriter[S]) ConcealLoss(frames int, next S) error {
+	return w.conceal(frames, next)
+}
+
+// pcmSwitch forwards decoded audio to a writer that can be replaced after the decoder is created.
+type pcmSwitch struct {
+	media.WriteCloser[media.PCM16Sample]
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtp
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+)
+
+type testFrame []byte
+
+func (f testFrame) Size() int { return len(f) }
+
+func (f testFrame) CopyTo(dst []byte) (int, error) {
+	return copy(dst, f), nil
+}
+
+// testDecoder writes one PCM sample per byte of the frame.
+type testDecoder struct {
+	w     media.PCM16Writer
+	lost  []int
+	plcOK bool
+}
+
+func (d *testDecoder) String() string  { return "test" }
+func (d *testDecoder) SampleRate() int { return d.w.SampleRate() }
+func (d *testDecoder) Close() error    { return d.w.Close() }
+
+func (d *testDecoder) WriteSample(s testFrame) error {
+	return d.w.WriteSample(make(media.PCM16Sample, len(s)))
+}
+
+// concealDecoder conceals the loss natively.
+type concealDecoder struct {
+	testDecoder
+}
+
+func (d *concealDecoder) ConcealLoss(frames int, _ testFrame) error {
+	d.lost = append(d.lost, frames)
+	return nil
+}
+
+type plcRecorder struct {
+	media.PCM16Writer
+	lost []int
+}
+
+func (w *plcRecorder) Close() error { return nil }
+
+func (w *plcRecorder) ConcealLoss(frames int, _ media.PCM16Sample) error {
+	w.lost = append(w.lost, frames)
+	return nil
+}
+
+func TestDecodeRTPConceal(t *testing.T) {
+	var plcs []*plcRecorder
+	defer func(fnc func(w media.PCM16Writer) PLCWriter) { NewPLC = fnc }(NewPLC)
+	NewPLC = func(w media.PCM16Writer) PLCWriter {
+		p := &plcRecorder{PCM16Writer: w}
+		plcs = append(plcs, p)
+		return p
+	}
+	info := media.CodecInfo{SDPName: "test/8000", SampleRate: 8000}
+	encode := func(w media.WriteCloser[testFrame]) media.PCM16Writer { return nil }
+
+	run := func(c AudioCodec) {
+		var frames []media.PCM16Sample
+		h := c.DecodeRTP(media.NewPCM16FrameWriter(&frames, 8000), 96)
+		lh, ok := h.(LossHandler)
+		require.True(t, ok)
+		hdr := &rtp.Header{PayloadType: 96}
+		require.NoError(t, h.HandleRTP(hdr, []byte{1, 2}))
+		require.NoError(t, lh.HandleLoss(2, hdr, []byte{3, 4}))
+		require.NoError(t, h.HandleRTP(hdr, []byte{3, 4}))
+		require.Len(t, frames, 2)
+	}
+
+	var native *concealDecoder
+	run(NewAudioCodec(info, func(w media.PCM16Writer) media.WriteCloser[testFrame] {
+		native = &concealDecoder{testDecoder{w: w}}
+		return native
+	}, encode))
+	require.Equal(t, []int{2}, native.lost)
+	require.Empty(t, plcs, "PLC stage must not be added for decoders with native concealment")
+
+	run(NewAudioCodec(info, func(w media.PCM16Writer) media.WriteCloser[testFrame] {
+		return &testDecoder{w: w}
+	}, encode))
+	require.Len(t, plcs, 1)
+	require.Equal(t, []int{2}, plcs[0].lost)
+}
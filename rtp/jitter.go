@@ -26,18 +26,27 @@ const (
 	jitterMaxLatency = 60 * time.Millisecond // should match mixer's target buffer size
 )
 
-func HandleJitter(h HandlerCloser) HandlerCloser {
+// HandleJitter creates a jitter buffer in front of the handler.
+//
+// By default, the buffer uses a fixed latency. Options like jitter.WithAdaptiveLatency can be used to change it.
+//
+// If the handler implements LossHandler, it will be notified about packets declared lost by the buffer,
+// before the next packet. In this case, the packet loss handler of the buffer is replaced.
+func HandleJitter(h HandlerCloser, opts ...jitter.Option) HandlerCloser {
 	handler := &jitterHandler{
 		h:   h,
 		err: make(chan error, 1),
 	}
+	if _, ok := h.(LossHandler); ok {
+		opts = append(opts, jitter.WithPacketLossHandler(handler.onLoss))
+	}
 	// Jitter buffer expects to be closed (to stop the timer), but handler interface doesn't allow it.
 	// This should be fine, because GC can now collect timers and goroutines blocked on them if they are not referenced.
 	handler.buf = jitter.NewBuffer(audioDepacketizer{}, jitterMaxLatency, func(packets []jitter.ExtPacket) {
 		for _, p := range packets {
 			handler.handleRTP(p.Packet)
 		}
-	})
+	}, opts...)
 	return handler
 }
 
@@ -45,6 +54,9 @@ type jitterHandler struct {
 	h   HandlerCloser
 	buf *jitter.Buffer
 	err chan error
+
+	lost    uint64 // lost packets reported by the buffer
+	pending int    // lost packets to report before the next packet
 }
 
 func (r *jitterHandler) String() string {
@@ -52,13 +64,40 @@ func (r *jitterHandler) String() string {
 }
 
 func (r *jitterHandler) handleRTP(p *rtp.Packet) {
+	if err := r.handleLoss(p); err != nil {
+		r.pushErr(err)
+	}
 	if err := r.h.HandleRTP(&p.Header, p.Payload); err != nil {
-		select {
-		case r.err <- err:
-			// error pushed
-		default:
-			// error channel is full, don't block
-		}
+		r.pushErr(err)
+	}
+}
+
+// onLoss is called by the buffer, before the packet following the gap.
+func (r *jitterHandler) onLoss() {
+	lost := r.buf.PacketsLost()
+	r.pending += int(lost - r.lost)
+	r.lost = lost
+}
+
+func (r *jitterHandler) handleLoss(p *rtp.Packet) error {
+	if r.pending == 0 {
+		return nil
+	}
+	lost := r.pending
+	r.pending = 0
+	lh, ok := r.h.(LossHandler)
+	if !ok {
+		return nil
+	}
+	return lh.HandleLoss(lost, &p.Header, p.Payload)
+}
+
+func (r *jitterHandler) pushErr(err error) {
+	select {
+	case r.err <- err:
+		// error pushed
+	default:
+		// error channel is full, don't block
 	}
 }
 
@@ -0,0 +1,74 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtp
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+type lossRecorder struct {
+	mu   sync.Mutex
+	seqs []uint16
+	lost []int
+}
+
+func (r *lossRecorder) String() string {
+	return "lossRecorder"
+}
+
+func (r *lossRecorder) HandleRTP(h *rtp.Header, _ []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seqs = append(r.seqs, h.SequenceNumber)
+	return nil
+}
+
+func (r *lossRecorder) HandleLoss(lost int, h *rtp.Header, _ []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lost = append(r.lost, lost)
+	return nil
+}
+
+func TestJitterLoss(t *testing.T) {
+	rec := &lossRecorder{}
+	h := HandleJitter(NewNopCloser(rec))
+	defer h.Close()
+
+	hdr := &rtp.Header{SequenceNumber: 10, Timestamp: 1000}
+	for i := range 10 {
+		if i != 3 && i != 6 && i != 7 {
+			require.NoError(t, h.HandleRTP(hdr, []byte{1}))
+		}
+		hdr.SequenceNumber++
+		hdr.Timestamp += 160
+	}
+	// stream discontinuity is not a loss
+	hdr.SequenceNumber += 10000
+	require.NoError(t, h.HandleRTP(hdr, []byte{1}))
+	require.Eventually(t, func() bool {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		return len(rec.seqs) == 8
+	}, time.Second, 10*time.Millisecond)
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	require.Equal(t, []int{1, 2}, rec.lost)
+}
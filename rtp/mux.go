@@ -61,24 +61,38 @@ func (m *Mux) String() string {
 // HandleRTP selects a Handler based on payload type.
 // Types can be registered with Register. If no handler is set, a default one will be used.
 func (m *Mux) HandleRTP(h *rtp.Header, payload []byte) error {
+	r := m.handler(h.PayloadType)
+	if r == nil {
+		return nil
+	}
+	return r.HandleRTP(h, payload)
+}
+
+// HandleLoss forwards loss notification to a Handler selected by payload type of the packet after the gap.
+func (m *Mux) HandleLoss(lost int, h *rtp.Header, payload []byte) error {
+	r, ok := m.handler(h.PayloadType).(LossHandler)
+	if !ok {
+		return nil
+	}
+	return r.HandleLoss(lost, h, payload)
+}
+
+func (m *Mux) handler(typ byte) Handler {
 	if m == nil {
 		return nil
 	}
 	var r Handler
 	m.mu.RLock()
-	if h.PayloadType < byte(len(m.static)) {
-		r = m.static[h.PayloadType]
+	defer m.mu.RUnlock()
+	if typ < byte(len(m.static)) {
+		r = m.static[typ]
 	} else {
-		r = m.dynamic[h.PayloadType]
+		r = m.dynamic[typ]
 	}
 	if r == nil {
 		r = m.def
 	}
-	m.mu.RUnlock()
-	if r == nil {
-		return nil
-	}
-	return r.HandleRTP(h, payload)
+	return r
 }
 
 // SetDefault sets a default RTP handler.
@@ -0,0 +1,608 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtp
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/frostbyte73/core"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+
+	"github.com/livekit/protocol/logger"
+)
+
+const (
+	// DefRTCPBandwidth is a default session bandwidth (in bits per second) used to calculate RTCP report interval.
+	DefRTCPBandwidth = 64000
+
+	rtcpMinInterval  = 5 * time.Second
+	rtcpBandwidthPct = 0.05
+	rtcpSenderFrac   = 0.25
+	rtcpReceiverFrac = 0.75
+	rtcpCompensation = math.E - 1.5 // RFC 3550, 6.3.1
+	rtcpPacketExtra  = 28           // IP + UDP header size
+
+	// Constants from RFC 3550, A.1.
+	seqMaxDropout    = 3000
+	seqMaxMisorder   = 100
+	seqMinSequential = 2
+
+	// ntpEpochOffset is a number of seconds between NTP (1900) and Unix (1970) epochs.
+	ntpEpochOffset = 2208988800
+)
+
+// RTCPConfig configures RTCP reports for a Session.
+type RTCPConfig struct {
+	// Conn is a separate connection used for RTCP. If not set, RTCP is multiplexed with RTP (RFC 5761).
+	Conn net.Conn
+	// ClockRate is an RTP clock rate of media streams. It is used to calculate jitter and Sender Report timestamps.
+	// If not set, the clock rate is derived from the static payload type, or DefClockRate is used.
+	ClockRate int
+	// Bandwidth is a session bandwidth in bits per second. RTCP will use 5% of it. Defaults to DefRTCPBandwidth.
+	Bandwidth int
+	// CNAME is sent in SDES items. A random name is generated if not set.
+	CNAME string
+}
+
+// SessionConfig is a set of optional Session parameters.
+type SessionConfig struct {
+	// RTCP enables sending RTCP reports. If nil, RTCP is only parsed from the RTP connection.
+	RTCP *RTCPConfig
+}
+
+type SessionOption func(c *SessionConfig)
+
+// WithRTCP enables periodic RTCP reports for the session.
+func WithRTCP(conf RTCPConfig) SessionOption {
+	return func(c *SessionConfig) {
+		c.RTCP = &conf
+	}
+}
+
+// NewSessionConfig applies session options and returns the resulting config.
+func NewSessionConfig(opts ...SessionOption) SessionConfig {
+	var c SessionConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// SessionStats contains RTP and RTCP statistics for a Session.
+type SessionStats struct {
+	SSRC        uint32 // SSRC of the local write stream
+	PacketsSent uint64 // RTP packets sent
+	OctetsSent  uint64 // RTP payload octets sent
+	ReportsSent uint64 // RTCP reports sent
+
+	// Streams contains statistics for each remote SSRC.
+	Streams map[uint32]StreamStats
+}
+
+// StreamStats contains statistics for a single remote SSRC.
+type StreamStats struct {
+	SSRC uint32
+
+	// Reception statistics for the remote stream, as measured locally.
+
+	PacketsReceived uint64        // RTP packets received
+	OctetsReceived  uint64        // RTP payload octets received
+	PacketsLost     int64         // cumulative number of packets lost
+	FractionLost    float64       // fraction of packets lost since the last report
+	Jitter          time.Duration // interarrival jitter
+
+	// Statistics for the local stream, as reported by the remote side.
+
+	RemotePacketsLost  int64         // cumulative number of packets lost
+	RemoteFractionLost float64       // fraction of packets lost
+	RemoteJitter       time.Duration // interarrival jitter
+	RTT                time.Duration // round-trip time calculated from LSR and DLSR
+
+	LastSenderReport time.Time // time when the last SR was received
+	LastReport       time.Time // time when the last SR or RR was received
+	Bye              bool      // set if BYE was received
+}
+
+// IsRTCP checks if the packet is RTCP, when multiplexed with RTP (RFC 5761).
+func IsRTCP(data []byte) bool {
+	if len(data) < 2 {
+		return false
+	}
+	// RTCP packet types 192-223 collide with RTP payload types 64-95 when the marker bit is set.
+	return data[1] >= 192 && data[1] <= 223
+}
+
+// NTPTime converts time to the 64-bit NTP timestamp format.
+func NTPTime(t time.Time) uint64 {
+	sec := uint64(t.Unix()) + ntpEpochOffset
+	frac := (uint64(t.Nanosecond()) << 32) / uint64(time.Second)
+	return sec<<32 | frac
+}
+
+// RTCPWriter writes a marshaled compound RTCP packet.
+type RTCPWriter func(data []byte) error
+
+// NewRTCPReporter creates an RTCP state machine that collects RTP statistics and sends periodic reports
+// to the writer, following timing rules from RFC 3550.
+//
+// If writer is nil, reports are not sent, but the statistics are still collected.
+func NewRTCPReporter(log logger.Logger, w RTCPWriter, conf RTCPConfig) *RTCPReporter {
+	if conf.Bandwidth <= 0 {
+		conf.Bandwidth = DefRTCPBandwidth
+	}
+	if conf.CNAME == "" {
+		conf.CNAME = fmt.Sprintf("%016x@livekit", rand.Uint64())
+	}
+	r := &RTCPReporter{
+		log:     log,
+		w:       w,
+		conf:    conf,
+		epoch:   time.Now(),
+		ssrc:    rand.Uint32(), // replaced by the SSRC of the write stream once it starts sending
+		avgSize: 128,           // a reasonable initial estimate for SR/RR + SDES
+		sources: make(map[uint32]*rtcpSource),
+	}
+	if w != nil {
+		go r.run()
+	}
+	return r
+}
+
+// RTCPReporter tracks statistics of RTP streams, sends RTCP reports and parses incoming RTCP.
+type RTCPReporter struct {
+	log    logger.Logger
+	w      RTCPWriter
+	conf   RTCPConfig
+	epoch  time.Time
+	closed core.Fuse
+
+	mu          sync.Mutex
+	ssrc        uint32
+	sendType    byte
+	sendInit    bool
+	lastTS      uint32
+	lastSentAt  time.Time
+	weSent      bool // sent RTP since the last report
+	packetsSent uint64
+	octetsSent  uint64
+	reportsSent uint64
+	avgSize     float64
+	sources     map[uint32]*rtcpSource
+}
+
+type rtcpSource struct {
+	stats     StreamStats
+	clockRate int
+	sender    bool // sent RTP since the last report
+
+	// Sequence number tracking, see RFC 3550, A.1.
+	init          bool
+	maxSeq        uint16
+	cycles        uint32
+	baseSeq       uint32
+	badSeq        uint32
+	probation     int
+	received      uint32
+	expectedPrior uint32
+	receivedPrior uint32
+
+	// Jitter calculation, see RFC 3550, A.8.
+	hasTransit bool
+	transit    int64
+	jitter     float64 // in timestamp units
+
+	lastSR   uint32 // middle 32 bits of the NTP timestamp from the last SR
+	lastSRAt time.Time
+}
+
+func (r *RTCPReporter) clockRate(typ byte) int {
+	if r.conf.ClockRate > 0 {
+		return r.conf.ClockRate
+	}
+	if c := CodecByPayloadType(typ); c != nil {
+		if rate := c.Info().RTPClockRate; rate > 0 {
+			return rate
+		}
+	}
+	return DefClockRate
+}
+
+func (r *RTCPReporter) source(ssrc uint32) *rtcpSource {
+	s := r.sources[ssrc]
+	if s == nil {
+		s = &rtcpSource{stats: StreamStats{SSRC: ssrc}}
+		r.sources[ssrc] = s
+	}
+	return s
+}
+
+// OnSend must be called for each RTP packet sent to the remote.
+func (r *RTCPReporter) OnSend(h *rtp.Header, payloadSize int) {
+	if r == nil {
+		return
+	}
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ssrc = h.SSRC
+	r.sendType = h.PayloadType
+	r.sendInit = true
+	r.lastTS = h.Timestamp
+	r.lastSentAt = now
+	r.weSent = true
+	r.packetsSent++
+	r.octetsSent += uint64(payloadSize)
+}
+
+// OnReceive must be called for each RTP packet received from the remote.
+func (r *RTCPReporter) OnReceive(h *rtp.Header, payloadSize int) {
+	if r == nil {
+		return
+	}
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.source(h.SSRC)
+	if s.clockRate == 0 {
+		s.clockRate = r.clockRate(h.PayloadType)
+	}
+	s.sender = true
+	if !s.updateSeq(h.SequenceNumber) {
+		return
+	}
+	s.stats.PacketsReceived++
+	s.stats.OctetsReceived += uint64(payloadSize)
+	s.updateJitter(h.Timestamp, now.Sub(r.epoch))
+}
+
+func (s *rtcpSource) initSeq(seq uint16) {
+	s.baseSeq = uint32(seq)
+	s.maxSeq = seq
+	s.badSeq = math.MaxUint32
+	s.cycles = 0
+	s.received = 0
+	s.receivedPrior = 0
+	s.expectedPrior = 0
+}
+
+// updateSeq updates the sequence number state and reports if the packet should be counted.
+func (s *rtcpSource) updateSeq(seq uint16) bool {
+	if !s.init {
+		s.init = true
+		s.initSeq(seq)
+		s.maxSeq = seq - 1
+		s.probation = seqMinSequential
+	}
+	udelta := seq - s.maxSeq
+	// Source is not valid until seqMinSequential packets with sequential numbers have been received.
+	if s.probation > 0 {
+		if seq == s.maxSeq+1 {
+			s.probation--
+			s.maxSeq = seq
+			if s.probation == 0 {
+				s.initSeq(seq)
+				s.received++
+				return true
+			}
+		} else {
+			s.probation = seqMinSequential - 1
+			s.maxSeq = seq
+		}
+		return false
+	}
+	switch {
+	case udelta < seqMaxDropout:
+		// in order, with permissible gap
+		if seq < s.maxSeq {
+			s.cycles += 1 << 16
+		}
+		s.maxSeq = seq
+	case udelta <= 1<<16-seqMaxMisorder:
+		// the sequence number made a very large jump
+		if uint32(seq) == s.badSeq {
+			// Two sequential packets - assume that the other side restarted without telling us.
+			s.initSeq(seq)
+		} else {
+			s.badSeq = uint32(seq + 1)
+			return false
+		}
+	default:
+		// duplicate or reordered packet
+	}
+	s.received++
+	return true
+}
+
+// updateJitter updates interarrival jitter estimate. Arrival time is relative to an arbitrary epoch.
+func (s *rtcpSource) updateJitter(ts uint32, arrivalTime time.Duration) {
+	arrival := int64(arrivalTime.Seconds() * float64(s.clockRate))
+	transit := arrival - int64(ts)
+	if !s.hasTransit {
+		s.hasTransit = true
+		s.transit = transit
+		return
+	}
+	d := transit - s.transit
+	s.transit = transit
+	if d < 0 {
+		d = -d
+	}
+	s.jitter += (float64(d) - s.jitter) / 16
+	s.stats.Jitter = time.Duration(s.jitter * float64(time.Second) / float64(s.clockRate))
+}
+
+// report creates a reception report block for the source and resets interval counters.
+func (s *rtcpSource) report(now time.Time) rtcp.ReceptionReport {
+	extMax := s.cycles + uint32(s.maxSeq)
+	expected := int64(extMax) - int64(s.baseSeq) + 1
+	lost := expected - int64(s.received)
+
+	expectedInterval := int64(extMax - s.baseSeq + 1 - s.expectedPrior)
+	s.expectedPrior = extMax - s.baseSeq + 1
+	receivedInterval := int64(s.received - s.receivedPrior)
+	s.receivedPrior = s.received
+	lostInterval := expectedInterval - receivedInterval
+
+	var fraction uint8
+	if expectedInterval > 0 && lostInterval > 0 {
+		fraction = uint8((lostInterval << 8) / expectedInterval)
+	}
+	s.stats.PacketsLost = lost
+	s.stats.FractionLost = float64(fraction) / 256
+
+	var dlsr uint32
+	if !s.lastSRAt.IsZero() {
+		dlsr = uint32(now.Sub(s.lastSRAt) * 65536 / time.Second)
+	}
+	return rtcp.ReceptionReport{
+		SSRC:               s.stats.SSRC,
+		FractionLost:       fraction,
+		TotalLost:          encodeTotalLost(lost),
+		LastSequenceNumber: extMax,
+		Jitter:             uint32(s.jitter),
+		LastSenderReport:   s.lastSR,
+		Delay:              dlsr,
+	}
+}
+
+// encodeTotalLost clamps the number of lost packets to a 24-bit signed integer.
+func encodeTotalLost(lost int64) uint32 {
+	const maxLost = 1<<23 - 1
+	if lost > maxLost {
+		lost = maxLost
+	} else if lost < -maxLost-1 {
+		lost = -maxLost - 1
+	}
+	return uint32(lost) & 0xFFFFFF
+}
+
+// decodeTotalLost sign-extends a 24-bit cumulative loss value.
+func decodeTotalLost(v uint32) int64 {
+	return int64(int32(v<<8) >> 8)
+}
+
+// HandleRTCP parses a compound RTCP packet and updates statistics.
+func (r *RTCPReporter) HandleRTCP(data []byte) error {
+	pkts, err := rtcp.Unmarshal(data)
+	if err != nil {
+		return err
+	}
+	r.HandleRTCPPackets(pkts)
+	return nil
+}
+
+// HandleRTCPPackets updates statistics from parsed RTCP packets.
+func (r *RTCPReporter) HandleRTCPPackets(pkts []rtcp.Packet) {
+	if r == nil {
+		return
+	}
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range pkts {
+		switch p := p.(type) {
+		case *rtcp.SenderReport:
+			s := r.source(p.SSRC)
+			s.lastSR = uint32(p.NTPTime >> 16)
+			s.lastSRAt = now
+			s.stats.LastSenderReport = now
+			s.stats.LastReport = now
+			r.handleReports(s, p.Reports, now)
+		case *rtcp.ReceiverReport:
+			s := r.source(p.SSRC)
+			s.stats.LastReport = now
+			r.handleReports(s, p.Reports, now)
+		case *rtcp.Goodbye:
+			for _, ssrc := range p.Sources {
+				if s := r.sources[ssrc]; s != nil {
+					s.stats.Bye = true
+				}
+			}
+		}
+	}
+}
+
+func (r *RTCPReporter) handleReports(s *rtcpSource, reports []rtcp.ReceptionReport, now time.Time) {
+	for _, rep := range reports {
+		if !r.sendInit || rep.SSRC != r.ssrc {
+			continue // not about our stream
+		}
+		s.stats.RemoteFractionLost = float64(rep.FractionLost) / 256
+		s.stats.RemotePacketsLost = decodeTotalLost(rep.TotalLost)
+		s.stats.RemoteJitter = time.Duration(rep.Jitter) * time.Second / time.Duration(r.clockRate(r.sendType))
+		if rep.LastSenderReport != 0 {
+			// RFC 3550, 6.4.1
+			a := uint32(NTPTime(now) >> 16)
+			if rtt := a - rep.LastSenderReport - rep.Delay; int32(rtt) >= 0 {
+				s.stats.RTT = time.Duration(rtt) * time.Second / 65536
+			}
+		}
+	}
+}
+
+// Stats returns a copy of current statistics.
+func (r *RTCPReporter) Stats() *SessionStats {
+	if r == nil {
+		return &SessionStats{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st := &SessionStats{
+		SSRC:        r.ssrc,
+		PacketsSent: r.packetsSent,
+		OctetsSent:  r.octetsSent,
+		ReportsSent: r.reportsSent,
+		Streams:     make(map[uint32]StreamStats, len(r.sources)),
+	}
+	for ssrc, s := range r.sources {
+		st.Streams[ssrc] = s.stats
+	}
+	return st
+}
+
+// interval calculates the RTCP report interval, as defined in RFC 3550, A.7.
+func (r *RTCPReporter) interval(initial bool) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	members, senders := 1, 0
+	if r.weSent {
+		senders++
+	}
+	for _, s := range r.sources {
+		if s.stats.Bye {
+			continue
+		}
+		members++
+		if s.sender {
+			senders++
+		}
+	}
+	bw := float64(r.conf.Bandwidth) / 8 * rtcpBandwidthPct // in octets per second
+	n := members
+	if float64(senders) <= float64(members)*rtcpSenderFrac {
+		if r.weSent {
+			bw *= rtcpSenderFrac
+			n = senders
+		} else {
+			bw *= rtcpReceiverFrac
+			n -= senders
+		}
+	}
+	minInterval := rtcpMinInterval
+	if initial {
+		minInterval /= 2
+	}
+	t := time.Duration(r.avgSize * float64(n) / bw * float64(time.Second))
+	if t < minInterval {
+		t = minInterval
+	}
+	// randomize to avoid synchronization with other participants
+	t = time.Duration(float64(t) * (rand.Float64() + 0.5) / rtcpCompensation)
+	return t
+}
+
+func (r *RTCPReporter) run() {
+	initial := true
+	for {
+		timer := time.NewTimer(r.interval(initial))
+		select {
+		case <-r.closed.Watch():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		initial = false
+		if err := r.sendReport(false); err != nil {
+			r.log.Debugw("cannot send RTCP report", "error", err)
+		}
+	}
+}
+
+// buildReport creates a compound RTCP packet with SR or RR, SDES and an optional BYE.
+func (r *RTCPReporter) buildReport(bye bool) []rtcp.Packet {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reports := make([]rtcp.ReceptionReport, 0, len(r.sources))
+	for _, ssrc := range slices.Sorted(maps.Keys(r.sources)) {
+		s := r.sources[ssrc]
+		if !s.init || s.probation > 0 || s.stats.Bye {
+			continue
+		}
+		reports = append(reports, s.report(now))
+		s.sender = false
+	}
+	// RFC 3550 limits the number of report blocks in a single SR/RR.
+	if len(reports) > 31 {
+		reports = reports[:31]
+	}
+	var pkts []rtcp.Packet
+	if r.weSent {
+		rtpTime := r.lastTS + uint32(now.Sub(r.lastSentAt)*time.Duration(r.clockRate(r.sendType))/time.Second)
+		pkts = append(pkts, &rtcp.SenderReport{
+			SSRC:        r.ssrc,
+			NTPTime:     NTPTime(now),
+			RTPTime:     rtpTime,
+			PacketCount: uint32(r.packetsSent),
+			OctetCount:  uint32(r.octetsSent),
+			Reports:     reports,
+		})
+	} else {
+		pkts = append(pkts, &rtcp.ReceiverReport{
+			SSRC:    r.ssrc,
+			Reports: reports,
+		})
+	}
+	r.weSent = false
+	pkts = append(pkts, rtcp.NewCNAMESourceDescription(r.ssrc, r.conf.CNAME))
+	if bye {
+		pkts = append(pkts, &rtcp.Goodbye{Sources: []uint32{r.ssrc}})
+	}
+	return pkts
+}
+
+func (r *RTCPReporter) sendReport(bye bool) error {
+	data, err := rtcp.Marshal(r.buildReport(bye))
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.reportsSent++
+	r.avgSize += (float64(len(data)+rtcpPacketExtra) - r.avgSize) / 16
+	r.mu.Unlock()
+	return r.w(data)
+}
+
+// Close stops periodic reports and sends BYE to the remote.
+func (r *RTCPReporter) Close() error {
+	if r == nil {
+		return nil
+	}
+	var err error
+	r.closed.Once(func() {
+		if r.w != nil {
+			err = r.sendReport(true)
+		}
+	})
+	return err
+}
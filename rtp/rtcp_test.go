@@ -0,0 +1,121 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/logger"
+)
+
+func TestRTCPReceptionReport(t *testing.T) {
+	r := NewRTCPReporter(logger.GetLogger(), nil, RTCPConfig{ClockRate: 8000})
+	defer r.Close()
+
+	const ssrc = 0x1234
+	h := &rtp.Header{SSRC: ssrc, SequenceNumber: 0xfff0, Timestamp: 1000}
+	for i := 0; i < 100; i++ {
+		if i%10 != 5 {
+			r.OnReceive(h, 160)
+		}
+		h.SequenceNumber++
+		h.Timestamp += 160
+	}
+	pkts := r.buildReport(false)
+	require.Len(t, pkts, 2)
+	rr, ok := pkts[0].(*rtcp.ReceiverReport)
+	require.True(t, ok)
+	require.Len(t, rr.Reports, 1)
+	rep := rr.Reports[0]
+	require.Equal(t, uint32(ssrc), rep.SSRC)
+	// sequence wrapped once
+	require.Equal(t, uint32(1<<16|(0xfff0+99)&0xffff), rep.LastSequenceNumber)
+	require.Equal(t, uint32(10), rep.TotalLost)
+	require.Equal(t, uint8(10*256/99), rep.FractionLost)
+
+	st := r.Stats().Streams[ssrc]
+	require.Equal(t, uint64(89), st.PacketsReceived)
+	require.Equal(t, int64(10), st.PacketsLost)
+
+	// no new losses in the next interval
+	for i := 0; i < 10; i++ {
+		r.OnReceive(h, 160)
+		h.SequenceNumber++
+		h.Timestamp += 160
+	}
+	rr = r.buildReport(false)[0].(*rtcp.ReceiverReport)
+	require.Equal(t, uint8(0), rr.Reports[0].FractionLost)
+	require.Equal(t, uint32(10), rr.Reports[0].TotalLost)
+}
+
+func TestRTCPExchange(t *testing.T) {
+	var a, b *RTCPReporter
+	a = NewRTCPReporter(logger.GetLogger(), nil, RTCPConfig{ClockRate: 8000})
+	b = NewRTCPReporter(logger.GetLogger(), nil, RTCPConfig{ClockRate: 8000})
+	a.w = func(data []byte) error { return b.HandleRTCP(data) }
+	b.w = func(data []byte) error { return a.HandleRTCP(data) }
+
+	const ssrc = 0x5678
+	h := &rtp.Header{SSRC: ssrc, PayloadType: 0}
+	for i := 0; i < 50; i++ {
+		a.OnSend(h, 160)
+		b.OnReceive(h, 160)
+		h.SequenceNumber++
+		h.Timestamp += 160
+	}
+	require.NoError(t, a.sendReport(false))
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, b.sendReport(false))
+
+	sta := a.Stats()
+	require.Equal(t, uint32(ssrc), sta.SSRC)
+	require.Equal(t, uint64(50), sta.PacketsSent)
+	require.Equal(t, uint64(50*160), sta.OctetsSent)
+	remote := sta.Streams[b.Stats().SSRC]
+	require.False(t, remote.LastReport.IsZero())
+	require.Zero(t, remote.RemotePacketsLost)
+	require.Less(t, remote.RTT, 5*time.Millisecond)
+
+	stb := b.Stats().Streams[ssrc]
+	require.False(t, stb.LastSenderReport.IsZero())
+	require.Equal(t, uint64(49), stb.PacketsReceived) // first packet is used for probation
+
+	require.NoError(t, a.Close())
+	require.True(t, b.Stats().Streams[ssrc].Bye)
+	require.NoError(t, b.Close())
+}
+
+func TestRTCPTotalLost(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, 1<<23 - 1, -1 << 23} {
+		require.Equal(t, v, decodeTotalLost(encodeTotalLost(v)))
+	}
+	require.Equal(t, int64(1<<23-1), decodeTotalLost(encodeTotalLost(1<<30)))
+}
+
+func TestIsRTCP(t *testing.T) {
+	sr, err := rtcp.Marshal([]rtcp.Packet{&rtcp.ReceiverReport{SSRC: 1}})
+	require.NoError(t, err)
+	require.True(t, IsRTCP(sr))
+
+	p := &rtp.Packet{Header: rtp.Header{Version: 2, PayloadType: 96, Marker: true}}
+	data, err := p.Marshal()
+	require.NoError(t, err)
+	require.False(t, IsRTCP(data))
+}
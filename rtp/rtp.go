@@ -19,6 +19,7 @@ import (
 	"math/rand/v2"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/pion/interceptor"
 	"github.com/pion/rtp"
@@ -45,6 +46,13 @@ type Handler interface {
 	HandleRTP(h *rtp.Header, payload []byte) error
 }
 
+// LossHandler is an optional interface for Handler that is notified about lost packets.
+type LossHandler interface {
+	// HandleLoss is called before HandleRTP of the first packet after the gap.
+	// The lost argument is the number of missing packets.
+	HandleLoss(lost int, h *rtp.Header, payload []byte) error
+}
+
 type HandlerCloser interface {
 	Handler
 	Close()
@@ -88,6 +96,13 @@ func NewNopCloser(h Handler) HandlerCloser {
 
 func (nopCloser) Close() {}
 
+func (h nopCloser) HandleLoss(lost int, hdr *rtp.Header, payload []byte) error {
+	if lh, ok := h.Handler.(LossHandler); ok {
+		return lh.HandleLoss(lost, hdr, payload)
+	}
+	return nil
+}
+
 // Buffer is a Writer that clones and appends RTP packets into a slice.
 type Buffer []*Packet
 
@@ -163,30 +178,44 @@ type Stream struct {
 	packetDur uint32
 	mu        sync.Mutex
 	ev        Event
+	skipped   bool
 }
 
-func (s *Stream) writePayload(inc bool, data []byte, marker bool) error {
+func (s *Stream) writePayload(inc uint32, data []byte, marker bool) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.ev.Payload = data
-	s.ev.Marker = marker
+	s.ev.Marker = marker || s.skipped
 	if err := s.s.WriteEvent(&s.ev); err != nil {
 		return err
 	}
-	if inc {
-		s.ev.Timestamp += s.packetDur
-	}
+	s.skipped = false
+	s.ev.Timestamp += inc
 	return nil
 }
 
+// Skip increments the timestamp without sending the packet, for example, during discontinuous transmission.
+// The next packet will have the marker bit set.
+func (s *Stream) Skip() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ev.Timestamp += s.packetDur
+	s.skipped = true
+}
+
 // WritePayload writes the payload to RTP and increments the timestamp.
 func (s *Stream) WritePayload(data []byte, marker bool) error {
-	return s.writePayload(true, data, marker)
+	return s.writePayload(s.packetDur, data, marker)
+}
+
+// WritePayloadWithDur writes the payload to RTP and increments the timestamp by a given duration in clock units.
+func (s *Stream) WritePayloadWithDur(data []byte, marker bool, dur uint32) error {
+	return s.writePayload(dur, data, marker)
 }
 
 // WritePayloadAtCurrent writes the payload to RTP at the current timestamp.
 func (s *Stream) WritePayloadAtCurrent(data []byte, marker bool) error {
-	return s.writePayload(false, data, marker)
+	return s.writePayload(0, data, marker)
 }
 
 func (s *Stream) Delay(dur uint32) {
@@ -209,6 +238,12 @@ func (s *Stream) GetCurrentTimestamp() uint32 {
 	return s.ev.Timestamp
 }
 
+// DurationFrame is an optional interface for frames with variable duration.
+// MediaStreamOut uses it to increment RTP timestamps, assuming the sample rate matches the RTP clock rate.
+type DurationFrame interface {
+	Duration() time.Duration
+}
+
 func NewMediaStreamOut[T BytesFrame](s *Stream, sampleRate int) *MediaStreamOut[T] {
 	return &MediaStreamOut[T]{s: s, sampleRate: sampleRate}
 }
@@ -231,6 +266,11 @@ func (s *MediaStreamOut[T]) Close() error {
 }
 
 func (s *MediaStreamOut[T]) WriteSample(sample T) error {
+	if f, ok := any(sample).(DurationFrame); ok {
+		if dur := f.Duration(); dur > 0 {
+			return s.s.WritePayloadWithDur([]byte(sample), false, uint32(time.Duration(s.sampleRate)*dur/time.Second))
+		}
+	}
 	return s.s.WritePayload([]byte(sample), false)
 }
 
@@ -249,3 +289,10 @@ func (s *MediaStreamIn[T]) String() string {
 func (s *MediaStreamIn[T]) HandleRTP(_ *rtp.Header, payload []byte) error {
 	return s.Writer.WriteSample(T(payload))
 }
+
+func (s *MediaStreamIn[T]) HandleLoss(lost int, _ *rtp.Header, payload []byte) error {
+	if c, ok := s.Writer.(media.LossConcealer[T]); ok {
+		return c.ConcealLoss(lost, T(payload))
+	}
+	return nil
+}
@@ -35,6 +35,8 @@ const (
 type Session interface {
 	OpenWriteStream() (WriteStream, error)
 	AcceptStream() (ReadStream, uint32, error)
+	// Stats returns RTP statistics for the session, including ones reported by the remote via RTCP.
+	Stats() *SessionStats
 	Close() error
 }
 
@@ -49,21 +51,40 @@ type ReadStream interface {
 	ReadRTP(h *rtp.Header, payload []byte) (int, error)
 }
 
-func NewSession(log logger.Logger, conn net.Conn) Session {
-	return &session{
+func NewSession(log logger.Logger, conn net.Conn, opts ...SessionOption) Session {
+	conf := NewSessionConfig(opts...)
+	s := &session{
 		log:    log,
 		conn:   conn,
-		w:      &writeStream{conn: conn},
 		bySSRC: make(map[uint32]*readStream),
 		rbuf:   make([]byte, MTUSize+1), // larger buffer to detect overflow
 	}
+	if conf.RTCP == nil {
+		s.rtcp = NewRTCPReporter(log, nil, RTCPConfig{})
+	} else {
+		s.rtcpConn = conf.RTCP.Conn
+		if s.rtcpConn == nil {
+			s.rtcpConn = conn // RTCP mux
+		}
+		s.rtcp = NewRTCPReporter(log, func(data []byte) error {
+			_, err := s.rtcpConn.Write(data)
+			return err
+		}, *conf.RTCP)
+		if s.rtcpConn != conn {
+			go s.readRTCP()
+		}
+	}
+	s.w = &writeStream{conn: conn, rtcp: s.rtcp}
+	return s
 }
 
 type session struct {
-	log    logger.Logger
-	conn   net.Conn
-	closed core.Fuse
-	w      *writeStream
+	log      logger.Logger
+	conn     net.Conn
+	rtcpConn net.Conn
+	rtcp     *RTCPReporter
+	closed   core.Fuse
+	w        *writeStream
 
 	rmu    sync.Mutex
 	rbuf   []byte
@@ -91,11 +112,16 @@ func (s *session) AcceptStream() (ReadStream, uint32, error) {
 			continue // ignore partial messages
 		}
 		buf := s.rbuf[:n]
+		if IsRTCP(buf) {
+			_ = s.rtcp.HandleRTCP(buf)
+			continue
+		}
 		var p rtp.Packet
 		err = p.Unmarshal(buf)
 		if err != nil {
 			continue // ignore
 		}
+		s.rtcp.OnReceive(&p.Header, len(p.Payload))
 
 		isNew := false
 		r := s.bySSRC[p.SSRC]
@@ -116,9 +142,30 @@ func (s *session) AcceptStream() (ReadStream, uint32, error) {
 	}
 }
 
+func (s *session) readRTCP() {
+	buf := make([]byte, MTUSize)
+	for {
+		n, err := s.rtcpConn.Read(buf)
+		if err != nil {
+			return
+		}
+		_ = s.rtcp.HandleRTCP(buf[:n])
+	}
+}
+
+func (s *session) Stats() *SessionStats {
+	return s.rtcp.Stats()
+}
+
 func (s *session) Close() error {
 	var err error
 	s.closed.Once(func() {
+		if err := s.rtcp.Close(); err != nil {
+			s.log.Debugw("cannot send RTCP BYE", "error", err)
+		}
+		if s.rtcpConn != nil && s.rtcpConn != s.conn {
+			_ = s.rtcpConn.Close()
+		}
 		err = s.conn.Close()
 		s.rmu.Lock()
 		defer s.rmu.Unlock()
@@ -131,6 +178,7 @@ type writeStream struct {
 	mu   sync.Mutex
 	buf  []byte
 	conn net.Conn
+	rtcp *RTCPReporter
 }
 
 func (w *writeStream) String() string {
@@ -150,7 +198,12 @@ func (w *writeStream) WriteRTP(h *rtp.Header, payload []byte) (int, error) {
 		return 0, err
 	}
 	copy(buf[n:], payload)
-	return w.conn.Write(buf)
+	n, err = w.conn.Write(buf)
+	if err != nil {
+		return n, err
+	}
+	w.rtcp.OnSend(h, len(payload))
+	return n, nil
 }
 
 type readStream struct {
@@ -15,9 +15,14 @@
 package sdp
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/pion/sdp/v3"
+
 	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/rtp"
 )
 
 var (
@@ -44,3 +49,52 @@ func CodecByName(name string) media.Codec {
 	}
 	return c
 }
+
+// parseFmtp parses format parameters in the form "key1=value1;key2=value2".
+// Keys are converted to lower case. Parameters without a value are stored with an empty value.
+func parseFmtp(s string) map[string]string {
+	params := make(map[string]string)
+	for _, p := range strings.Split(s, ";") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		key, val, _ := strings.Cut(p, "=")
+		params[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(val)
+	}
+	return params
+}
+
+// formatFmtp formats parameters with keys sorted alphabetically.
+func formatFmtp(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var buf strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(';')
+		}
+		buf.WriteString(k)
+		if v := params[k]; v != "" {
+			buf.WriteByte('=')
+			buf.WriteString(v)
+		}
+	}
+	return buf.String()
+}
+
+// codecFmtp returns an fmtp attribute for the codec, if it has format parameters.
+func codecFmtp(typ byte, c media.Codec) (sdp.Attribute, bool) {
+	fc, ok := c.(rtp.FmtpCodec)
+	if !ok {
+		return sdp.Attribute{}, false
+	}
+	params := fc.Fmtp()
+	if len(params) == 0 {
+		return sdp.Attribute{}, false
+	}
+	return sdp.Attribute{Key: "fmtp", Value: fmt.Sprintf("%d %s", typ, formatFmtp(params))}, true
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdp_test
+
+import (
+	"testing"
+
+	"github.com/pion/sdp/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/rtp"
+	. "github.com/livekit/media-sdk/sdp"
+)
+
+const fmtpCodecName = "fmtp-test/48000/2"
+
+func init() {
+	media.RegisterCodec(&fmtpCodec{local: map[string]string{"useinbandfec": "1", "usedtx": "1"}})
+}
+
+// fmtpCodec is a dynamic codec with format parameters. It is disabled by default.
+type fmtpCodec struct {
+	local  map[string]string
+	remote map[string]string
+}
+
+func (c *fmtpCodec) Info() media.CodecInfo {
+	return media.CodecInfo{
+		SDPName:      fmtpCodecName,
+		SampleRate:   48000,
+		RTPClockRate: 48000,
+		Priority:     10,
+		Disabled:     true,
+	}
+}
+
+func (c *fmtpCodec) EncodeRTP(*rtp.Stream) media.PCM16Writer { return nil }
+
+func (c *fmtpCodec) DecodeRTP(media.Writer[media.PCM16Sample], byte) rtp.Handler { return nil }
+
+func (c *fmtpCodec) Fmtp() map[string]string { return c.local }
+
+func (c *fmtpCodec) WithFmtp(remote map[string]string) rtp.AudioCodec {
+	return &fmtpCodec{local: c.local, remote: remote}
+}
+
+func TestSDPFmtp(t *testing.T) {
+	media.CodecSetEnabled(fmtpCodecName, true)
+	defer media.CodecSetEnabled(fmtpCodecName, false)
+
+	_, offer, err := OfferMedia(12345, EncryptionNone)
+	require.NoError(t, err)
+	require.Equal(t, []string{"9", "0", "8", "13", "101", "102"}, offer.MediaName.Formats)
+	require.Equal(t, []sdp.Attribute{
+		{Key: "rtpmap", Value: "9 G722/8000"},
+		{Key: "rtpmap", Value: "0 PCMU/8000"},
+		{Key: "rtpmap", Value: "8 PCMA/8000"},
+		{Key: "rtpmap", Value: "13 CN/8000"},
+		{Key: "rtpmap", Value: "101 " + fmtpCodecName},
+		{Key: "fmtp", Value: "101 usedtx=1;useinbandfec=1"},
+		{Key: "rtpmap", Value: "102 telephone-event/8000"},
+		{Key: "fmtp", Value: "102 0-16"},
+		{Key: "ptime", Value: "20"},
+		{Key: "sendrecv"},
+	}, offer.Attributes)
+
+	m, err := ParseMedia(&sdp.MediaDescription{
+		MediaName: sdp.MediaName{
+			Formats: []string{"96", "0", "101"},
+		},
+		Attributes: []sdp.Attribute{
+			{Key: "rtpmap", Value: "96 " + fmtpCodecName},
+			{Key: "fmtp", Value: "96 Stereo=1; maxaveragebitrate=20000;cbr"},
+			{Key: "rtpmap", Value: "0 PCMU/8000"},
+			{Key: "rtpmap", Value: "101 telephone-event/8000"},
+			{Key: "fmtp", Value: "101 0-16"},
+		},
+	})
+	require.NoError(t, err)
+	audio, err := SelectAudio(*m, true)
+	require.NoError(t, err)
+	require.EqualValues(t, 96, audio.Type)
+	require.EqualValues(t, 101, audio.DTMFType)
+	c, ok := audio.Codec.(*fmtpCodec)
+	require.True(t, ok)
+	require.Equal(t, map[string]string{"stereo": "1", "maxaveragebitrate": "20000", "cbr": ""}, c.remote)
+
+	answer := AnswerMedia(12345, audio, nil)
+	require.Equal(t, []string{"96", "101"}, answer.MediaName.Formats)
+	require.Equal(t, []sdp.Attribute{
+		{Key: "rtpmap", Value: "96 " + fmtpCodecName},
+		{Key: "fmtp", Value: "96 usedtx=1;useinbandfec=1"},
+		{Key: "rtpmap", Value: "101 telephone-event/8000"},
+		{Key: "fmtp", Value: "101 0-16"},
+		{Key: "ptime", Value: "20"},
+		{Key: "sendrecv"},
+	}, answer.Attributes)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/pion/sdp/v3"
 
 	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/cn"
 	"github.com/livekit/media-sdk/dtmf"
 	"github.com/livekit/media-sdk/rtp"
 	"github.com/livekit/media-sdk/srtp"
@@ -87,6 +88,7 @@ func OfferCodecs() []CodecInfo {
 type MediaDesc struct {
 	Codecs         []CodecInfo
 	DTMFType       byte // set to 0 if there's no DTMF
+	CNType         byte // set to 0 if there's no comfort noise
 	CryptoProfiles []srtp.Profile
 }
 
@@ -110,12 +112,24 @@ func OfferMedia(rtpListenerPort int, encrypted Encryption) (MediaDesc, *sdp.Medi
 	var _ = [1]struct{}{}[20*time.Millisecond-rtp.DefFrameDur]
 
 	codecs := OfferCodecs()
+	if !slices.ContainsFunc(codecs, func(c CodecInfo) bool {
+		_, ok := c.Codec.(rtp.AudioCodec)
+		return ok && cnSupported(c.Codec)
+	}) {
+		codecs = slices.DeleteFunc(codecs, func(c CodecInfo) bool {
+			return c.Codec.Info().SDPName == cn.SDPName
+		})
+	}
 	attrs := make([]sdp.Attribute, 0, len(codecs)+4)
 	formats := make([]string, 0, len(codecs))
 	dtmfType := byte(0)
+	cnType := byte(0)
 	for _, codec := range codecs {
-		if codec.Codec.Info().SDPName == dtmf.SDPName {
+		switch codec.Codec.Info().SDPName {
+		case dtmf.SDPName:
 			dtmfType = codec.Type
+		case cn.SDPName:
+			cnType = codec.Type
 		}
 		styp := strconv.Itoa(int(codec.Type))
 		formats = append(formats, styp)
@@ -123,6 +137,9 @@ func OfferMedia(rtpListenerPort int, encrypted Encryption) (MediaDesc, *sdp.Medi
 			Key:   "rtpmap",
 			Value: styp + " " + codec.Codec.Info().SDPName,
 		})
+		if fmtp, ok := codecFmtp(codec.Type, codec.Codec); ok {
+			attrs = append(attrs, fmtp)
+		}
 	}
 	if dtmfType > 0 {
 		attrs = append(attrs, sdp.Attribute{
@@ -152,6 +169,7 @@ func OfferMedia(rtpListenerPort int, encrypted Encryption) (MediaDesc, *sdp.Medi
 	return MediaDesc{
 			Codecs:         codecs,
 			DTMFType:       dtmfType,
+			CNType:         cnType,
 			CryptoProfiles: cryptoProfiles,
 		}, &sdp.MediaDescription{
 			MediaName: sdp.MediaName{
@@ -172,8 +190,17 @@ func AnswerMedia(rtpListenerPort int, audio *AudioConfig, crypt *srtp.Profile) *
 	attrs = append(attrs, sdp.Attribute{
 		Key: "rtpmap", Value: fmt.Sprintf("%d %s", audio.Type, audio.Codec.Info().SDPName),
 	})
-	formats := make([]string, 0, 2)
+	if fmtp, ok := codecFmtp(audio.Type, audio.Codec); ok {
+		attrs = append(attrs, fmtp)
+	}
+	formats := make([]string, 0, 3)
 	formats = append(formats, strconv.Itoa(int(audio.Type)))
+	if audio.CNType != 0 && cnSupported(audio.Codec) {
+		formats = append(formats, strconv.Itoa(int(audio.CNType)))
+		attrs = append(attrs, sdp.Attribute{
+			Key: "rtpmap", Value: fmt.Sprintf("%d %s", audio.CNType, cn.SDPName),
+		})
+	}
 	if audio.DTMFType != 0 {
 		formats = append(formats, strconv.Itoa(int(audio.DTMFType)))
 		attrs = append(attrs, []sdp.Attribute{
@@ -311,6 +338,7 @@ func (d *Offer) Answer(publicIp netip.Addr, rtpListenerPort int, enc Encryption)
 					{Type: audio.Type, Codec: audio.Codec},
 				},
 				DTMFType: audio.DTMFType,
+				CNType:   audio.CNType,
 			},
 		}, &MediaConfig{
 			Local:  src,
@@ -424,8 +452,19 @@ func parseSRTPProfile(val string) (*srtp.Profile, error) {
 
 func ParseMedia(d *sdp.MediaDescription) (*MediaDesc, error) {
 	var out MediaDesc
+	fmtp := make(map[byte]map[string]string)
 	for _, m := range d.Attributes {
 		switch m.Key {
+		case "fmtp":
+			sub := strings.SplitN(m.Value, " ", 2)
+			if len(sub) != 2 {
+				continue
+			}
+			typ, err := strconv.Atoi(sub[0])
+			if err != nil {
+				continue
+			}
+			fmtp[byte(typ)] = parseFmtp(sub[1])
 		case "rtpmap":
 			sub := strings.SplitN(m.Value, " ", 2)
 			if len(sub) != 2 {
@@ -440,6 +479,10 @@ func ParseMedia(d *sdp.MediaDescription) (*MediaDesc, error) {
 				out.DTMFType = byte(typ)
 				continue
 			}
+			if strings.EqualFold(name, cn.SDPName) {
+				out.CNType = byte(typ)
+				continue
+			}
 			codec, _ := CodecByName(name).(rtp.AudioCodec)
 			out.Codecs = append(out.Codecs, CodecInfo{
 				Type:  byte(typ),
@@ -460,12 +503,24 @@ func ParseMedia(d *sdp.MediaDescription) (*MediaDesc, error) {
 		if err != nil {
 			continue
 		}
+		if typ == cn.PayloadType {
+			out.CNType = byte(typ)
+			continue
+		}
 		codec, _ := rtp.CodecByPayloadType(byte(typ)).(rtp.AudioCodec)
+		if !media.CodecEnabled(codec) {
+			codec = nil
+		}
 		out.Codecs = append(out.Codecs, CodecInfo{
 			Type:  byte(typ),
 			Codec: codec,
 		})
 	}
+	for i, c := range out.Codecs {
+		if fc, ok := c.Codec.(rtp.FmtpCodec); ok {
+			out.Codecs[i].Codec = fc.WithFmtp(fmtp[c.Type])
+		}
+	}
 	return &out, nil
 }
 
@@ -480,6 +535,7 @@ type AudioConfig struct {
 	Codec    rtp.AudioCodec
 	Type     byte
 	DTMFType byte
+	CNType   byte
 }
 
 func SelectAudio(desc MediaDesc, answer bool) (*AudioConfig, error) {
@@ -505,13 +561,29 @@ func SelectAudio(desc MediaDesc, answer bool) (*AudioConfig, error) {
 	if audioCodec == nil {
 		return nil, ErrNoCommonMedia
 	}
+	cnType := desc.CNType
+	if !cnSupported(audioCodec) {
+		cnType = 0
+	}
 	return &AudioConfig{
 		Codec:    audioCodec,
 		Type:     audioType,
 		DTMFType: desc.DTMFType,
+		CNType:   cnType,
 	}, nil
 }
 
+// cnSupported checks if comfort noise (CN/8000) can be used with the codec.
+// CN is only defined for codecs with 8000 Hz RTP clock rate (RFC 3389).
+func cnSupported(c media.Codec) bool {
+	info := c.Info()
+	rate := info.RTPClockRate
+	if rate == 0 {
+		rate = info.SampleRate
+	}
+	return rate == cn.SampleRate
+}
+
 func SelectCrypto(offer, answer []srtp.Profile, swap bool) (*srtp.Config, *srtp.Profile, error) {
 	if len(offer) == 0 {
 		return nil, nil, nil
@@ -25,6 +25,7 @@ import (
 	"github.com/livekit/media-sdk"
 	"github.com/livekit/media-sdk/g711"
 	"github.com/livekit/media-sdk/g722"
+	"github.com/livekit/media-sdk/l16"
 	"github.com/livekit/media-sdk/rtp"
 	. "github.com/livekit/media-sdk/sdp"
 	"github.com/livekit/media-sdk/srtp"
@@ -48,12 +49,13 @@ func TestSDPMediaOffer(t *testing.T) {
 			Media:   "audio",
 			Port:    sdp.RangedPort{Value: port},
 			Protos:  []string{"RTP", "AVP"},
-			Formats: []string{"9", "0", "8", "101"},
+			Formats: []string{"9", "0", "8", "13", "101"},
 		},
 		Attributes: []sdp.Attribute{
 			{Key: "rtpmap", Value: "9 G722/8000"},
 			{Key: "rtpmap", Value: "0 PCMU/8000"},
 			{Key: "rtpmap", Value: "8 PCMA/8000"},
+			{Key: "rtpmap", Value: "13 CN/8000"},
 			{Key: "rtpmap", Value: "101 telephone-event/8000"},
 			{Key: "fmtp", Value: "101 0-16"},
 			{Key: "ptime", Value: "20"},
@@ -72,12 +74,13 @@ func TestSDPMediaOffer(t *testing.T) {
 			Media:   "audio",
 			Port:    sdp.RangedPort{Value: port},
 			Protos:  []string{"RTP", "SAVP"},
-			Formats: []string{"9", "0", "8", "101"},
+			Formats: []string{"9", "0", "8", "13", "101"},
 		},
 		Attributes: []sdp.Attribute{
 			{Key: "rtpmap", Value: "9 G722/8000"},
 			{Key: "rtpmap", Value: "0 PCMU/8000"},
 			{Key: "rtpmap", Value: "8 PCMA/8000"},
+			{Key: "rtpmap", Value: "13 CN/8000"},
 			{Key: "rtpmap", Value: "101 telephone-event/8000"},
 			{Key: "fmtp", Value: "101 0-16"},
 			{Key: "crypto", Value: "1 AES_CM_128_HMAC_SHA1_80 inline:" + getInline(offer.Attributes[i+0].Value)},
@@ -99,11 +102,12 @@ func TestSDPMediaOffer(t *testing.T) {
 			Media:   "audio",
 			Port:    sdp.RangedPort{Value: port},
 			Protos:  []string{"RTP", "AVP"},
-			Formats: []string{"0", "8", "101"},
+			Formats: []string{"0", "8", "13", "101"},
 		},
 		Attributes: []sdp.Attribute{
 			{Key: "rtpmap", Value: "0 PCMU/8000"},
 			{Key: "rtpmap", Value: "8 PCMA/8000"},
+			{Key: "rtpmap", Value: "13 CN/8000"},
 			{Key: "rtpmap", Value: "101 telephone-event/8000"},
 			{Key: "fmtp", Value: "101 0-16"},
 			{Key: "ptime", Value: "20"},
@@ -112,6 +116,52 @@ func TestSDPMediaOffer(t *testing.T) {
 	}, offer)
 }
 
+func TestSDPComfortNoiseWideband(t *testing.T) {
+	wideband := l16.SDPName(16000, 1)
+	media.CodecsSetEnabled(map[string]bool{
+		wideband:         true,
+		g722.SDPName:     false,
+		g711.ULawSDPName: false,
+		g711.ALawSDPName: false,
+	})
+	defer media.CodecsSetEnabled(map[string]bool{
+		wideband:         false,
+		g722.SDPName:     true,
+		g711.ULawSDPName: true,
+		g711.ALawSDPName: true,
+	})
+
+	// CN/8000 is not offered without 8 kHz codecs
+	desc, offer, err := OfferMedia(10000, EncryptionNone)
+	require.NoError(t, err)
+	require.Zero(t, desc.CNType)
+	require.NotContains(t, offer.MediaName.Formats, "13")
+	for _, a := range offer.Attributes {
+		require.NotContains(t, a.Value, "CN/8000")
+	}
+
+	// and is not answered for a codec with a different clock rate
+	m, err := ParseMedia(&sdp.MediaDescription{
+		MediaName: sdp.MediaName{
+			Formats: []string{"96", "13", "101"},
+		},
+		Attributes: []sdp.Attribute{
+			{Key: "rtpmap", Value: "96 " + wideband},
+			{Key: "rtpmap", Value: "13 CN/8000"},
+			{Key: "rtpmap", Value: "101 telephone-event/8000"},
+		},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 13, m.CNType)
+	audio, err := SelectAudio(*m, true)
+	require.NoError(t, err)
+	require.Equal(t, getCodec(wideband), audio.Codec)
+	require.Zero(t, audio.CNType)
+
+	answer := AnswerMedia(10000, &AudioConfig{Codec: audio.Codec, Type: 96, CNType: 13}, nil)
+	require.Equal(t, []string{"96"}, answer.MediaName.Formats)
+}
+
 func getCodec(name string) rtp.AudioCodec {
 	return CodecByName(name).(rtp.AudioCodec)
 }
@@ -141,6 +191,38 @@ func TestSDPMediaAnswer(t *testing.T) {
 				DTMFType: 101,
 			},
 		},
+		{
+			name: "comfort noise",
+			offer: sdp.MediaDescription{
+				MediaName: sdp.MediaName{
+					Formats: []string{"0", "13", "101"},
+				},
+				Attributes: []sdp.Attribute{
+					{Key: "rtpmap", Value: "0 PCMU/8000"},
+					{Key: "rtpmap", Value: "13 CN/8000"},
+					{Key: "rtpmap", Value: "101 telephone-event/8000"},
+				},
+			},
+			exp: &AudioConfig{
+				Codec:    getCodec(g711.ULawSDPName),
+				Type:     0,
+				DTMFType: 101,
+				CNType:   13,
+			},
+		},
+		{
+			name: "comfort noise static",
+			offer: sdp.MediaDescription{
+				MediaName: sdp.MediaName{
+					Formats: []string{"8", "13"},
+				},
+			},
+			exp: &AudioConfig{
+				Codec:  getCodec(g711.ALawSDPName),
+				Type:   8,
+				CNType: 13,
+			},
+		},
 		{
 			name: "lowercase",
 			offer: sdp.MediaDescription{
@@ -325,12 +407,13 @@ func TestSDPMediaAnswer(t *testing.T) {
 			Media:   "audio",
 			Port:    sdp.RangedPort{Value: port},
 			Protos:  []string{"RTP", "AVP"},
-			Formats: []string{"9", "0", "8", "101"},
+			Formats: []string{"9", "0", "8", "13", "101"},
 		},
 		Attributes: []sdp.Attribute{
 			{Key: "rtpmap", Value: "9 G722/8000"},
 			{Key: "rtpmap", Value: "0 PCMU/8000"},
 			{Key: "rtpmap", Value: "8 PCMA/8000"},
+			{Key: "rtpmap", Value: "13 CN/8000"},
 			{Key: "rtpmap", Value: "101 telephone-event/8000"},
 			{Key: "fmtp", Value: "101 0-16"},
 			{Key: "ptime", Value: "20"},
@@ -0,0 +1,115 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package srtp
+
+import (
+	"io"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/livekit/media-sdk/rtp"
+)
+
+const muxQueueSize = 32
+
+// newMuxConn splits RTP and RTCP packets multiplexed on a single connection (RFC 5761).
+//
+// SRTP and SRTCP sessions both expect to own the connection, so each of them gets a separate endpoint.
+// Closing any of the endpoints closes the underlying connection.
+func newMuxConn(conn net.Conn) (rtpConn, rtcpConn net.Conn) {
+	m := &muxConn{
+		conn:   conn,
+		closed: make(chan struct{}),
+	}
+	m.rtp = &muxEndpoint{Conn: conn, m: m, recv: make(chan []byte, muxQueueSize)}
+	m.rtcp = &muxEndpoint{Conn: conn, m: m, recv: make(chan []byte, muxQueueSize)}
+	go m.run()
+	return m.rtp, m.rtcp
+}
+
+type muxConn struct {
+	conn      net.Conn
+	rtp, rtcp *muxEndpoint
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (m *muxConn) run() {
+	defer m.close()
+	buf := make([]byte, rtp.MTUSize+1)
+	for {
+		n, err := m.conn.Read(buf)
+		if err != nil {
+			return
+		}
+		if n > rtp.MTUSize {
+			continue // ignore partial messages
+		}
+		data := slices.Clone(buf[:n])
+		e := m.rtp
+		if rtp.IsRTCP(data) {
+			e = m.rtcp
+		}
+		select {
+		case e.recv <- data:
+		default:
+			// reader is too slow, drop the packet
+		}
+	}
+}
+
+func (m *muxConn) close() error {
+	var err error
+	m.closeOnce.Do(func() {
+		close(m.closed)
+		err = m.conn.Close()
+	})
+	return err
+}
+
+type muxEndpoint struct {
+	net.Conn
+	m    *muxConn
+	recv chan []byte
+}
+
+func (e *muxEndpoint) Read(b []byte) (int, error) {
+	select {
+	case data := <-e.recv:
+		n := copy(b, data)
+		if n < len(data) {
+			return n, io.ErrShortBuffer
+		}
+		return n, nil
+	case <-e.m.closed:
+		return 0, io.EOF
+	}
+}
+
+func (e *muxEndpoint) Close() error {
+	return e.m.close()
+}
+
+// SetDeadline only sets the write deadline, since reads are served by the shared read loop.
+func (e *muxEndpoint) SetDeadline(t time.Time) error {
+	return e.Conn.SetWriteDeadline(t)
+}
+
+// SetReadDeadline is ignored, since reads are served by the shared read loop.
+func (e *muxEndpoint) SetReadDeadline(t time.Time) error {
+	return nil
+}
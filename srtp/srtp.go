@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net"
 
+	"github.com/pion/rtcp"
 	prtp "github.com/pion/rtp"
 	"github.com/pion/srtp/v3"
 
@@ -98,17 +99,48 @@ type Profile struct {
 type Config = srtp.Config
 type SessionKeys = srtp.SessionKeys
 
-func NewSession(log logger.Logger, conn net.Conn, conf *Config) (rtp.Session, error) {
-	s, err := srtp.NewSessionSRTP(conn, conf)
+func NewSession(log logger.Logger, conn net.Conn, conf *Config, opts ...rtp.SessionOption) (rtp.Session, error) {
+	sconf := rtp.NewSessionConfig(opts...)
+	if sconf.RTCP == nil {
+		s, err := srtp.NewSessionSRTP(conn, conf)
+		if err != nil {
+			return nil, err
+		}
+		return &session{log: log, s: s, rtcp: rtp.NewRTCPReporter(log, nil, rtp.RTCPConfig{})}, nil
+	}
+	rtpConn, rtcpConn := conn, sconf.RTCP.Conn
+	if rtcpConn == nil {
+		rtpConn, rtcpConn = newMuxConn(conn)
+	}
+	s, err := srtp.NewSessionSRTP(rtpConn, conf)
 	if err != nil {
 		return nil, err
 	}
-	return &session{log: log, s: s}, nil
+	sc, err := srtp.NewSessionSRTCP(rtcpConn, conf)
+	if err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	w, err := sc.OpenWriteStream()
+	if err != nil {
+		_ = s.Close()
+		_ = sc.Close()
+		return nil, err
+	}
+	sess := &session{log: log, s: s, sc: sc}
+	sess.rtcp = rtp.NewRTCPReporter(log, func(data []byte) error {
+		_, err := w.Write(data)
+		return err
+	}, *sconf.RTCP)
+	go sess.acceptRTCP()
+	return sess, nil
 }
 
 type session struct {
-	log logger.Logger
-	s   *srtp.SessionSRTP
+	log  logger.Logger
+	s    *srtp.SessionSRTP
+	sc   *srtp.SessionSRTCP
+	rtcp *rtp.RTCPReporter
 }
 
 func (s *session) OpenWriteStream() (rtp.WriteStream, error) {
@@ -116,7 +148,7 @@ func (s *session) OpenWriteStream() (rtp.WriteStream, error) {
 	if err != nil {
 		return nil, err
 	}
-	return writeStream{w: w}, nil
+	return writeStream{w: w, rtcp: s.rtcp}, nil
 }
 
 func (s *session) AcceptStream() (rtp.ReadStream, uint32, error) {
@@ -124,15 +156,78 @@ func (s *session) AcceptStream() (rtp.ReadStream, uint32, error) {
 	if err != nil {
 		return nil, 0, err
 	}
-	return readStream{r: r}, ssrc, nil
+	return readStream{r: r, rtcp: s.rtcp}, ssrc, nil
+}
+
+func (s *session) acceptRTCP() {
+	for {
+		r, ssrc, err := s.sc.AcceptStream()
+		if err != nil {
+			return
+		}
+		go s.readRTCP(r, ssrc)
+	}
+}
+
+func (s *session) readRTCP(r *srtp.ReadStreamSRTCP, ssrc uint32) {
+	buf := make([]byte, rtp.MTUSize)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		pkts, err := rtcp.Unmarshal(buf[:n])
+		if err != nil {
+			continue
+		}
+		// SRTCP delivers compound packets to a stream for each SSRC mentioned in it.
+		// Only handle packets on the stream of the sender to avoid counting them multiple times.
+		if rtcpSender(pkts) != ssrc {
+			continue
+		}
+		s.rtcp.HandleRTCPPackets(pkts)
+	}
+}
+
+// rtcpSender returns an SSRC of the sender of the compound RTCP packet.
+func rtcpSender(pkts []rtcp.Packet) uint32 {
+	if len(pkts) == 0 {
+		return 0
+	}
+	switch p := pkts[0].(type) {
+	case *rtcp.SenderReport:
+		return p.SSRC
+	case *rtcp.ReceiverReport:
+		return p.SSRC
+	case *rtcp.SourceDescription:
+		if len(p.Chunks) != 0 {
+			return p.Chunks[0].Source
+		}
+	case *rtcp.Goodbye:
+		if len(p.Sources) != 0 {
+			return p.Sources[0]
+		}
+	}
+	return 0
+}
+
+func (s *session) Stats() *rtp.SessionStats {
+	return s.rtcp.Stats()
 }
 
 func (s *session) Close() error {
+	if err := s.rtcp.Close(); err != nil {
+		s.log.Debugw("cannot send RTCP BYE", "error", err)
+	}
+	if s.sc != nil {
+		_ = s.sc.Close()
+	}
 	return s.s.Close()
 }
 
 type writeStream struct {
-	w *srtp.WriteStreamSRTP
+	w    *srtp.WriteStreamSRTP
+	rtcp *rtp.RTCPReporter
 }
 
 func (w writeStream) String() string {
@@ -140,11 +235,17 @@ func (w writeStream) String() string {
 }
 
 func (w writeStream) WriteRTP(h *prtp.Header, payload []byte) (int, error) {
-	return w.w.WriteRTP(h, payload)
+	n, err := w.w.WriteRTP(h, payload)
+	if err != nil {
+		return n, err
+	}
+	w.rtcp.OnSend(h, len(payload))
+	return n, nil
 }
 
 type readStream struct {
-	r *srtp.ReadStreamSRTP
+	r    *srtp.ReadStreamSRTP
+	rtcp *rtp.RTCPReporter
 }
 
 func (r readStream) ReadRTP(h *prtp.Header, payload []byte) (int, error) {
@@ -158,6 +259,7 @@ func (r readStream) ReadRTP(h *prtp.Header, payload []byte) (int, error) {
 		return 0, err
 	}
 	*h = p.Header
+	r.rtcp.OnReceive(&p.Header, len(p.Payload))
 	n = copy(payload, p.Payload)
 	return n, nil
 }
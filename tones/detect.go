@@ -0,0 +1,398 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tones
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/internal/goertzel"
+)
+
+// SignalType is a type of the call progress signal.
+type SignalType int
+
+const (
+	// SignalUnknown is reported for audio that doesn't match any known signal, for example a voice announcement.
+	SignalUnknown SignalType = iota
+	SignalSilence
+	SignalDial
+	SignalRingback
+	SignalBusy
+	SignalCongestion
+	// SignalSIT is a special information tone, which usually precedes an announcement (ITU-T E.180).
+	SignalSIT
+	SignalCallWaiting
+)
+
+func (t SignalType) String() string {
+	switch t {
+	case SignalUnknown:
+		return "unknown"
+	case SignalSilence:
+		return "silence"
+	case SignalDial:
+		return "dial"
+	case SignalRingback:
+		return "ringback"
+	case SignalBusy:
+		return "busy"
+	case SignalCongestion:
+		return "congestion"
+	case SignalSIT:
+		return "sit"
+	case SignalCallWaiting:
+		return "call_waiting"
+	}
+	return fmt.Sprintf("SignalType(%d)", int(t))
+}
+
+// Signal is a call progress signal definition.
+type Signal struct {
+	Type  SignalType
+	Tones []Tone
+}
+
+// Detection is a call progress signal detected by the Detector.
+type Detection struct {
+	Type SignalType
+	// Start is the time when the signal started, relative to the start of the audio stream.
+	Start time.Duration
+	// Time is the time when the signal was detected, relative to the start of the audio stream.
+	Time time.Duration
+}
+
+// Detection parameters.
+const (
+	// detectWindow is the analysis window. It must be long enough to separate tones that are 50 Hz apart.
+	detectWindow = 40 * time.Millisecond
+	// detectStep is the step between analysis windows.
+	detectStep = 20 * time.Millisecond
+	// detectDebounce is the number of windows with a new signal required to start a new segment.
+	detectDebounce = 2
+	// detectMaxSegments is the number of recent segments kept for cadence matching.
+	detectMaxSegments = 16
+
+	// detectMinLevel is the min level of the tone in dBFS.
+	detectMinLevel = -45
+	// detectSilenceLevel is the level in dBFS below which the audio is considered silent.
+	detectSilenceLevel = -50
+	// detectToneRatio is the min ratio of the tone power to the total power of the signal.
+	detectToneRatio = 0.6
+	// detectFreqRatio is the min ratio of each frequency power to the total power for multi-frequency tones.
+	detectFreqRatio = 0.15
+
+	// detectTolerance is the relative tolerance for cadence durations.
+	detectTolerance = 0.2
+	// detectMinTolerance is the min tolerance for cadence durations, which accounts for the analysis window.
+	detectMinTolerance = 80 * time.Millisecond
+	// detectContinuous is the min duration of the continuous tone (without cadence) to be detected.
+	detectContinuous = time.Second
+
+	// DetectSilenceTimeout is the duration of silence after which SignalSilence is reported.
+	// It must be longer than the silence in ringback cadences.
+	DetectSilenceTimeout = 6 * time.Second
+	// DetectUnknownTimeout is the duration of non-tone audio after which SignalUnknown is reported.
+	DetectUnknownTimeout = time.Second
+)
+
+const (
+	labelSilence = -1 // silent segment
+	labelOther   = -2 // audio that is not a known tone
+)
+
+// NewDetector creates a PCM writer that detects call progress signals by matching frequencies and cadences
+// of the audio with given signal definitions.
+//
+// Handler is called each time the detected signal changes.
+func NewDetector(sampleRate int, signals []Signal, h func(d Detection)) *Detector {
+	d := &Detector{
+		h:          h,
+		sampleRate: sampleRate,
+		n:          int(time.Duration(sampleRate) * detectWindow / time.Second),
+		step:       int(time.Duration(sampleRate) * detectStep / time.Second),
+		last:       -1,
+	}
+	fullScale := float64(math.MaxInt16) * float64(math.MaxInt16) / 2
+	d.minPower = fullScale * math.Pow(10, detectMinLevel/10.0)
+	d.silencePower = fullScale * math.Pow(10, detectSilenceLevel/10.0)
+	for _, s := range signals {
+		if len(s.Tones) == 0 {
+			continue
+		}
+		p := pattern{typ: s.Type}
+		for _, t := range s.Tones {
+			p.steps = append(p.steps, cadenceStep{label: d.addFreqSet(t), dur: t.Dur})
+			if t.Silence != 0 {
+				p.steps = append(p.steps, cadenceStep{label: labelSilence, dur: t.Silence})
+			}
+		}
+		d.patterns = append(d.patterns, p)
+	}
+	return d
+}
+
+// Detector detects call progress signals. See NewDetector.
+type Detector struct {
+	h            func(d Detection)
+	sampleRate   int
+	n            int // window size
+	step         int // window step
+	minPower     float64
+	silencePower float64
+
+	freqs    []Hz              // all frequencies used in signal definitions
+	filters  []goertzel.Filter // filters for each frequency
+	sets     []freqSet
+	patterns []pattern
+
+	buf   media.PCM16Sample
+	pos   int // position of the current window in samples
+	power []float64
+
+	segs    []segment // recent segments; the last one is in progress
+	pending int       // label of the pending segment
+	pendCnt int       // number of windows with a pending label
+	last    SignalType
+}
+
+type cadenceStep struct {
+	label int // frequency set index or labelSilence
+	dur   time.Duration
+}
+
+type pattern struct {
+	typ   SignalType
+	steps []cadenceStep
+}
+
+// freqSet is a set of frequencies of a single tone, as indexes in Detector.freqs.
+type freqSet struct {
+	freq []int // tone frequencies
+	side []int // sidebands of the amplitude modulated tone, if any
+}
+
+type segment struct {
+	label int
+	start int // in samples
+	end   int // in samples
+}
+
+func (d *Detector) addFreq(f Hz) int {
+	i := slices.Index(d.freqs, f)
+	if i < 0 {
+		i = len(d.freqs)
+		d.freqs = append(d.freqs, f)
+		d.filters = append(d.filters, goertzel.New(float64(f), d.sampleRate))
+	}
+	return i
+}
+
+func (d *Detector) addFreqSet(t Tone) int {
+	var set freqSet
+	for _, f := range t.Freq {
+		set.freq = append(set.freq, d.addFreq(f))
+		if t.Mod != 0 && t.Mod < f {
+			set.side = append(set.side, d.addFreq(f-t.Mod), d.addFreq(f+t.Mod))
+		}
+	}
+	slices.Sort(set.freq)
+	slices.Sort(set.side)
+	for i, s := range d.sets {
+		if slices.Equal(s.freq, set.freq) && slices.Equal(s.side, set.side) {
+			return i
+		}
+	}
+	d.sets = append(d.sets, set)
+	return len(d.sets) - 1
+}
+
+func (d *Detector) String() string {
+	return fmt.Sprintf("ToneDetector(%d)", d.sampleRate)
+}
+
+func (d *Detector) SampleRate() int {
+	return d.sampleRate
+}
+
+func (d *Detector) Close() error {
+	return nil
+}
+
+func (d *Detector) WriteSample(in media.PCM16Sample) error {
+	d.buf = append(d.buf, in...)
+	for len(d.buf) >= d.n {
+		d.update(d.classify(d.buf[:d.n]))
+		d.buf = d.buf[:copy(d.buf, d.buf[d.step:])]
+		d.pos += d.step
+	}
+	return nil
+}
+
+// classify a single window and return its label.
+func (d *Detector) classify(w media.PCM16Sample) int {
+	var total float64
+	for _, v := range w {
+		total += float64(v) * float64(v)
+	}
+	total /= float64(len(w))
+	if total < d.silencePower {
+		return labelSilence
+	}
+	if total < d.minPower {
+		return labelOther
+	}
+	d.power = d.power[:0]
+	for _, f := range d.filters {
+		d.power = append(d.power, f.Power(w))
+	}
+	best, bestRatio := labelOther, 0.0
+	for i, set := range d.sets {
+		var sum float64
+		ok := true
+		for _, j := range set.freq {
+			sum += d.power[j]
+			if len(set.freq) > 1 && d.power[j] < total*detectFreqRatio {
+				ok = false
+			}
+		}
+		for _, j := range set.side {
+			sum += d.power[j]
+		}
+		if ratio := sum / total; ok && ratio >= detectToneRatio && ratio > bestRatio {
+			best, bestRatio = i, ratio
+		}
+	}
+	return best
+}
+
+// update segments with the label of the next window.
+func (d *Detector) update(label int) {
+	end := d.pos + d.step
+	if len(d.segs) == 0 {
+		d.segs = append(d.segs, segment{label: label, start: d.pos, end: end})
+		d.detect()
+		return
+	}
+	cur := &d.segs[len(d.segs)-1]
+	if label == cur.label {
+		d.pendCnt = 0
+		cur.end = end
+		d.detect()
+		return
+	}
+	if label != d.pending {
+		d.pending, d.pendCnt = label, 0
+	}
+	d.pendCnt++
+	if d.pendCnt < detectDebounce {
+		cur.end = end
+		return
+	}
+	// New segment starts at the first window with the new label.
+	start := end - d.pendCnt*d.step
+	cur.end = start
+	d.segs = append(d.segs, segment{label: label, start: start, end: end})
+	if len(d.segs) > detectMaxSegments {
+		d.segs = slices.Delete(d.segs, 0, len(d.segs)-detectMaxSegments)
+	}
+	d.pendCnt = 0
+	d.detect()
+}
+
+func (d *Detector) dur(samples int) time.Duration {
+	return time.Duration(samples) * time.Second / time.Duration(d.sampleRate)
+}
+
+func durMatch(got, exp time.Duration) bool {
+	tol := max(time.Duration(float64(exp)*detectTolerance), detectMinTolerance)
+	return got >= exp-tol && got <= exp+tol
+}
+
+// match checks if the recent segments match the cadence pattern and returns the start of the first matched segment.
+func (d *Detector) match(p *pattern) (int, bool) {
+	cur := d.segs[len(d.segs)-1]
+	if len(p.steps) == 1 && p.steps[0].dur == 0 {
+		// continuous tone
+		if cur.label == p.steps[0].label && d.dur(cur.end-cur.start) >= detectContinuous {
+			return cur.start, true
+		}
+		return 0, false
+	}
+	need := max(len(p.steps), 2)
+	for rot := range p.steps {
+		k := rot
+		cnt := 0
+		start := 0
+		for i := len(d.segs) - 1; i >= 0 && cnt < need; i-- {
+			s := d.segs[i]
+			step := p.steps[k]
+			dur := d.dur(s.end - s.start)
+			if s.label != step.label {
+				break
+			}
+			if i == len(d.segs)-1 {
+				// Segment is in progress, it may still be shorter than expected.
+				if dur > step.dur+max(time.Duration(float64(step.dur)*detectTolerance), detectMinTolerance) {
+					break
+				}
+				if !durMatch(dur, step.dur) {
+					// Not yet matched, but the cadence might match with previous segments.
+					k = (k + len(p.steps) - 1) % len(p.steps)
+					continue
+				}
+			} else if !durMatch(dur, step.dur) {
+				break
+			}
+			cnt++
+			start = s.start
+			k = (k + len(p.steps) - 1) % len(p.steps)
+		}
+		if cnt >= need {
+			return start, true
+		}
+	}
+	return 0, false
+}
+
+// detect the signal based on recent segments and report it, if it changed.
+func (d *Detector) detect() {
+	cur := d.segs[len(d.segs)-1]
+	typ, start, ok := SignalType(0), 0, false
+	for i := range d.patterns {
+		if start, ok = d.match(&d.patterns[i]); ok {
+			typ = d.patterns[i].typ
+			break
+		}
+	}
+	if !ok {
+		switch {
+		case cur.label == labelSilence && d.dur(cur.end-cur.start) >= DetectSilenceTimeout:
+			typ, start, ok = SignalSilence, cur.start, true
+		case cur.label == labelOther && d.dur(cur.end-cur.start) >= DetectUnknownTimeout:
+			typ, start, ok = SignalUnknown, cur.start, true
+		}
+	}
+	if !ok || typ == d.last {
+		return
+	}
+	d.last = typ
+	if d.h != nil {
+		d.h(Detection{Type: typ, Start: d.dur(start), Time: d.dur(cur.end)})
+	}
+}
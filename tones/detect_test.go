@@ -0,0 +1,109 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tones
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+)
+
+type detectorTest struct {
+	mu  sync.Mutex
+	got []Detection
+}
+
+func (d *detectorTest) Detected() []SignalType {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var out []SignalType
+	for _, v := range d.got {
+		out = append(out, v.Type)
+	}
+	return out
+}
+
+func playAndDetect(t *testing.T, sampleRate int, signals []Signal, tones []Tone, dur time.Duration) *detectorTest {
+	res := &detectorTest{}
+	d := NewDetector(sampleRate, signals, func(v Detection) {
+		res.mu.Lock()
+		defer res.mu.Unlock()
+		res.got = append(res.got, v)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), dur)
+	defer cancel()
+	err := Play(ctx, d, math.MaxInt16/4, tones)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	return res
+}
+
+func TestDetector(t *testing.T) {
+	cases := []struct {
+		name  string
+		rate  int
+		tones []Tone
+		dur   time.Duration
+		exp   SignalType
+		at    time.Duration // max detection time
+	}{
+		{name: "dial", rate: 8000, tones: ETSIDial, dur: 1500 * time.Millisecond, exp: SignalDial, at: 1200 * time.Millisecond},
+		{name: "ringback", rate: 16000, tones: ETSIRinging, dur: 5 * time.Second, exp: SignalRingback, at: 4500 * time.Millisecond},
+		{name: "busy", rate: 8000, tones: ETSIBusy, dur: 2 * time.Second, exp: SignalBusy, at: 1500 * time.Millisecond},
+		{name: "congestion", rate: 48000, tones: ETSICongestion, dur: time.Second, exp: SignalCongestion, at: 800 * time.Millisecond},
+		{name: "sit", rate: 8000, tones: SIT, dur: 2500 * time.Millisecond, exp: SignalSIT, at: 2200 * time.Millisecond},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			res := playAndDetect(t, c.rate, ETSISignals, c.tones, c.dur)
+			require.Equal(t, []SignalType{c.exp}, res.Detected())
+			require.LessOrEqual(t, res.got[0].Time, c.at)
+			require.LessOrEqual(t, res.got[0].Start, res.got[0].Time)
+		})
+	}
+}
+
+func TestDetectorSilence(t *testing.T) {
+	var got []Detection
+	d := NewDetector(8000, ETSISignals, func(v Detection) {
+		got = append(got, v)
+	})
+	buf := make(media.PCM16Sample, 160)
+	for range int(DetectSilenceTimeout/(20*time.Millisecond)) + 5 {
+		require.NoError(t, d.WriteSample(buf))
+	}
+	require.Len(t, got, 1)
+	require.Equal(t, SignalSilence, got[0].Type)
+	require.Equal(t, time.Duration(0), got[0].Start)
+
+	// noise is not a tone
+	rnd := rand.New(rand.NewPCG(1, 2))
+	for range 100 {
+		for i := range buf {
+			buf[i] = int16(3000 * rnd.NormFloat64())
+		}
+		require.NoError(t, d.WriteSample(buf))
+	}
+	require.Len(t, got, 2)
+	require.Equal(t, SignalUnknown, got[1].Type)
+	require.InDelta(t, DetectSilenceTimeout+100*time.Millisecond, got[1].Start, float64(50*time.Millisecond))
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tones
+
+import (
+	"strings"
+	"time"
+)
+
+// Plan is a set of call progress tones used in a region.
+//
+// Values are based on ITU-T E.180 Supplement 2 (Various tones used in national networks).
+type Plan struct {
+	Name        string
+	Dial        []Tone
+	Ringback    []Tone
+	Busy        []Tone
+	Congestion  []Tone
+	CallWaiting []Tone
+	SIT         []Tone
+}
+
+// Signals returns signal definitions for the Detector.
+//
+// If the plan uses the same cadence for multiple signals, the first one in the following order is detected:
+// SIT, congestion, busy, ringback, call waiting, dial.
+func (p *Plan) Signals() []Signal {
+	var out []Signal
+	add := func(typ SignalType, tones []Tone) {
+		if len(tones) != 0 {
+			out = append(out, Signal{Type: typ, Tones: tones})
+		}
+	}
+	add(SignalSIT, p.SIT)
+	add(SignalCongestion, p.Congestion)
+	add(SignalBusy, p.Busy)
+	add(SignalRingback, p.Ringback)
+	add(SignalCallWaiting, p.CallWaiting)
+	add(SignalDial, p.Dial)
+	return out
+}
+
+var (
+	// PlanETSI is a plan recommended by ETSI (TR 101 041) and used by most European countries.
+	PlanETSI = &Plan{
+		Name:       "ETSI",
+		Dial:       ETSIDial,
+		Ringback:   ETSIRinging,
+		Busy:       ETSIBusy,
+		Congestion: ETSICongestion,
+		CallWaiting: []Tone{
+			{Freq: []Hz{425}, Dur: 200 * time.Millisecond, Silence: 200 * time.Millisecond},
+			{Freq: []Hz{425}, Dur: 200 * time.Millisecond, Silence: 9 * time.Second},
+		},
+		SIT: SIT,
+	}
+
+	// PlanUS is a plan used in the North American Numbering Plan (NANP) countries.
+	PlanUS = &Plan{
+		Name:        "US",
+		Dial:        []Tone{{Freq: []Hz{350, 440}}},
+		Ringback:    []Tone{{Freq: []Hz{440, 480}, Dur: 2 * time.Second, Silence: 4 * time.Second}},
+		Busy:        []Tone{{Freq: []Hz{480, 620}, Dur: 500 * time.Millisecond, Silence: 500 * time.Millisecond}},
+		Congestion:  []Tone{{Freq: []Hz{480, 620}, Dur: 250 * time.Millisecond, Silence: 250 * time.Millisecond}},
+		CallWaiting: []Tone{{Freq: []Hz{440}, Dur: 300 * time.Millisecond, Silence: 9700 * time.Millisecond}},
+		SIT:         SIT,
+	}
+
+	// PlanUK is a plan used in the United Kingdom.
+	PlanUK = &Plan{
+		Name: "UK",
+		Dial: []Tone{{Freq: []Hz{350, 440}}},
+		Ringback: []Tone{
+			{Freq: []Hz{400, 450}, Dur: 400 * time.Millisecond, Silence: 200 * time.Millisecond},
+			{Freq: []Hz{400, 450}, Dur: 400 * time.Millisecond, Silence: 2 * time.Second},
+		},
+		Busy: []Tone{{Freq: []Hz{400}, Dur: 375 * time.Millisecond, Silence: 375 * time.Millisecond}},
+		Congestion: []Tone{
+			{Freq: []Hz{400}, Dur: 400 * time.Millisecond, Silence: 350 * time.Millisecond},
+			{Freq: []Hz{400}, Dur: 225 * time.Millisecond, Silence: 525 * time.Millisecond},
+		},
+		CallWaiting: []Tone{{Freq: []Hz{400}, Dur: 100 * time.Millisecond, Silence: 3 * time.Second}},
+		SIT:         SIT,
+	}
+
+	// PlanJP is a plan used in Japan. Congestion uses the same tone as busy.
+	PlanJP = &Plan{
+		Name:       "JP",
+		Dial:       []Tone{{Freq: []Hz{400}}},
+		Ringback:   []Tone{{Freq: []Hz{400}, Mod: 16, Dur: time.Second, Silence: 2 * time.Second}},
+		Busy:       []Tone{{Freq: []Hz{400}, Dur: 500 * time.Millisecond, Silence: 500 * time.Millisecond}},
+		Congestion: []Tone{{Freq: []Hz{400}, Dur: 500 * time.Millisecond, Silence: 500 * time.Millisecond}},
+		CallWaiting: []Tone{
+			{Freq: []Hz{400}, Mod: 16, Dur: 500 * time.Millisecond, Silence: 50 * time.Millisecond},
+			{Freq: []Hz{400}, Mod: 16, Dur: 500 * time.Millisecond, Silence: 3 * time.Second},
+		},
+		SIT: SIT,
+	}
+
+	// PlanAU is a plan used in Australia. Congestion uses the same cadence as busy, but alternates the level.
+	PlanAU = &Plan{
+		Name: "AU",
+		Dial: []Tone{{Freq: []Hz{425}, Mod: 25}},
+		Ringback: []Tone{
+			{Freq: []Hz{425}, Mod: 25, Dur: 400 * time.Millisecond, Silence: 200 * time.Millisecond},
+			{Freq: []Hz{425}, Mod: 25, Dur: 400 * time.Millisecond, Silence: 2 * time.Second},
+		},
+		Busy:       []Tone{{Freq: []Hz{425}, Dur: 375 * time.Millisecond, Silence: 375 * time.Millisecond}},
+		Congestion: []Tone{{Freq: []Hz{425}, Dur: 375 * time.Millisecond, Silence: 375 * time.Millisecond}},
+		CallWaiting: []Tone{
+			{Freq: []Hz{425}, Dur: 200 * time.Millisecond, Silence: 200 * time.Millisecond},
+			{Freq: []Hz{425}, Dur: 200 * time.Millisecond, Silence: 4400 * time.Millisecond},
+		},
+		SIT: SIT,
+	}
+
+	// PlanIN is a plan used in India.
+	PlanIN = &Plan{
+		Name: "IN",
+		Dial: []Tone{{Freq: []Hz{400}, Mod: 25}},
+		Ringback: []Tone{
+			{Freq: []Hz{400}, Mod: 25, Dur: 400 * time.Millisecond, Silence: 200 * time.Millisecond},
+			{Freq: []Hz{400}, Mod: 25, Dur: 400 * time.Millisecond, Silence: 2 * time.Second},
+		},
+		Busy:       []Tone{{Freq: []Hz{400}, Dur: 750 * time.Millisecond, Silence: 750 * time.Millisecond}},
+		Congestion: []Tone{{Freq: []Hz{400}, Dur: 250 * time.Millisecond, Silence: 250 * time.Millisecond}},
+		CallWaiting: []Tone{
+			{Freq: []Hz{400}, Dur: 200 * time.Millisecond, Silence: 100 * time.Millisecond},
+			{Freq: []Hz{400}, Dur: 200 * time.Millisecond, Silence: 7500 * time.Millisecond},
+		},
+		SIT: SIT,
+	}
+
+	// ETSISignals is a set of ETSI call progress signals for the Detector.
+	ETSISignals = PlanETSI.Signals()
+)
+
+var plansByCountry = map[string]*Plan{
+	// NANP
+	"US": PlanUS, "CA": PlanUS, "PR": PlanUS,
+	// UK
+	"GB": PlanUK, "UK": PlanUK,
+	// EU/ETSI
+	"EU": PlanETSI,
+	"AT": PlanETSI, "BE": PlanETSI, "BG": PlanETSI, "CH": PlanETSI, "CY": PlanETSI, "CZ": PlanETSI,
+	"DE": PlanETSI, "DK": PlanETSI, "EE": PlanETSI, "ES": PlanETSI, "FI": PlanETSI, "FR": PlanETSI,
+	"GR": PlanETSI, "HR": PlanETSI, "HU": PlanETSI, "IE": PlanETSI, "IS": PlanETSI, "IT": PlanETSI,
+	"LT": PlanETSI, "LU": PlanETSI, "LV": PlanETSI, "MT": PlanETSI, "NL": PlanETSI, "NO": PlanETSI,
+	"PL": PlanETSI, "PT": PlanETSI, "RO": PlanETSI, "SE": PlanETSI, "SI": PlanETSI, "SK": PlanETSI,
+	// other
+	"JP": PlanJP,
+	"AU": PlanAU,
+	"IN": PlanIN,
+}
+
+// PlanByCountry returns a tone plan for a given ISO 3166-1 alpha-2 country code (case-insensitive).
+// It returns false for unknown countries; PlanETSI is a reasonable fallback in this case.
+func PlanByCountry(code string) (*Plan, bool) {
+	p, ok := plansByCountry[strings.ToUpper(code)]
+	return p, ok
+}
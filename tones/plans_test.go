@@ -0,0 +1,104 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tones
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/internal/goertzel"
+)
+
+func TestPlanByCountry(t *testing.T) {
+	for code, exp := range map[string]*Plan{
+		"us": PlanUS, "CA": PlanUS,
+		"GB": PlanUK, "uk": PlanUK,
+		"DE": PlanETSI, "fr": PlanETSI, "EU": PlanETSI,
+		"JP": PlanJP, "AU": PlanAU, "IN": PlanIN,
+	} {
+		p, ok := PlanByCountry(code)
+		require.True(t, ok, code)
+		require.Equal(t, exp, p, code)
+	}
+	_, ok := PlanByCountry("XX")
+	require.False(t, ok)
+}
+
+func TestPlanSignals(t *testing.T) {
+	var got []SignalType
+	for _, s := range PlanUS.Signals() {
+		got = append(got, s.Type)
+	}
+	require.Equal(t, []SignalType{
+		SignalSIT, SignalCongestion, SignalBusy, SignalRingback, SignalCallWaiting, SignalDial,
+	}, got)
+
+	got = got[:0]
+	for _, s := range (&Plan{Busy: ETSIBusy}).Signals() {
+		got = append(got, s.Type)
+	}
+	require.Equal(t, []SignalType{SignalBusy}, got)
+}
+
+func TestGenerateAM(t *testing.T) {
+	const rate = 8000
+	buf := make(media.PCM16Sample, rate/5)
+	GenerateAM(buf, 0, 200*time.Millisecond, 1000, []Hz{400}, 25)
+
+	// envelope starts at the half of the amplitude and peaks at 10 ms (1/4 of the 25 Hz period)
+	peak := func(from, to int) int {
+		v := 0
+		for _, s := range buf[from:to] {
+			v = max(v, int(s), -int(s))
+		}
+		return v
+	}
+	require.InDelta(t, 1000, peak(70, 90), 20)
+	require.InDelta(t, 0, peak(225, 255), 50)
+	require.InDelta(t, 1000, peak(390, 410), 20)
+
+	// carrier and both sidebands are present
+	pow := func(f Hz) float64 {
+		return goertzel.New(float64(f), rate).Power(buf)
+	}
+	require.Greater(t, pow(400), 100*pow(300))
+	require.Greater(t, pow(375), 100*pow(300))
+	require.Greater(t, pow(425), 100*pow(300))
+}
+
+func TestPlanDetect(t *testing.T) {
+	cases := []struct {
+		name  string
+		plan  *Plan
+		tones func(p *Plan) []Tone
+		dur   time.Duration
+		exp   SignalType
+	}{
+		{name: "us busy", plan: PlanUS, tones: func(p *Plan) []Tone { return p.Busy }, dur: 2 * time.Second, exp: SignalBusy},
+		{name: "uk ringback", plan: PlanUK, tones: func(p *Plan) []Tone { return p.Ringback }, dur: 4 * time.Second, exp: SignalRingback},
+		{name: "in dial", plan: PlanIN, tones: func(p *Plan) []Tone { return p.Dial }, dur: 1500 * time.Millisecond, exp: SignalDial},
+		{name: "au ringback", plan: PlanAU, tones: func(p *Plan) []Tone { return p.Ringback }, dur: 4 * time.Second, exp: SignalRingback},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			res := playAndDetect(t, 8000, c.plan.Signals(), c.tones(c.plan), c.dur)
+			require.Equal(t, []SignalType{c.exp}, res.Detected())
+		})
+	}
+}
@@ -26,6 +26,15 @@ import (
 type Hz uint32
 
 func Generate(buf media.PCM16Sample, ts, dur time.Duration, amp int16, freq []Hz) time.Duration {
+	return generate(buf, ts, dur, amp, freq, 0)
+}
+
+// GenerateAM is similar to Generate, but also modulates the amplitude of the tone with a given frequency.
+func GenerateAM(buf media.PCM16Sample, ts, dur time.Duration, amp int16, freq []Hz, mod Hz) time.Duration {
+	return generate(buf, ts, dur, amp, freq, mod)
+}
+
+func generate(buf media.PCM16Sample, ts, dur time.Duration, amp int16, freq []Hz, mod Hz) time.Duration {
 	for i := range buf {
 		phi := ts + (dur*time.Duration(i))/time.Duration(len(buf))
 		if len(freq) == 0 {
@@ -36,6 +45,10 @@ func Generate(buf media.PCM16Sample, ts, dur time.Duration, amp int16, freq []Hz
 				ph := (phi * time.Duration(hz) * 2).Seconds() * math.Pi
 				sum += math.Sin(ph)
 			}
+			if mod != 0 {
+				ph := (phi * time.Duration(mod) * 2).Seconds() * math.Pi
+				sum *= (1 + math.Sin(ph)) / 2
+			}
 			buf[i] = int16(float64(amp) * sum / float64(len(freq)))
 		}
 	}
@@ -43,15 +56,26 @@ func Generate(buf media.PCM16Sample, ts, dur time.Duration, amp int16, freq []Hz
 }
 
 type Tone struct {
-	Freq    []Hz
+	Freq []Hz
+	// Mod is an optional frequency of the amplitude modulation (100% depth) applied to the tone.
+	// For example, ITU-T E.180 tone "400*25" has Freq 400 and Mod 25.
+	Mod     Hz
 	Dur     time.Duration
 	Silence time.Duration
 }
 
 var (
-	ETSIDial    = []Tone{{Freq: []Hz{425}}}
-	ETSIRinging = []Tone{{Freq: []Hz{425}, Dur: time.Second, Silence: 4 * time.Second}}
-	ETSIBusy    = []Tone{{Freq: []Hz{425}, Dur: time.Second / 2, Silence: time.Second / 2}}
+	ETSIDial       = []Tone{{Freq: []Hz{425}}}
+	ETSIRinging    = []Tone{{Freq: []Hz{425}, Dur: time.Second, Silence: 4 * time.Second}}
+	ETSIBusy       = []Tone{{Freq: []Hz{425}, Dur: time.Second / 2, Silence: time.Second / 2}}
+	ETSICongestion = []Tone{{Freq: []Hz{425}, Dur: time.Second / 4, Silence: time.Second / 4}}
+
+	// SIT is a special information tone (ITU-T E.180), which is the same in all regions.
+	SIT = []Tone{
+		{Freq: []Hz{950}, Dur: 330 * time.Millisecond},
+		{Freq: []Hz{1400}, Dur: 330 * time.Millisecond},
+		{Freq: []Hz{1800}, Dur: 330 * time.Millisecond, Silence: time.Second},
+	}
 )
 
 // Play specified audio tones in a loop until the context is cancelled.
@@ -68,6 +92,7 @@ func Play(ctx context.Context, audio media.Writer[media.PCM16Sample], vol int16,
 	var (
 		ts        time.Duration
 		freq      []Hz
+		mod       Hz
 		remaining time.Duration
 		ind       = -1 // ind%2 is tone and silence, ind/2 is the index in tones
 	)
@@ -90,7 +115,7 @@ func Play(ctx context.Context, audio media.Writer[media.PCM16Sample], vol int16,
 				t, silence = next()
 			}
 			if !silence {
-				freq = t.Freq
+				freq, mod = t.Freq, t.Mod
 				remaining = t.Dur
 			} else {
 				freq = nil
@@ -101,7 +126,7 @@ func Play(ctx context.Context, audio media.Writer[media.PCM16Sample], vol int16,
 		if len(freq) == 0 {
 			pcmBuf.Clear() // silence
 		} else {
-			Generate(pcmBuf, ts, frameDur, vol, freq)
+			generate(pcmBuf, ts, frameDur, vol, freq, mod)
 		}
 		if err := audio.WriteSample(pcmBuf); err != nil {
 			return err
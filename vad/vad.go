@@ -0,0 +1,305 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vad implements voice activity detection.
+package vad
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/livekit/media-sdk"
+)
+
+// Default detection parameters.
+const (
+	DefaultThreshold = 9
+	DefaultMinLevel  = -55
+	DefaultOnset     = 30 * time.Millisecond
+	DefaultHangover  = 300 * time.Millisecond
+)
+
+// Config sets parameters of the voice activity detector. Zero values are replaced with defaults.
+type Config struct {
+	// Threshold is the margin in dB above the noise floor required for speech.
+	Threshold float64
+	// MinLevel is the min level of speech in dBFS.
+	MinLevel float64
+	// Onset is the duration of speech required to switch to the speech state.
+	Onset time.Duration
+	// Hangover is the duration the speech state is kept after the end of speech.
+	Hangover time.Duration
+}
+
+func (c *Config) setDefaults() {
+	if c.Threshold <= 0 {
+		c.Threshold = DefaultThreshold
+	}
+	if c.MinLevel == 0 {
+		c.MinLevel = DefaultMinLevel
+	}
+	if c.Onset <= 0 {
+		c.Onset = DefaultOnset
+	}
+	if c.Hangover <= 0 {
+		c.Hangover = DefaultHangover
+	}
+}
+
+// Event is reported when the speech state changes.
+type Event struct {
+	// Speech is set when the speech starts (onset), and cleared when it ends (after the hangover).
+	Speech bool
+	// Time of the state change, relative to the start of the audio stream.
+	// For the onset, it is the start of the speech.
+	Time time.Duration
+}
+
+// Analysis parameters.
+const (
+	// blockDur is the duration of the analysis block.
+	blockDur = 10 * time.Millisecond
+	// analysisRate is the sample rate used for the periodicity analysis.
+	analysisRate = 8000
+	// pitchMin and pitchMax are the range of pitch periods at analysisRate (400 Hz - 62.5 Hz).
+	pitchMin = 20
+	pitchMax = 128
+	// voicedPeriodicity is the min normalized autocorrelation of voiced speech.
+	voicedPeriodicity = 0.5
+	// noiseZCR and noisePeriodicity describe noise-like blocks: many zero crossings and no periodicity.
+	noiseZCR         = 0.35
+	noisePeriodicity = 0.3
+
+	// noiseFall and noiseRise are smoothing factors of the noise floor estimate.
+	// The noise floor follows energy down quickly, and rises slowly in non-speech blocks.
+	noiseFall = 0.5
+	noiseRise = 0.05
+	// noiseDrift is the noise floor rise in dB per block during speech. It prevents locking in the speech state.
+	noiseDrift = 0.01
+)
+
+// NewProcessor creates a voice activity detection processor. See NewWriter.
+func NewProcessor(conf *Config, h func(e Event)) media.PCM16Processor {
+	return func(w media.WriteCloser[media.PCM16Sample]) media.WriteCloser[media.PCM16Sample] {
+		return NewWriter(w, conf, h)
+	}
+}
+
+// NewWriter creates a PCM writer that detects voice activity and passes the audio to w unchanged.
+//
+// Detector compares the energy of the signal with an adaptive noise floor, and uses periodicity
+// and zero-crossing rate to distinguish voiced speech from noise. Speech must start with a voiced segment,
+// while the speech state is kept by the energy alone.
+//
+// Handler is called on each state change. Voice activity metadata of each frame is passed to w,
+// if it implements media.VoiceActivityWriter.
+func NewWriter(w media.WriteCloser[media.PCM16Sample], conf *Config, h func(e Event)) *Writer {
+	var c Config
+	if conf != nil {
+		c = *conf
+	}
+	c.setDefaults()
+	rate := w.SampleRate()
+	if rate <= 0 {
+		panic("invalid sample rate")
+	}
+	v := &Writer{
+		w:          w,
+		h:          h,
+		conf:       c,
+		sampleRate: rate,
+		block:      int(time.Duration(rate) * blockDur / time.Second),
+		decimate:   max(rate/analysisRate, 1),
+		floor:      c.MinLevel - c.Threshold,
+		onsetStart: -1,
+	}
+	v.vaw, _ = w.(media.VoiceActivityWriter)
+	v.hist = make([]float64, pitchMax+v.block/v.decimate)
+	return v
+}
+
+// Writer detects voice activity. See NewWriter.
+type Writer struct {
+	w          media.WriteCloser[media.PCM16Sample]
+	vaw        media.VoiceActivityWriter
+	h          func(e Event)
+	conf       Config
+	sampleRate int
+	block      int
+	decimate   int
+
+	buf  media.PCM16Sample
+	pos  int       // position of the current block in samples
+	hist []float64 // decimated signal history for periodicity analysis
+
+	floor      float64 // noise floor in dBFS
+	speech     bool
+	onsetStart int // start of the speech candidate in samples, or -1
+	lastSpeech int // end of the last speech block in samples
+}
+
+func (v *Writer) String() string {
+	return fmt.Sprintf("VAD(%d) -> %s", v.sampleRate, v.w)
+}
+
+func (v *Writer) SampleRate() int {
+	return v.sampleRate
+}
+
+func (v *Writer) Close() error {
+	return v.w.Close()
+}
+
+// Speech returns true if the detector is in the speech state.
+func (v *Writer) Speech() bool {
+	return v.speech
+}
+
+// NoiseFloor returns the current noise floor estimate in dBFS.
+func (v *Writer) NoiseFloor() float64 {
+	return v.floor
+}
+
+func (v *Writer) WriteSample(in media.PCM16Sample) error {
+	v.buf = append(v.buf, in...)
+	for len(v.buf) >= v.block {
+		v.update(v.buf[:v.block])
+		v.buf = v.buf[:copy(v.buf, v.buf[v.block:])]
+		v.pos += v.block
+	}
+	if v.vaw != nil {
+		v.vaw.SetVoiceActivity(media.VoiceActivity{Voice: v.speech, Level: Level(in)})
+	}
+	return v.w.WriteSample(in)
+}
+
+func (v *Writer) dur(samples int) time.Duration {
+	return time.Duration(samples) * time.Second / time.Duration(v.sampleRate)
+}
+
+// update the state with the next block.
+func (v *Writer) update(b media.PCM16Sample) {
+	energy, zcr, periodicity := v.analyze(b)
+	end := v.pos + len(b)
+
+	isSpeech := energy >= v.floor+v.conf.Threshold && energy >= v.conf.MinLevel
+	if zcr >= noiseZCR && periodicity < noisePeriodicity {
+		isSpeech = false
+	}
+	if !v.speech && v.onsetStart < 0 && periodicity < voicedPeriodicity {
+		// speech must start with a voiced segment
+		isSpeech = false
+	}
+
+	switch {
+	case energy < v.floor:
+		v.floor += (energy - v.floor) * noiseFall
+	case !isSpeech:
+		v.floor += (energy - v.floor) * noiseRise
+	default:
+		v.floor += noiseDrift
+	}
+
+	if !isSpeech {
+		v.onsetStart = -1
+		if v.speech && v.dur(end-v.lastSpeech) >= v.conf.Hangover {
+			v.speech = false
+			v.report(Event{Speech: false, Time: v.dur(end)})
+		}
+		return
+	}
+	v.lastSpeech = end
+	if v.speech {
+		return
+	}
+	if v.onsetStart < 0 {
+		v.onsetStart = v.pos
+	}
+	if v.dur(end-v.onsetStart) >= v.conf.Onset {
+		v.speech = true
+		v.report(Event{Speech: true, Time: v.dur(v.onsetStart)})
+		v.onsetStart = -1
+	}
+}
+
+func (v *Writer) report(e Event) {
+	if v.h != nil {
+		v.h(e)
+	}
+}
+
+// analyze returns energy of the block in dBFS, zero-crossing rate and periodicity.
+func (v *Writer) analyze(b media.PCM16Sample) (energy, zcr, periodicity float64) {
+	var sum float64
+	crossings := 0
+	for i, s := range b {
+		sum += float64(s) * float64(s)
+		if i > 0 && (s >= 0) != (b[i-1] >= 0) {
+			crossings++
+		}
+	}
+	energy = powerToDB(sum / float64(len(b)))
+	zcr = float64(crossings) / float64(len(b))
+
+	// Periodicity is the max normalized autocorrelation in the pitch range of the decimated signal.
+	n := len(b) / v.decimate
+	copy(v.hist, v.hist[n:])
+	cur := v.hist[len(v.hist)-n:]
+	for i := range cur {
+		var s float64
+		for _, x := range b[i*v.decimate : (i+1)*v.decimate] {
+			s += float64(x)
+		}
+		cur[i] = s / float64(v.decimate)
+	}
+	var e0 float64
+	for _, x := range cur {
+		e0 += x * x
+	}
+	if e0 == 0 {
+		return energy, zcr, 0
+	}
+	off := len(v.hist) - n
+	for lag := pitchMin; lag <= pitchMax; lag++ {
+		var corr, e1 float64
+		for i, x := range cur {
+			y := v.hist[off+i-lag]
+			corr += x * y
+			e1 += y * y
+		}
+		if e1 != 0 {
+			periodicity = max(periodicity, corr/math.Sqrt(e0*e1))
+		}
+	}
+	return energy, zcr, periodicity
+}
+
+func powerToDB(p float64) float64 {
+	const fullScale = float64(math.MaxInt16) * float64(math.MaxInt16)
+	return 10 * math.Log10(max(p, 1e-9)/fullScale)
+}
+
+// Level returns an audio level of the frame in -dBov (0-127), as defined in RFC 6464.
+func Level(in media.PCM16Sample) uint8 {
+	if len(in) == 0 {
+		return 127
+	}
+	var sum float64
+	for _, s := range in {
+		sum += float64(s) * float64(s)
+	}
+	db := powerToDB(sum / float64(len(in)))
+	return uint8(max(min(math.Round(-db), 127), 0))
+}
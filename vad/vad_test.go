@@ -0,0 +1,215 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vad
+
+import (
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/res"
+	"github.com/livekit/media-sdk/res/testdata"
+)
+
+type segment struct {
+	dur    time.Duration
+	voiced bool
+	noise  float64 // amplitude of the white noise
+}
+
+// synth generates voiced speech-like signal (harmonics of 150 Hz with syllable modulation) mixed with white noise.
+func synth(rate int, segs ...segment) media.PCM16Sample {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	var out media.PCM16Sample
+	for _, s := range segs {
+		for i := range int(time.Duration(rate) * s.dur / time.Second) {
+			t := float64(i) / float64(rate)
+			v := s.noise * rnd.NormFloat64()
+			if s.voiced {
+				env := 0.6 + 0.4*math.Sin(2*math.Pi*4*t)
+				for h := 1; h <= 10; h++ {
+					v += env * 3000 / float64(h) * math.Sin(2*math.Pi*150*float64(h)*t)
+				}
+			}
+			out = append(out, int16(max(min(v, math.MaxInt16), math.MinInt16)))
+		}
+	}
+	return out
+}
+
+// activityWriter records voice activity metadata of each frame.
+type activityWriter struct {
+	media.PCM16Writer
+	cur    media.VoiceActivity
+	frames []media.VoiceActivity
+}
+
+func (w *activityWriter) SetVoiceActivity(v media.VoiceActivity) {
+	w.cur = v
+}
+
+func (w *activityWriter) WriteSample(in media.PCM16Sample) error {
+	w.frames = append(w.frames, w.cur)
+	return w.PCM16Writer.WriteSample(in)
+}
+
+func run(t testing.TB, rate int, conf *Config, samples media.PCM16Sample) ([]Event, *activityWriter, *Writer) {
+	var frames []media.PCM16Sample
+	aw := &activityWriter{PCM16Writer: media.NewPCM16FrameWriter(&frames, rate)}
+	var events []Event
+	v := NewWriter(aw, conf, func(e Event) {
+		events = append(events, e)
+	})
+	frame := rate / media.DefFramesPerSec
+	var out media.PCM16Sample
+	for len(samples) > 0 {
+		n := min(frame, len(samples))
+		require.NoError(t, v.WriteSample(samples[:n]))
+		out = append(out, samples[:n]...)
+		samples = samples[n:]
+	}
+	require.NoError(t, v.Close())
+	var got media.PCM16Sample
+	for _, f := range frames {
+		got = append(got, f...)
+	}
+	require.Equal(t, out, got)
+	return events, aw, v
+}
+
+func TestVAD(t *testing.T) {
+	for _, rate := range []int{8000, 16000, 48000} {
+		t.Run(strconv.Itoa(rate), func(t *testing.T) {
+			samples := synth(rate,
+				segment{dur: 500 * time.Millisecond, noise: 30},
+				segment{dur: time.Second, voiced: true, noise: 30},
+				segment{dur: time.Second, noise: 30},
+				segment{dur: 700 * time.Millisecond, voiced: true, noise: 30},
+				segment{dur: time.Second, noise: 30},
+			)
+			events, aw, v := run(t, rate, nil, samples)
+			require.Len(t, events, 4)
+			for i, exp := range []Event{
+				{Speech: true, Time: 500 * time.Millisecond},
+				{Speech: false, Time: 1500*time.Millisecond + DefaultHangover},
+				{Speech: true, Time: 2500 * time.Millisecond},
+				{Speech: false, Time: 3200*time.Millisecond + DefaultHangover},
+			} {
+				require.Equal(t, exp.Speech, events[i].Speech, "event %d", i)
+				require.InDelta(t, exp.Time, events[i].Time, float64(30*time.Millisecond), "event %d", i)
+			}
+			require.False(t, v.Speech())
+			require.InDelta(t, -61, v.NoiseFloor(), 3)
+
+			// metadata
+			require.Len(t, aw.frames, 210)
+			require.False(t, aw.frames[10].Voice)
+			require.True(t, aw.frames[50].Voice)
+			require.False(t, aw.frames[100].Voice)
+			require.Less(t, aw.frames[50].Level, aw.frames[10].Level)
+		})
+	}
+}
+
+func TestVADNoise(t *testing.T) {
+	const rate = 16000
+	// loud noise and level changes are not speech
+	samples := synth(rate,
+		segment{dur: time.Second, noise: 30},
+		segment{dur: 2 * time.Second, noise: 3000},
+		segment{dur: time.Second, noise: 300},
+	)
+	events, _, v := run(t, rate, nil, samples)
+	require.Empty(t, events)
+	require.InDelta(t, -40, v.NoiseFloor(), 3)
+
+	// voice in the noise is still detected
+	samples = synth(rate,
+		segment{dur: time.Second, noise: 500},
+		segment{dur: time.Second, voiced: true, noise: 500},
+		segment{dur: time.Second, noise: 500},
+	)
+	events, _, _ = run(t, rate, nil, samples)
+	require.Len(t, events, 2)
+	require.InDelta(t, time.Second, events[0].Time, float64(30*time.Millisecond))
+}
+
+func TestVADConfig(t *testing.T) {
+	const rate = 8000
+	samples := synth(rate,
+		segment{dur: 500 * time.Millisecond},
+		segment{dur: 100 * time.Millisecond, voiced: true},
+		segment{dur: 200 * time.Millisecond},
+		segment{dur: 100 * time.Millisecond, voiced: true},
+		segment{dur: time.Second},
+	)
+	events, _, _ := run(t, rate, nil, samples)
+	require.Len(t, events, 2)
+
+	events, _, _ = run(t, rate, &Config{Hangover: 100 * time.Millisecond}, samples)
+	require.Len(t, events, 4)
+
+	events, _, _ = run(t, rate, &Config{Onset: 200 * time.Millisecond}, samples)
+	require.Empty(t, events)
+}
+
+func TestVADSpeech(t *testing.T) {
+	var samples media.PCM16Sample
+	for _, f := range res.ReadOggAudioFile(testdata.TestAudioOgg, res.SampleRate, 1) {
+		samples = append(samples, f...)
+	}
+	events, _, _ := run(t, res.SampleRate, nil, samples)
+	require.NotEmpty(t, events)
+	require.True(t, events[0].Speech)
+	require.Less(t, events[0].Time, 200*time.Millisecond)
+	last := events[len(events)-1]
+	require.False(t, last.Speech)
+	require.Greater(t, last.Time, 4*time.Second)
+}
+
+func TestProcessor(t *testing.T) {
+	var frames []media.PCM16Sample
+	var events []Event
+	p := NewProcessor(nil, func(e Event) {
+		events = append(events, e)
+	})
+	w := p(media.NopCloser(media.NewPCM16FrameWriter(&frames, 8000)))
+	require.Equal(t, 8000, w.SampleRate())
+	samples := synth(8000, segment{dur: 200 * time.Millisecond}, segment{dur: 200 * time.Millisecond, voiced: true})
+	for i := 0; i < len(samples); i += 160 {
+		require.NoError(t, w.WriteSample(samples[i:i+160]))
+	}
+	require.Len(t, frames, 20)
+	require.Equal(t, []Event{{Speech: true, Time: 200 * time.Millisecond}}, events)
+}
+
+func TestLevel(t *testing.T) {
+	require.EqualValues(t, 127, Level(nil))
+	require.EqualValues(t, 127, Level(make(media.PCM16Sample, 160)))
+	buf := make(media.PCM16Sample, 160)
+	for i := range buf {
+		buf[i] = int16(math.MaxInt16 * math.Sin(2*math.Pi*float64(i)/16))
+	}
+	require.EqualValues(t, 3, Level(buf))
+	for i := range buf {
+		buf[i] /= 10
+	}
+	require.EqualValues(t, 23, Level(buf))
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wav
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/g711"
+)
+
+var (
+	ErrNotWAV  = errors.New("wav: not a WAVE file")
+	errNoFmt   = errors.New("wav: data chunk before format chunk")
+	errBadFmt  = errors.New("wav: invalid format chunk")
+	errNoAudio = errors.New("wav: no data chunk")
+)
+
+var _ media.Reader[media.PCM16Sample] = (*Reader)(nil)
+
+// NewReader reads the header of a WAV file and returns a streaming reader of its audio.
+// It supports 16 bit PCM, A-law and u-law, which are all decoded to 16 bit PCM.
+func NewReader(r io.Reader) (*Reader, error) {
+	rd := &Reader{r: bufio.NewReader(r)}
+	if err := rd.readHeader(); err != nil {
+		return nil, err
+	}
+	return rd, nil
+}
+
+// Reader is a streaming reader of WAV files. See NewReader.
+type Reader struct {
+	r          *bufio.Reader
+	format     Format
+	sampleRate int
+	channels   int
+	remaining  int64 // remaining size of data, or -1 if unknown
+	buf        []byte
+}
+
+// Format returns the audio format of the file.
+func (r *Reader) Format() Format {
+	return r.format
+}
+
+func (r *Reader) SampleRate() int {
+	return r.sampleRate
+}
+
+// Channels returns the number of channels. Samples of multiple channels are interleaved.
+func (r *Reader) Channels() int {
+	return r.channels
+}
+
+func (r *Reader) readHeader() error {
+	var hdr [12]byte
+	if _, err := io.ReadFull(r.r, hdr[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrNotWAV
+		}
+		return err
+	}
+	if string(hdr[0:4]) != "RIFF" || string(hdr[8:12]) != "WAVE" {
+		return ErrNotWAV
+	}
+	hasFmt := false
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r.r, chunk[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return errNoAudio
+			}
+			return err
+		}
+		id, size := string(chunk[:4]), binary.LittleEndian.Uint32(chunk[4:])
+		switch id {
+		case "fmt ":
+			if size < 16 || size > 1024 {
+				return errBadFmt
+			}
+			data := make([]byte, size+size%2)
+			if _, err := io.ReadFull(r.r, data); err != nil {
+				return err
+			}
+			if err := r.parseFormat(data[:size]); err != nil {
+				return err
+			}
+			hasFmt = true
+		case "data":
+			if !hasFmt {
+				return errNoFmt
+			}
+			r.remaining = int64(size)
+			if size == math.MaxUint32 {
+				r.remaining = -1
+			}
+			return nil
+		default:
+			if _, err := r.r.Discard(int(size + size%2)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (r *Reader) parseFormat(data []byte) error {
+	format := Format(binary.LittleEndian.Uint16(data[0:]))
+	channels := int(binary.LittleEndian.Uint16(data[2:]))
+	sampleRate := int(binary.LittleEndian.Uint32(data[4:]))
+	bits := int(binary.LittleEndian.Uint16(data[14:]))
+	if format == formatExtensible {
+		if len(data) < 40 {
+			return errBadFmt
+		}
+		// the first two bytes of the sub-format GUID are the format tag
+		format = Format(binary.LittleEndian.Uint16(data[24:]))
+	}
+	switch format {
+	case FormatPCM, FormatALaw, FormatULaw:
+	default:
+		return fmt.Errorf("wav: unsupported format: %s", format)
+	}
+	if bits != format.bitsPerSample() {
+		return fmt.Errorf("wav: unsupported bits per sample for %s: %d", format, bits)
+	}
+	if channels <= 0 || sampleRate <= 0 {
+		return errBadFmt
+	}
+	r.format, r.channels, r.sampleRate = format, channels, sampleRate
+	return nil
+}
+
+// ReadSample reads up to len(buf) samples, interleaved if there are multiple channels.
+// It returns io.EOF at the end of the audio data.
+func (r *Reader) ReadSample(buf media.PCM16Sample) (int, error) {
+	bytesPer := r.format.bitsPerSample() / 8
+	size := len(buf) * bytesPer
+	if r.remaining >= 0 {
+		size = int(min(int64(size), r.remaining-r.remaining%int64(bytesPer)))
+	}
+	if size == 0 {
+		if len(buf) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	if cap(r.buf) < size {
+		r.buf = make([]byte, size)
+	}
+	n, err := io.ReadFull(r.r, r.buf[:size])
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = nil // truncated file, or unknown size
+	}
+	n -= n % bytesPer
+	if r.remaining >= 0 {
+		r.remaining -= int64(n)
+	}
+	data := r.buf[:n]
+	switch r.format {
+	case FormatPCM:
+		for i := range n / 2 {
+			buf[i] = int16(binary.LittleEndian.Uint16(data[2*i:]))
+		}
+	case FormatALaw:
+		g711.DecodeALawTo(buf, data)
+	case FormatULaw:
+		g711.DecodeULawTo(buf, data)
+	}
+	samples := n / bytesPer
+	if samples != 0 && err == io.EOF {
+		err = nil
+	}
+	if samples == 0 && err == nil {
+		err = io.EOF
+	}
+	return samples, err
+}
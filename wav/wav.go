@@ -0,0 +1,63 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wav implements reading and writing of RIFF/WAVE audio files.
+//
+// Importing this package also allows media.DumpWriter to write PCM16 as ".wav" files.
+package wav
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/livekit/media-sdk"
+)
+
+const FileExt = "wav"
+
+// Format is an audio format tag of WAV file.
+type Format uint16
+
+const (
+	FormatPCM        Format = 1
+	FormatALaw       Format = 6
+	FormatULaw       Format = 7
+	formatExtensible Format = 0xFFFE
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatPCM:
+		return "PCM"
+	case FormatALaw:
+		return "A-law"
+	case FormatULaw:
+		return "u-law"
+	}
+	return fmt.Sprintf("Format(%d)", uint16(f))
+}
+
+// bitsPerSample returns the size of a single sample of one channel in bits.
+func (f Format) bitsPerSample() int {
+	if f == FormatPCM {
+		return 16
+	}
+	return 8
+}
+
+func init() {
+	media.RegisterFileWriter(FileExt, func(w io.WriteCloser, sampleRate int) media.PCM16Writer {
+		return NewPCM16Writer(w, sampleRate, 1)
+	})
+}
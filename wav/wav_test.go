@@ -0,0 +1,238 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/g711"
+)
+
+type nopCloser struct {
+	bytes.Buffer
+}
+
+func (*nopCloser) Close() error { return nil }
+
+func sine(n, channels int) media.PCM16Sample {
+	out := make(media.PCM16Sample, n*channels)
+	for i := range n {
+		for ch := range channels {
+			out[i*channels+ch] = int16(10000 * math.Sin(2*math.Pi*440*float64(i)/8000) / float64(ch+1))
+		}
+	}
+	return out
+}
+
+func readAll(t testing.TB, r *Reader, frame int) media.PCM16Sample {
+	var out media.PCM16Sample
+	buf := make(media.PCM16Sample, frame)
+	for {
+		n, err := r.ReadSample(buf)
+		out = append(out, buf[:n]...)
+		if err == io.EOF {
+			return out
+		}
+		require.NoError(t, err)
+		require.NotZero(t, n)
+	}
+}
+
+func TestPCM16(t *testing.T) {
+	for _, channels := range []int{1, 2} {
+		path := filepath.Join(t.TempDir(), "test.wav")
+		f, err := os.Create(path)
+		require.NoError(t, err)
+		src := sine(1000, channels)
+		w := NewPCM16Writer(f, 8000, channels)
+		require.Equal(t, 8000, w.SampleRate())
+		for i := 0; i < len(src); i += 160 {
+			require.NoError(t, w.WriteSample(src[i:min(i+160, len(src))]))
+		}
+		require.NoError(t, w.Close())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Len(t, data, 44+2*len(src))
+		require.EqualValues(t, len(data)-8, binary.LittleEndian.Uint32(data[4:]))
+		require.EqualValues(t, 2*len(src), binary.LittleEndian.Uint32(data[40:]))
+		require.EqualValues(t, 8000*2*channels, binary.LittleEndian.Uint32(data[28:])) // byte rate
+
+		r, err := NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		require.Equal(t, FormatPCM, r.Format())
+		require.Equal(t, 8000, r.SampleRate())
+		require.Equal(t, channels, r.Channels())
+		require.Equal(t, src, readAll(t, r, 300))
+	}
+}
+
+func TestStream(t *testing.T) {
+	// sizes cannot be updated if the output is not seekable
+	var buf nopCloser
+	src := sine(500, 1)
+	w := NewPCM16Writer(&buf, 16000, 1)
+	require.NoError(t, w.WriteSample(src))
+	require.NoError(t, w.Close())
+	data := buf.Bytes()
+	require.EqualValues(t, uint32(math.MaxUint32), binary.LittleEndian.Uint32(data[4:]))
+	require.EqualValues(t, uint32(math.MaxUint32), binary.LittleEndian.Uint32(data[40:]))
+
+	r, err := NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, 16000, r.SampleRate())
+	require.Equal(t, src, readAll(t, r, 160))
+}
+
+func TestG711(t *testing.T) {
+	src := sine(801, 1) // odd size requires padding
+	alaw := make(g711.ALawSample, len(src))
+	g711.EncodeALawTo(alaw, src)
+	ulaw := make(g711.ULawSample, len(src))
+	g711.EncodeULawTo(ulaw, src)
+
+	for _, c := range []struct {
+		format Format
+		write  func(path string) error
+		exp    media.PCM16Sample
+	}{
+		{FormatALaw, func(path string) error {
+			f, err := os.Create(path)
+			require.NoError(t, err)
+			w := NewALawWriter(f, 8000)
+			require.NoError(t, w.WriteSample(alaw))
+			return w.Close()
+		}, alaw.Decode()},
+		{FormatULaw, func(path string) error {
+			f, err := os.Create(path)
+			require.NoError(t, err)
+			w := NewULawWriter(f, 8000)
+			require.NoError(t, w.WriteSample(ulaw))
+			return w.Close()
+		}, ulaw.Decode()},
+	} {
+		t.Run(c.format.String(), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.wav")
+			require.NoError(t, c.write(path))
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.Len(t, data, 58+len(src)+1)
+			require.EqualValues(t, c.format, binary.LittleEndian.Uint16(data[20:]))
+			require.Equal(t, "fact", string(data[38:42]))
+			require.EqualValues(t, len(src), binary.LittleEndian.Uint32(data[46:]))
+			require.EqualValues(t, len(src), binary.LittleEndian.Uint32(data[54:]))
+			require.EqualValues(t, len(data)-8, binary.LittleEndian.Uint32(data[4:]))
+
+			r, err := NewReader(bytes.NewReader(data))
+			require.NoError(t, err)
+			require.Equal(t, c.format, r.Format())
+			require.Equal(t, c.exp, readAll(t, r, 160))
+		})
+	}
+}
+
+func TestReader(t *testing.T) {
+	chunk := func(id string, data []byte) []byte {
+		b := append([]byte(id), binary.LittleEndian.AppendUint32(nil, uint32(len(data)))...)
+		b = append(b, data...)
+		if len(data)%2 != 0 {
+			b = append(b, 0)
+		}
+		return b
+	}
+	file := func(chunks ...[]byte) []byte {
+		b := []byte("RIFF\x00\x00\x00\x00WAVE")
+		for _, c := range chunks {
+			b = append(b, c...)
+		}
+		return b
+	}
+	fmtChunk := func(format Format, channels, rate, bits int, ext []byte) []byte {
+		b := binary.LittleEndian.AppendUint16(nil, uint16(format))
+		b = binary.LittleEndian.AppendUint16(b, uint16(channels))
+		b = binary.LittleEndian.AppendUint32(b, uint32(rate))
+		b = binary.LittleEndian.AppendUint32(b, uint32(rate*channels*bits/8))
+		b = binary.LittleEndian.AppendUint16(b, uint16(channels*bits/8))
+		b = binary.LittleEndian.AppendUint16(b, uint16(bits))
+		return append(b, ext...)
+	}
+	pcm := []byte{1, 0, 2, 0, 0xff, 0xff}
+
+	// unknown chunks are skipped, including padding
+	r, err := NewReader(bytes.NewReader(file(
+		chunk("LIST", []byte("odd")),
+		chunk("fmt ", fmtChunk(FormatPCM, 1, 48000, 16, nil)),
+		chunk("data", pcm),
+	)))
+	require.NoError(t, err)
+	require.Equal(t, media.PCM16Sample{1, 2, -1}, readAll(t, r, 2))
+
+	// extensible format
+	ext := make([]byte, 24)
+	binary.LittleEndian.PutUint16(ext[0:], 22)
+	binary.LittleEndian.PutUint16(ext[8:], uint16(FormatPCM))
+	r, err = NewReader(bytes.NewReader(file(
+		chunk("fmt ", fmtChunk(formatExtensible, 1, 48000, 16, ext)),
+		chunk("data", pcm),
+	)))
+	require.NoError(t, err)
+	require.Equal(t, FormatPCM, r.Format())
+
+	// truncated data
+	data := file(chunk("fmt ", fmtChunk(FormatPCM, 1, 8000, 16, nil)), chunk("data", pcm))
+	r, err = NewReader(bytes.NewReader(data[:len(data)-1]))
+	require.NoError(t, err)
+	require.Equal(t, media.PCM16Sample{1, 2}, readAll(t, r, 10))
+
+	for name, data := range map[string][]byte{
+		"empty":       nil,
+		"not wav":     []byte("RIFF\x00\x00\x00\x00AVI LIST"),
+		"no fmt":      file(chunk("data", pcm)),
+		"no data":     file(chunk("fmt ", fmtChunk(FormatPCM, 1, 8000, 16, nil))),
+		"8 bit pcm":   file(chunk("fmt ", fmtChunk(FormatPCM, 1, 8000, 8, nil)), chunk("data", pcm)),
+		"float":       file(chunk("fmt ", fmtChunk(3, 1, 8000, 32, nil)), chunk("data", pcm)),
+		"no channels": file(chunk("fmt ", fmtChunk(FormatPCM, 0, 8000, 16, nil)), chunk("data", pcm)),
+	} {
+		_, err := NewReader(bytes.NewReader(data))
+		require.Error(t, err, name)
+	}
+}
+
+func TestDumpWriter(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "dump")
+	var frames []media.PCM16Sample
+	w := media.DumpWriter[media.PCM16Sample](FileExt, name, media.NewPCM16FrameWriter(&frames, 8000))
+	src := sine(320, 1)
+	require.NoError(t, w.WriteSample(src))
+	require.NoError(t, w.Close())
+	require.Len(t, frames, 1)
+
+	f, err := os.Open(name + "_ar8000.wav")
+	require.NoError(t, err)
+	defer f.Close()
+	r, err := NewReader(f)
+	require.NoError(t, err)
+	require.Equal(t, 8000, r.SampleRate())
+	require.Equal(t, src, readAll(t, r, 160))
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wav
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/livekit/media-sdk"
+	"github.com/livekit/media-sdk/g711"
+)
+
+// NewPCM16Writer creates a WAV writer for 16 bit PCM. Channels must be interleaved.
+func NewPCM16Writer(w io.WriteCloser, sampleRate, channels int) media.PCM16Writer {
+	return NewWriter[media.PCM16Sample](w, FormatPCM, sampleRate, channels)
+}
+
+// NewALawWriter creates a WAV writer for G.711 A-law.
+func NewALawWriter(w io.WriteCloser, sampleRate int) media.WriteCloser[g711.ALawSample] {
+	return NewWriter[g711.ALawSample](w, FormatALaw, sampleRate, 1)
+}
+
+// NewULawWriter creates a WAV writer for G.711 u-law.
+func NewULawWriter(w io.WriteCloser, sampleRate int) media.WriteCloser[g711.ULawSample] {
+	return NewWriter[g711.ULawSample](w, FormatULaw, sampleRate, 1)
+}
+
+// NewWriter creates a WAV writer for frames of a given format.
+//
+// Sizes in the header are unknown until the writer is closed.
+// If w implements io.Seeker, they are updated on Close. Otherwise, they are left at the max value,
+// which most readers interpret as a stream of unknown length.
+func NewWriter[T media.Frame](w io.WriteCloser, format Format, sampleRate, channels int) media.WriteCloser[T] {
+	switch format {
+	default:
+		panic("unsupported format")
+	case FormatPCM, FormatALaw, FormatULaw:
+	}
+	if channels <= 0 {
+		panic("invalid number of channels")
+	}
+	wr := &writer[T]{
+		w:          w,
+		bw:         bufio.NewWriter(w),
+		format:     format,
+		sampleRate: sampleRate,
+		channels:   channels,
+	}
+	wr.writeHeader(math.MaxUint32)
+	return wr
+}
+
+type writer[T media.Frame] struct {
+	w          io.WriteCloser
+	bw         *bufio.Writer
+	format     Format
+	sampleRate int
+	channels   int
+	size       int64 // size of the data written so far
+	buf        []byte
+}
+
+func (w *writer[T]) String() string {
+	return fmt.Sprintf("WAV(%s,%d,%d)", w.format, w.sampleRate, w.channels)
+}
+
+func (w *writer[T]) SampleRate() int {
+	return w.sampleRate
+}
+
+// header returns the header of the file for a given data size.
+// Formats other than PCM have an extended format chunk and a fact chunk, as required by the specification.
+func (w *writer[T]) header(size uint32) []byte {
+	pcm := w.format == FormatPCM
+	fmtSize := 16
+	if !pcm {
+		fmtSize = 18
+	}
+	blockAlign := w.channels * w.format.bitsPerSample() / 8
+
+	b := make([]byte, 0, 58)
+	b = append(b, "RIFF"...)
+	b = binary.LittleEndian.AppendUint32(b, 0) // set below
+	b = append(b, "WAVE"...)
+
+	b = append(b, "fmt "...)
+	b = binary.LittleEndian.AppendUint32(b, uint32(fmtSize))
+	b = binary.LittleEndian.AppendUint16(b, uint16(w.format))
+	b = binary.LittleEndian.AppendUint16(b, uint16(w.channels))
+	b = binary.LittleEndian.AppendUint32(b, uint32(w.sampleRate))
+	b = binary.LittleEndian.AppendUint32(b, uint32(w.sampleRate*blockAlign))
+	b = binary.LittleEndian.AppendUint16(b, uint16(blockAlign))
+	b = binary.LittleEndian.AppendUint16(b, uint16(w.format.bitsPerSample()))
+	if !pcm {
+		b = binary.LittleEndian.AppendUint16(b, 0) // no extra format bytes
+
+		b = append(b, "fact"...)
+		b = binary.LittleEndian.AppendUint32(b, 4)
+		frames := uint32(math.MaxUint32)
+		if size != math.MaxUint32 {
+			frames = size / uint32(blockAlign)
+		}
+		b = binary.LittleEndian.AppendUint32(b, frames)
+	}
+
+	b = append(b, "data"...)
+	b = binary.LittleEndian.AppendUint32(b, size)
+
+	riff := uint32(math.MaxUint32)
+	if size != math.MaxUint32 {
+		riff = uint32(min(int64(len(b))-8+int64(size)+int64(size%2), math.MaxUint32))
+	}
+	binary.LittleEndian.PutUint32(b[4:], riff)
+	return b
+}
+
+func (w *writer[T]) writeHeader(size uint32) {
+	_, _ = w.bw.Write(w.header(size)) // errors are returned on flush
+}
+
+func (w *writer[T]) WriteSample(sample T) error {
+	if sz := sample.Size(); cap(w.buf) < sz {
+		w.buf = make([]byte, sz)
+	} else {
+		w.buf = w.buf[:sz]
+	}
+	n, err := sample.CopyTo(w.buf)
+	if err != nil {
+		return err
+	}
+	n, err = w.bw.Write(w.buf[:n])
+	w.size += int64(n)
+	return err
+}
+
+// finalize adds padding to the data chunk and updates sizes in the header, if possible.
+func (w *writer[T]) finalize() error {
+	if w.size%2 != 0 {
+		if err := w.bw.WriteByte(0); err != nil {
+			return err
+		}
+	}
+	if err := w.bw.Flush(); err != nil {
+		return err
+	}
+	ws, ok := w.w.(io.WriteSeeker)
+	if !ok {
+		return nil
+	}
+	if _, err := ws.Seek(0, io.SeekStart); err != nil {
+		return nil // not seekable, e.g. a pipe
+	}
+	hdr := w.header(uint32(min(w.size, math.MaxUint32-1)))
+	if _, err := ws.Write(hdr); err != nil {
+		return err
+	}
+	_, err := ws.Seek(0, io.SeekEnd)
+	return err
+}
+
+func (w *writer[T]) Close() error {
+	if err := w.finalize(); err != nil {
+		_ = w.w.Close()
+		return err
+	}
+	return w.w.Close()
+}
@@ -0,0 +1,294 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webm
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/bits"
+)
+
+// EBML element IDs used by the reader, as defined in the Matroska specification.
+const (
+	idEBML               = 0x1A45DFA3
+	idDocType            = 0x4282
+	idSegment            = 0x18538067
+	idSeekHead           = 0x114D9B74
+	idSeek               = 0x4DBB
+	idSeekID             = 0x53AB
+	idSeekPosition       = 0x53AC
+	idInfo               = 0x1549A966
+	idTimecodeScale      = 0x2AD7B1
+	idDuration           = 0x4489
+	idTracks             = 0x1654AE6B
+	idTrackEntry         = 0xAE
+	idTrackNumber        = 0xD7
+	idTrackType          = 0x83
+	idName               = 0x536E
+	idCodecID            = 0x86
+	idCodecPrivate       = 0x63A2
+	idCodecDelay         = 0x56AA
+	idDefaultDuration    = 0x23E383
+	idAudio              = 0xE1
+	idSamplingFrequency  = 0xB5
+	idChannels           = 0x9F
+	idBitDepth           = 0x6264
+	idCluster            = 0x1F43B675
+	idTimecode           = 0xE7
+	idSimpleBlock        = 0xA3
+	idBlockGroup         = 0xA0
+	idBlock              = 0xA1
+	idBlockDuration      = 0x9B
+	idCues               = 0x1C53BB6B
+	idCuePoint           = 0xBB
+	idCueTime            = 0xB3
+	idCueTrackPositions  = 0xB7
+	idCueTrack           = 0xF7
+	idCueClusterPosition = 0xF1
+)
+
+// sizeUnknown is the element size of live streams, where the end of the element is not known.
+const sizeUnknown = -1
+
+var errInvalidVint = errors.New("webm: invalid variable size integer")
+
+// readVint reads EBML variable size integer. If raw is set, the length marker is kept, as in element IDs.
+func readVint(r io.ByteReader, raw bool) (v uint64, n int, err error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	n = bits.LeadingZeros8(b) + 1
+	if n > 8 {
+		return 0, 1, errInvalidVint
+	}
+	v = uint64(b)
+	if !raw {
+		v &= 0xff >> n
+	}
+	allOnes := v == 0xff>>n
+	for i := 1; i < n; i++ {
+		b, err = r.ReadByte()
+		if err != nil {
+			return 0, i, noEOF(err)
+		}
+		allOnes = allOnes && b == 0xff
+		v = v<<8 | uint64(b)
+	}
+	if !raw && allOnes {
+		return math.MaxUint64, n, nil
+	}
+	return v, n, nil
+}
+
+// readHeader reads the ID and data size of the next element. The size is sizeUnknown for elements of unknown size.
+func readHeader(r io.ByteReader) (id uint32, size int64, n int, err error) {
+	v, n, err := readVint(r, true)
+	if err != nil {
+		if n != 0 {
+			err = noEOF(err)
+		}
+		return 0, 0, n, err
+	}
+	if n > 4 {
+		return 0, 0, n, errInvalidVint
+	}
+	id = uint32(v)
+	sz, m, err := readVint(r, false)
+	n += m
+	if err != nil {
+		return 0, 0, n, noEOF(err)
+	}
+	if sz == math.MaxUint64 {
+		return id, sizeUnknown, n, nil
+	}
+	if sz > math.MaxInt64 {
+		return 0, 0, n, errInvalidVint
+	}
+	return id, int64(sz), n, nil
+}
+
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// element is an EBML element read into memory.
+type element struct {
+	id   uint32
+	data []byte
+}
+
+// children iterates over child elements of a master element.
+func children(data []byte, fnc func(e element) error) error {
+	for len(data) != 0 {
+		br := &byteReader{b: data}
+		id, size, n, err := readHeader(br)
+		if err != nil {
+			return noEOF(err)
+		}
+		data = data[n:]
+		if size == sizeUnknown || size > int64(len(data)) {
+			return errInvalidSize
+		}
+		if err := fnc(element{id: id, data: data[:size]}); err != nil {
+			return err
+		}
+		data = data[size:]
+	}
+	return nil
+}
+
+var errInvalidSize = errors.New("webm: invalid element size")
+
+type byteReader struct {
+	b []byte
+}
+
+func (r *byteReader) ReadByte() (byte, error) {
+	if len(r.b) == 0 {
+		return 0, io.EOF
+	}
+	b := r.b[0]
+	r.b = r.b[1:]
+	return b, nil
+}
+
+func (e element) uint() uint64 {
+	var v uint64
+	for _, b := range e.data {
+		v = v<<8 | uint64(b)
+	}
+	return v
+}
+
+func (e element) float() float64 {
+	switch len(e.data) {
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(e.data)))
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(e.data))
+	}
+	return 0
+}
+
+func (e element) string() string {
+	// strings may be padded with zeros
+	b := e.data
+	for len(b) != 0 && b[len(b)-1] == 0 {
+		b = b[:len(b)-1]
+	}
+	return string(b)
+}
+
+// Element IDs used only by the writer.
+const (
+	idDocTypeVersion     = 0x4287
+	idDocTypeReadVersion = 0x4285
+	idMuxingApp          = 0x4D80
+	idWritingApp         = 0x5741
+	idTrackUID           = 0x73C5
+	idVoid               = 0xEC
+)
+
+// sizeLen8 is the length of fixed 8 byte element sizes, which are used for values patched later.
+const sizeLen8 = 8
+
+func appendID(b []byte, id uint32) []byte {
+	switch {
+	case id > 0xFFFFFF:
+		return append(b, byte(id>>24), byte(id>>16), byte(id>>8), byte(id))
+	case id > 0xFFFF:
+		return append(b, byte(id>>16), byte(id>>8), byte(id))
+	case id > 0xFF:
+		return append(b, byte(id>>8), byte(id))
+	}
+	return append(b, byte(id))
+}
+
+// appendSize appends a data size with the minimal length.
+func appendSize(b []byte, size uint64) []byte {
+	n := 1
+	// all ones value is reserved for unknown size
+	for n < 8 && size >= 1<<(7*n)-1 {
+		n++
+	}
+	return appendSizeN(b, size, n)
+}
+
+func appendSizeN(b []byte, size uint64, n int) []byte {
+	size |= 1 << (7 * n) // length marker
+	for i := n - 1; i >= 0; i-- {
+		b = append(b, byte(size>>(8*i)))
+	}
+	return b
+}
+
+// appendSize8 appends a data size with fixed 8 byte length. Negative size is encoded as unknown.
+func appendSize8(b []byte, size int64) []byte {
+	if size < 0 {
+		return append(b, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF)
+	}
+	return appendSizeN(b, uint64(size), sizeLen8)
+}
+
+func appendElement(b []byte, id uint32, data []byte) []byte {
+	b = appendID(b, id)
+	b = appendSize(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendUint(b []byte, id uint32, v uint64) []byte {
+	n := 1
+	for n < 8 && v>>(8*n) != 0 {
+		n++
+	}
+	b = appendID(b, id)
+	b = appendSize(b, uint64(n))
+	for i := n - 1; i >= 0; i-- {
+		b = append(b, byte(v>>(8*i)))
+	}
+	return b
+}
+
+// appendUint8 appends an unsigned integer with fixed 8 byte length.
+func appendUint8(b []byte, id uint32, v uint64) []byte {
+	b = appendID(b, id)
+	b = appendSize(b, 8)
+	return binary.BigEndian.AppendUint64(b, v)
+}
+
+func appendFloat(b []byte, id uint32, v float64) []byte {
+	b = appendID(b, id)
+	b = appendSize(b, 8)
+	return binary.BigEndian.AppendUint64(b, math.Float64bits(v))
+}
+
+func appendString(b []byte, id uint32, s string) []byte {
+	b = appendID(b, id)
+	b = appendSize(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// appendVoid appends a Void element of a given total size, which must be in [2, 128].
+func appendVoid(b []byte, total int) []byte {
+	b = appendID(b, idVoid)
+	b = appendSizeN(b, uint64(total-2), 1)
+	return append(b, make([]byte, total-2)...)
+}
@@ -0,0 +1,336 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	muxingApp = "livekit/media-sdk"
+	// timecodeScale is the duration of a timecode tick in written files.
+	timecodeScale = time.Millisecond
+	// maxClusterDur is the max duration of a cluster.
+	maxClusterDur = 5 * time.Second
+	// durationSize is the size of the Duration element reserved in the header.
+	durationSize = 2 + 1 + 8
+)
+
+var errClosed = errors.New("webm: writer closed")
+
+// NewMuxer creates a writer of a WebM file with multiple tracks. Headers are written immediately.
+//
+// Blocks are written as soon as they arrive, so tracks should be written at a similar pace.
+// Tracks that start later than others must set Track.Start.
+// If w implements io.Seeker, Close writes Cues and Duration, which makes the file seekable in players.
+func NewMuxer(w io.WriteCloser, tracks []Track) (*Muxer, error) {
+	if len(tracks) == 0 {
+		return nil, ErrNoTracks
+	}
+	m := &Muxer{w: w, tracks: make([]*muxTrack, len(tracks)), cluster: -1}
+	for i, t := range tracks {
+		if t.Number == 0 {
+			t.Number = uint64(i + 1)
+		} else if t.Number >= 0x7F {
+			// keeps the track number in blocks a single byte
+			return nil, fmt.Errorf("webm: track number is too large: %d", t.Number)
+		}
+		if t.Type == 0 {
+			t.Type = TrackAudio
+		}
+		if t.SampleRate <= 0 {
+			return nil, fmt.Errorf("webm: invalid sample rate for track %d: %d", t.Number, t.SampleRate)
+		}
+		if t.Start < 0 {
+			return nil, fmt.Errorf("webm: invalid start for track %d: %v", t.Number, t.Start)
+		}
+		if slices.ContainsFunc(m.tracks[:i], func(mt *muxTrack) bool { return mt.Number == t.Number }) {
+			return nil, fmt.Errorf("webm: duplicate track number: %d", t.Number)
+		}
+		m.tracks[i] = &muxTrack{Track: t}
+	}
+	if ws, ok := w.(io.WriteSeeker); ok {
+		if pos, err := ws.Seek(0, io.SeekCurrent); err == nil {
+			m.ws = ws
+			m.pos = pos
+		}
+	}
+	if err := m.writeHeaders(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Muxer writes blocks of multiple tracks to a WebM file. See NewMuxer.
+type Muxer struct {
+	mu     sync.Mutex
+	w      io.WriteCloser
+	ws     io.WriteSeeker // set if the output is seekable
+	pos    int64          // offset of the next byte
+	tracks []*muxTrack
+	buf    []byte
+	closed bool
+
+	segStart    int64 // offset of the segment data
+	seekHeadPos int64 // offset of the space reserved for the seek head
+	seekHeadLen int
+	infoPos     int64
+	durationPos int64 // offset of the space reserved for the duration
+	tracksPos   int64
+
+	cluster  int64 // timecode of the current cluster, or -1
+	clusters []muxCluster
+}
+
+type muxTrack struct {
+	Track
+	uid     uint64
+	samples int64 // samples written since the start, which defines the timestamp of the next block
+	closed  bool
+}
+
+// timestamp returns the timestamp of the next block.
+func (t *muxTrack) timestamp() time.Duration {
+	rate := int64(t.SampleRate)
+	return t.Start + time.Duration(t.samples/rate)*time.Second + time.Duration(t.samples%rate)*time.Second/time.Duration(rate)
+}
+
+type muxCluster struct {
+	pos    int64 // offset of the cluster element
+	ts     int64
+	tracks []uint64 // tracks with blocks in the cluster
+}
+
+func (m *Muxer) write(b []byte) error {
+	n, err := m.w.Write(b)
+	m.pos += int64(n)
+	return err
+}
+
+// writeAt overwrites data at a given offset. It must only be used for seekable outputs.
+func (m *Muxer) writeAt(pos int64, b []byte) error {
+	if _, err := m.ws.Seek(pos, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := m.w.Write(b)
+	return err
+}
+
+type seekEntry struct {
+	id  uint32
+	pos int64 // relative to the segment data
+}
+
+// seekHead encodes a seek head. Positions use fixed size, so the length only depends on the number of entries.
+func seekHead(entries ...seekEntry) []byte {
+	var body []byte
+	for _, s := range entries {
+		var seek []byte
+		seek = appendElement(seek, idSeekID, appendID(nil, s.id))
+		seek = appendUint8(seek, idSeekPosition, uint64(s.pos))
+		body = appendElement(body, idSeek, seek)
+	}
+	return appendElement(nil, idSeekHead, body)
+}
+
+func (m *Muxer) writeHeaders() error {
+	var hdr []byte
+	hdr = appendUint(hdr, idDocTypeVersion, 4)
+	hdr = appendUint(hdr, idDocTypeReadVersion, 2)
+	hdr = appendString(hdr, idDocType, "webm")
+	b := appendElement(nil, idEBML, hdr)
+	b = appendID(b, idSegment)
+	b = appendSize8(b, sizeUnknown) // patched on close
+	m.segStart = m.pos + int64(len(b))
+
+	// space for the seek head, which is only written on close
+	m.seekHeadPos = m.pos + int64(len(b))
+	m.seekHeadLen = len(seekHead(seekEntry{idInfo, 0}, seekEntry{idTracks, 0}, seekEntry{idCues, 0}))
+	b = appendVoid(b, m.seekHeadLen)
+
+	m.infoPos = m.pos + int64(len(b))
+	var info []byte
+	info = appendUint(info, idTimecodeScale, uint64(timecodeScale))
+	info = appendString(info, idMuxingApp, muxingApp)
+	info = appendString(info, idWritingApp, muxingApp)
+	info = appendVoid(info, durationSize)
+	b = appendElement(b, idInfo, info)
+	m.durationPos = m.pos + int64(len(b)) - durationSize
+
+	m.tracksPos = m.pos + int64(len(b))
+	var tracks []byte
+	for _, t := range m.tracks {
+		t.uid = rand.Uint64()
+		var e []byte
+		e = appendUint(e, idTrackNumber, t.Number)
+		e = appendUint(e, idTrackUID, t.uid)
+		if t.Name != "" {
+			e = appendString(e, idName, t.Name)
+		}
+		e = appendUint(e, idTrackType, uint64(t.Type))
+		e = appendString(e, idCodecID, t.CodecID)
+		if len(t.CodecPrivate) != 0 {
+			e = appendElement(e, idCodecPrivate, t.CodecPrivate)
+		}
+		if t.CodecDelay != 0 {
+			e = appendUint(e, idCodecDelay, uint64(t.CodecDelay))
+		}
+		if t.DefaultDuration != 0 {
+			e = appendUint(e, idDefaultDuration, uint64(t.DefaultDuration))
+		}
+		var audio []byte
+		audio = appendFloat(audio, idSamplingFrequency, float64(t.SampleRate))
+		audio = appendUint(audio, idChannels, uint64(max(t.Channels, 1)))
+		if t.BitDepth != 0 {
+			audio = appendUint(audio, idBitDepth, uint64(t.BitDepth))
+		}
+		e = appendElement(e, idAudio, audio)
+		tracks = appendElement(tracks, idTrackEntry, e)
+	}
+	b = appendElement(b, idTracks, tracks)
+	return m.write(b)
+}
+
+// writeBlock writes a SimpleBlock with a given number of samples at the end of the track.
+func (m *Muxer) writeBlock(t *muxTrack, data []byte, samples int64) error {
+	ts := int64(t.timestamp() / timecodeScale)
+	t.samples += samples
+
+	b := m.buf[:0]
+	rel := ts - m.cluster
+	if m.cluster < 0 || rel >= int64(maxClusterDur/timecodeScale) {
+		m.cluster, rel = ts, 0
+		m.clusters = append(m.clusters, muxCluster{pos: m.pos, ts: ts})
+		b = appendID(b, idCluster)
+		b = appendSize8(b, sizeUnknown) // patched on close
+		b = appendUint(b, idTimecode, uint64(ts))
+	}
+	// cluster timecodes must increase, so blocks of lagging tracks use negative relative timecodes,
+	// which cannot go further than the range of the block timecode
+	rel = max(rel, math.MinInt16)
+	c := &m.clusters[len(m.clusters)-1]
+	if !slices.Contains(c.tracks, t.Number) {
+		c.tracks = append(c.tracks, t.Number)
+	}
+
+	b = appendID(b, idSimpleBlock)
+	b = appendSize(b, uint64(4+len(data)))
+	b = append(b, 0x80|byte(t.Number), byte(rel>>8), byte(rel), 0x80) // keyframe
+	b = append(b, data...)
+	m.buf = b
+	return m.write(b)
+}
+
+// Duration returns the duration of the longest track written so far.
+func (m *Muxer) Duration() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.duration()
+}
+
+func (m *Muxer) duration() time.Duration {
+	var dur time.Duration
+	for _, t := range m.tracks {
+		dur = max(dur, t.timestamp())
+	}
+	return dur
+}
+
+// Close finalizes the file and closes the underlying writer.
+func (m *Muxer) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.close()
+}
+
+func (m *Muxer) close() error {
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	err := m.finalize()
+	if err2 := m.w.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// finalize writes cues and patches the header of a seekable file.
+func (m *Muxer) finalize() error {
+	if m.ws == nil {
+		return nil
+	}
+	cuesPos := m.pos
+	var cues []byte
+	for _, c := range m.clusters {
+		var cp []byte
+		cp = appendUint(cp, idCueTime, uint64(c.ts))
+		for _, track := range c.tracks {
+			var pos []byte
+			pos = appendUint(pos, idCueTrack, track)
+			pos = appendUint(pos, idCueClusterPosition, uint64(c.pos-m.segStart))
+			cp = appendElement(cp, idCueTrackPositions, pos)
+		}
+		cues = appendElement(cues, idCuePoint, cp)
+	}
+	if len(cues) != 0 {
+		if err := m.write(appendElement(nil, idCues, cues)); err != nil {
+			return err
+		}
+	}
+	end := m.pos
+
+	var b []byte
+	for i, c := range m.clusters {
+		next := cuesPos
+		if i+1 < len(m.clusters) {
+			next = m.clusters[i+1].pos
+		}
+		dataPos := c.pos + 4 + sizeLen8
+		if err := m.writeAt(c.pos+4, appendSize8(b[:0], next-dataPos)); err != nil {
+			return err
+		}
+	}
+	if err := m.writeAt(m.segStart-sizeLen8, appendSize8(b[:0], end-m.segStart)); err != nil {
+		return err
+	}
+	entries := []seekEntry{
+		{idInfo, m.infoPos - m.segStart},
+		{idTracks, m.tracksPos - m.segStart},
+	}
+	if len(cues) != 0 {
+		entries = append(entries, seekEntry{idCues, cuesPos - m.segStart})
+	}
+	sh := seekHead(entries...)
+	if n := m.seekHeadLen - len(sh); n != 0 {
+		sh = appendVoid(sh, n)
+	}
+	if err := m.writeAt(m.seekHeadPos, sh); err != nil {
+		return err
+	}
+	dur := float64(m.duration()) / float64(timecodeScale)
+	if err := m.writeAt(m.durationPos, appendFloat(b[:0], idDuration, dur)); err != nil {
+		return err
+	}
+	_, err := m.ws.Seek(end, io.SeekStart)
+	return err
+}
@@ -0,0 +1,603 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webm
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/at-wat/ebml-go"
+)
+
+const (
+	CodecOpus  = "A_OPUS"
+	CodecPCM16 = "A_PCM/INT/LIT"
+
+	// defTimecodeScale is the default duration of a timecode tick, in nanoseconds.
+	defTimecodeScale = 1000000
+	// maxElementSize is the max size of an element read into memory, except for blocks.
+	maxElementSize = 16 << 20
+	// maxBlockSize is the max size of a block.
+	maxBlockSize = 64 << 20
+)
+
+var (
+	ErrNotWebM       = errors.New("webm: not a WebM or Matroska file")
+	ErrNoTracks      = errors.New("webm: no tracks")
+	ErrNoTrack       = errors.New("webm: track not found")
+	ErrNotSeekable   = errors.New("webm: reader is not seekable")
+	ErrUnknownFormat = errors.New("webm: unsupported track format")
+)
+
+// TrackType is the type of track, as defined in Matroska.
+type TrackType int
+
+const (
+	TrackVideo TrackType = 1
+	TrackAudio TrackType = 2
+)
+
+// Track describes a track of the file.
+type Track struct {
+	Number          uint64
+	Name            string
+	Type            TrackType
+	CodecID         string
+	CodecPrivate    []byte
+	CodecDelay      time.Duration
+	DefaultDuration time.Duration
+	SampleRate      int
+	Channels        int
+	// BitDepth is the number of bits per sample for PCM, or zero if it is not set.
+	BitDepth int
+	// Start is the timestamp of the first block of the track, for tracks that start later than others.
+	// It is only used by the Muxer.
+	Start time.Duration
+}
+
+// Block is a single frame of a track. Laced blocks are split into separate frames.
+type Block struct {
+	Track     uint64
+	Timestamp time.Duration
+	// Duration is the duration of the frame, or zero if it is not known.
+	Duration time.Duration
+	Keyframe bool
+	Data     []byte
+}
+
+type cuePoint struct {
+	ts    time.Duration
+	track uint64
+	pos   int64 // absolute offset of the cluster
+}
+
+// NewReader creates a streaming demuxer of a WebM or Matroska file. Headers and track descriptions are read immediately.
+//
+// Seeking is only supported if r implements io.Seeker.
+func NewReader(r io.Reader) (*Reader, error) {
+	d := &Reader{
+		r:       &posReader{r: bufio.NewReader(r)},
+		scale:   defTimecodeScale,
+		cuesPos: -1,
+	}
+	d.rs, _ = r.(io.ReadSeeker)
+	if d.rs != nil {
+		// streams like os.Stdin implement the interface, but cannot seek
+		pos, err := d.rs.Seek(0, io.SeekCurrent)
+		if err != nil {
+			d.rs = nil
+		} else {
+			d.r.pos = pos
+		}
+	}
+	if err := d.readHeaders(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Reader demuxes blocks of a WebM or Matroska file. See NewReader.
+type Reader struct {
+	r  *posReader
+	rs io.ReadSeeker
+
+	segStart     int64 // offset of the segment data
+	segEnd       int64 // offset of the segment end, or sizeUnknown
+	firstCluster int64
+	scale        time.Duration // duration of a timecode tick
+	dur          time.Duration
+	tracks       []Track
+
+	cuesPos    int64 // offset of cues from the seek head, or -1
+	cues       []cuePoint
+	cuesLoaded bool
+
+	clusterTS int64 // timecode of the current cluster
+	pending   []Block
+	skip      time.Duration // blocks before this timestamp are dropped after seeking
+	err       error
+}
+
+// posReader tracks the offset in the file.
+type posReader struct {
+	r   *bufio.Reader
+	pos int64 // offset of the next byte
+}
+
+func (r *posReader) ReadByte() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err == nil {
+		r.pos++
+	}
+	return b, err
+}
+
+func (r *posReader) readFull(buf []byte) error {
+	n, err := io.ReadFull(r.r, buf)
+	r.pos += int64(n)
+	return noEOF(err)
+}
+
+func (r *posReader) discard(n int64) error {
+	for n > 0 {
+		m, err := r.r.Discard(int(min(n, 1<<20)))
+		r.pos += int64(m)
+		n -= int64(m)
+		if err != nil {
+			return noEOF(err)
+		}
+	}
+	return nil
+}
+
+func (d *Reader) readData(size int64, limit int64) ([]byte, error) {
+	if size == sizeUnknown || size > limit {
+		return nil, errInvalidSize
+	}
+	buf := make([]byte, size)
+	if err := d.r.readFull(buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (d *Reader) skipElement(size int64) error {
+	if size == sizeUnknown {
+		return errInvalidSize
+	}
+	return d.r.discard(size)
+}
+
+func (d *Reader) seekTo(pos int64) error {
+	if _, err := d.rs.Seek(pos, io.SeekStart); err != nil {
+		return err
+	}
+	d.r.r.Reset(d.rs)
+	d.r.pos = pos
+	return nil
+}
+
+func (d *Reader) readHeaders() error {
+	id, size, _, err := readHeader(d.r)
+	if err != nil || id != idEBML {
+		return ErrNotWebM
+	}
+	data, err := d.readData(size, maxElementSize)
+	if err != nil {
+		return err
+	}
+	var docType string
+	err = children(data, func(e element) error {
+		if e.id == idDocType {
+			docType = e.string()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if docType != "webm" && docType != "matroska" {
+		return ErrNotWebM
+	}
+	for {
+		id, size, _, err = readHeader(d.r)
+		if err != nil {
+			return noEOF(err)
+		}
+		if id == idSegment {
+			break
+		}
+		if err = d.skipElement(size); err != nil {
+			return err
+		}
+	}
+	d.segStart = d.r.pos
+	d.segEnd = sizeUnknown
+	if size != sizeUnknown {
+		d.segEnd = d.r.pos + size
+	}
+	for {
+		start := d.r.pos
+		id, size, _, err = readHeader(d.r)
+		if err == io.EOF {
+			d.firstCluster = start // no clusters
+			break
+		} else if err != nil {
+			return err
+		}
+		if id == idCluster {
+			d.firstCluster = start
+			break
+		}
+		switch id {
+		case idSeekHead, idInfo, idTracks, idCues:
+			data, err := d.readData(size, maxElementSize)
+			if err != nil {
+				return err
+			}
+			if err = d.parse(element{id: id, data: data}); err != nil {
+				return err
+			}
+		default:
+			if err = d.skipElement(size); err != nil {
+				return err
+			}
+		}
+	}
+	if len(d.tracks) == 0 {
+		return ErrNoTracks
+	}
+	return nil
+}
+
+func (d *Reader) parse(e element) error {
+	switch e.id {
+	case idSeekHead:
+		return children(e.data, func(e element) error {
+			if e.id != idSeek {
+				return nil
+			}
+			var (
+				id  []byte
+				pos int64 = -1
+			)
+			err := children(e.data, func(e element) error {
+				switch e.id {
+				case idSeekID:
+					id = e.data
+				case idSeekPosition:
+					pos = int64(e.uint())
+				}
+				return nil
+			})
+			if err == nil && (element{data: id}).uint() == idCues && pos >= 0 {
+				d.cuesPos = d.segStart + pos
+			}
+			return err
+		})
+	case idInfo:
+		var dur float64
+		err := children(e.data, func(e element) error {
+			switch e.id {
+			case idTimecodeScale:
+				if v := e.uint(); v != 0 {
+					d.scale = time.Duration(v)
+				}
+			case idDuration:
+				dur = e.float()
+			}
+			return nil
+		})
+		d.dur = time.Duration(dur * float64(d.scale))
+		return err
+	case idTracks:
+		return children(e.data, func(e element) error {
+			if e.id != idTrackEntry {
+				return nil
+			}
+			t, err := parseTrack(e)
+			if err != nil {
+				return err
+			}
+			d.tracks = append(d.tracks, t)
+			return nil
+		})
+	case idCues:
+		d.cuesLoaded = true
+		d.cues = d.cues[:0]
+		err := children(e.data, func(e element) error {
+			if e.id != idCuePoint {
+				return nil
+			}
+			var ts time.Duration
+			return children(e.data, func(e element) error {
+				switch e.id {
+				case idCueTime:
+					ts = time.Duration(e.uint()) * d.scale
+				case idCueTrackPositions:
+					cp := cuePoint{ts: ts, pos: -1}
+					err := children(e.data, func(e element) error {
+						switch e.id {
+						case idCueTrack:
+							cp.track = e.uint()
+						case idCueClusterPosition:
+							cp.pos = d.segStart + int64(e.uint())
+						}
+						return nil
+					})
+					if err == nil && cp.pos >= 0 {
+						d.cues = append(d.cues, cp)
+					}
+					return err
+				}
+				return nil
+			})
+		})
+		slices.SortStableFunc(d.cues, func(a, b cuePoint) int {
+			return int(a.ts - b.ts)
+		})
+		return err
+	}
+	return nil
+}
+
+func parseTrack(e element) (Track, error) {
+	var t Track
+	err := children(e.data, func(e element) error {
+		switch e.id {
+		case idTrackNumber:
+			t.Number = e.uint()
+		case idName:
+			t.Name = e.string()
+		case idTrackType:
+			t.Type = TrackType(e.uint())
+		case idCodecID:
+			t.CodecID = e.string()
+		case idCodecPrivate:
+			t.CodecPrivate = e.data
+		case idCodecDelay:
+			t.CodecDelay = time.Duration(e.uint())
+		case idDefaultDuration:
+			t.DefaultDuration = time.Duration(e.uint())
+		case idAudio:
+			return children(e.data, func(e element) error {
+				switch e.id {
+				case idSamplingFrequency:
+					t.SampleRate = int(e.float())
+				case idChannels:
+					t.Channels = int(e.uint())
+				case idBitDepth:
+					t.BitDepth = int(e.uint())
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return t, err
+	}
+	if t.Number == 0 {
+		return t, fmt.Errorf("webm: invalid track number")
+	}
+	if t.Type == TrackAudio && t.Channels == 0 {
+		t.Channels = 1
+	}
+	return t, nil
+}
+
+// Tracks returns all tracks of the file.
+func (d *Reader) Tracks() []Track {
+	return d.tracks
+}
+
+// Track returns a track with a given number.
+func (d *Reader) Track(num uint64) (Track, bool) {
+	for _, t := range d.tracks {
+		if t.Number == num {
+			return t, true
+		}
+	}
+	return Track{}, false
+}
+
+// Duration returns the duration of the file from the header, or zero if it is not known.
+func (d *Reader) Duration() time.Duration {
+	return d.dur
+}
+
+// ReadBlock returns the next frame of any track. It returns io.EOF at the end of the file.
+func (d *Reader) ReadBlock() (Block, error) {
+	if d.err != nil {
+		return Block{}, d.err
+	}
+	for {
+		for len(d.pending) != 0 {
+			b := d.pending[0]
+			d.pending = d.pending[1:]
+			if b.Timestamp < d.skip {
+				continue
+			}
+			d.skip = 0
+			return b, nil
+		}
+		if err := d.next(); err != nil {
+			if err != io.EOF {
+				d.err = err
+			}
+			return Block{}, err
+		}
+	}
+}
+
+// next reads elements until a block is found.
+func (d *Reader) next() error {
+	for len(d.pending) == 0 {
+		if d.segEnd != sizeUnknown && d.r.pos >= d.segEnd {
+			return io.EOF
+		}
+		id, size, _, err := readHeader(d.r)
+		if err != nil {
+			return err
+		}
+		switch id {
+		case idCluster:
+			// descend into the cluster, the timecode follows
+			d.clusterTS = 0
+		case idBlockGroup:
+			if err = d.readBlockGroup(size); err != nil {
+				return err
+			}
+		case idTimecode:
+			data, err := d.readData(size, 8)
+			if err != nil {
+				return err
+			}
+			d.clusterTS = int64(element{data: data}.uint())
+		case idSimpleBlock:
+			data, err := d.readData(size, maxBlockSize)
+			if err != nil {
+				return err
+			}
+			if err = d.addBlock(data, 0); err != nil {
+				return err
+			}
+		case idCues:
+			data, err := d.readData(size, maxElementSize)
+			if err != nil {
+				return err
+			}
+			if !d.cuesLoaded {
+				if err = d.parse(element{id: id, data: data}); err != nil {
+					return err
+				}
+			}
+		case idEBML, idSegment:
+			// chained segments are not supported
+			return io.EOF
+		default:
+			if err = d.skipElement(size); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *Reader) readBlockGroup(size int64) error {
+	data, err := d.readData(size, maxBlockSize)
+	if err != nil {
+		return err
+	}
+	var (
+		block []byte
+		dur   time.Duration
+	)
+	err = children(data, func(e element) error {
+		switch e.id {
+		case idBlock:
+			block = e.data
+		case idBlockDuration:
+			dur = time.Duration(e.uint()) * d.scale
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return nil
+	}
+	return d.addBlock(block, dur)
+}
+
+func (d *Reader) addBlock(data []byte, dur time.Duration) error {
+	b, err := ebml.UnmarshalBlock(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("webm: invalid block: %w", err)
+	}
+	t, ok := d.Track(b.TrackNumber)
+	if !ok {
+		return nil
+	}
+	ts := time.Duration(d.clusterTS+int64(b.Timecode)) * d.scale
+	frameDur := t.DefaultDuration
+	if dur != 0 {
+		frameDur = dur / time.Duration(len(b.Data))
+	}
+	for i, frame := range b.Data {
+		d.pending = append(d.pending, Block{
+			Track:     b.TrackNumber,
+			Timestamp: ts + time.Duration(i)*frameDur,
+			Duration:  frameDur,
+			Keyframe:  b.Keyframe,
+			Data:      frame,
+		})
+	}
+	return nil
+}
+
+// Seek positions the reader at the first block at or after a given timestamp.
+//
+// Cues are used to find the nearest cluster if the file has them. Otherwise, blocks are scanned from the start.
+func (d *Reader) Seek(ts time.Duration) error {
+	if d.rs == nil {
+		return ErrNotSeekable
+	}
+	if !d.cuesLoaded && d.cuesPos >= 0 {
+		if err := d.loadCues(); err != nil {
+			return err
+		}
+	}
+	pos := d.firstCluster
+	for _, c := range d.cues {
+		if c.ts > ts {
+			break
+		}
+		pos = c.pos
+	}
+	if err := d.seekTo(pos); err != nil {
+		return err
+	}
+	d.pending = d.pending[:0]
+	d.clusterTS = 0
+	d.skip = ts
+	d.err = nil
+	return nil
+}
+
+func (d *Reader) loadCues() error {
+	d.cuesLoaded = true // don't retry on errors
+	if err := d.seekTo(d.cuesPos); err != nil {
+		return err
+	}
+	id, size, _, err := readHeader(d.r)
+	if err != nil {
+		return noEOF(err)
+	}
+	if id != idCues {
+		return fmt.Errorf("webm: invalid cues position")
+	}
+	data, err := d.readData(size, maxElementSize)
+	if err != nil {
+		return err
+	}
+	return d.parse(element{id: id, data: data})
+}
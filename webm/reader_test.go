@@ -0,0 +1,291 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+)
+
+type nopCloser struct {
+	bytes.Buffer
+}
+
+func (*nopCloser) Close() error { return nil }
+
+type rawSample []byte
+
+func (s rawSample) Size() int {
+	return len(s)
+}
+
+func (s rawSample) CopyTo(dst []byte) (int, error) {
+	if len(dst) < len(s) {
+		return 0, io.ErrShortBuffer
+	}
+	return copy(dst, s), nil
+}
+
+func TestReadWriter(t *testing.T) {
+	var buf nopCloser
+	w := NewPCM16Writer(&buf, 8000, 1, 20*time.Millisecond)
+	var src media.PCM16Sample
+	for i := range 10 {
+		frame := make(media.PCM16Sample, 160)
+		for j := range frame {
+			frame[j] = int16(i*1000 - j)
+		}
+		src = append(src, frame...)
+		require.NoError(t, w.WriteSample(frame))
+	}
+	require.NoError(t, w.Close())
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, []Track{{
+		Number:          1,
+		Name:            "Audio",
+		Type:            TrackAudio,
+		CodecID:         CodecPCM16,
+		DefaultDuration: 20 * time.Millisecond,
+		SampleRate:      8000,
+		Channels:        1,
+		BitDepth:        16,
+	}}, r.Tracks())
+
+	pr, err := NewPCM16Reader(r, 1)
+	require.NoError(t, err)
+	require.Equal(t, 8000, pr.SampleRate())
+	var got media.PCM16Sample
+	out := make(media.PCM16Sample, 100) // smaller than blocks
+	for {
+		n, err := pr.ReadSample(out)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, out[:n]...)
+	}
+	require.Equal(t, src, got)
+	require.Equal(t, 200*time.Millisecond, pr.Timestamp())
+
+	_, err = NewTrackReader[rawSample](r, 2)
+	require.ErrorIs(t, err, ErrNoTrack)
+	_, err = NewReader(bytes.NewReader(buf.Bytes()[:20]))
+	require.Error(t, err)
+	_, err = NewReader(bytes.NewReader([]byte("not a webm file")))
+	require.ErrorIs(t, err, ErrNotWebM)
+}
+
+func TestFrames(t *testing.T) {
+	var buf nopCloser
+	w := NewWriter[rawSample](&buf, CodecOpus, 2, 48000, 20*time.Millisecond)
+	var src []rawSample
+	for i := range 5 {
+		s := rawSample(bytes.Repeat([]byte{byte(i)}, 10+i))
+		src = append(src, s)
+		require.NoError(t, w.WriteSample(s))
+	}
+	require.NoError(t, w.Close())
+
+	r, err := NewReader(struct{ io.Reader }{bytes.NewReader(buf.Bytes())})
+	require.NoError(t, err)
+	tr, err := NewTrackReader[rawSample](r, 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, tr.Track().Channels)
+	require.Equal(t, 48000, tr.SampleRate())
+	out := make(rawSample, 100)
+	for i, s := range src {
+		n, err := tr.ReadSample(out)
+		require.NoError(t, err)
+		require.Equal(t, s, out[:n])
+		require.Equal(t, time.Duration(i)*20*time.Millisecond, tr.Timestamp())
+	}
+	_, err = tr.ReadSample(out)
+	require.Equal(t, io.EOF, err)
+	require.ErrorIs(t, r.Seek(0), ErrNotSeekable)
+}
+
+// el encodes an EBML element with 8 byte size.
+func el(id uint32, data ...[]byte) []byte {
+	var out []byte
+	for i := 3; i >= 0; i-- {
+		if b := byte(id >> (8 * i)); b != 0 || len(out) != 0 {
+			out = append(out, b)
+		}
+	}
+	body := slices.Concat(data...)
+	// 8 byte size: length marker followed by 7 bytes of the value
+	out = append(out, 0x01)
+	out = append(out, u64(uint64(len(body)))[1:]...)
+	return append(out, body...)
+}
+
+func u64(v uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, v)
+}
+
+func block(track byte, ts int16, flags byte, data ...byte) []byte {
+	out := []byte{0x80 | track}
+	out = binary.BigEndian.AppendUint16(out, uint16(ts))
+	out = append(out, flags)
+	return append(out, data...)
+}
+
+type seekRecorder struct {
+	*bytes.Reader
+	seeks []int64
+}
+
+func (r *seekRecorder) Seek(off int64, whence int) (int64, error) {
+	pos, err := r.Reader.Seek(off, whence)
+	if whence == io.SeekStart {
+		r.seeks = append(r.seeks, pos)
+	}
+	return pos, err
+}
+
+func TestSeek(t *testing.T) {
+	header := el(idEBML, el(idDocType, []byte("webm")))
+	info := el(idInfo,
+		el(idTimecodeScale, u64(1000000)),
+		el(idDuration, u64(math.Float64bits(2040))),
+	)
+	tracks := el(idTracks,
+		el(idTrackEntry,
+			el(idTrackNumber, u64(1)),
+			el(idTrackType, u64(2)),
+			el(idCodecID, []byte(CodecOpus)),
+			el(idDefaultDuration, u64(uint64(20*time.Millisecond))),
+			el(idAudio, el(idSamplingFrequency, u64(math.Float64bits(48000))), el(idChannels, u64(2))),
+		),
+		el(idTrackEntry,
+			el(idTrackNumber, u64(2)),
+			el(idTrackType, u64(2)),
+			el(idCodecID, []byte(CodecPCM16)),
+			el(idAudio, el(idSamplingFrequency, u64(math.Float64bits(8000))), el(idBitDepth, u64(16))),
+		),
+	)
+	clusters := [][]byte{
+		el(idCluster,
+			el(idTimecode, u64(0)),
+			el(idSimpleBlock, block(1, 0, 0x80, 1)),
+			el(idBlockGroup, el(idBlock, block(1, 20, 0, 2)), el(idBlockDuration, u64(20))),
+		),
+		el(idCluster,
+			el(idTimecode, u64(1000)),
+			// Xiph lacing of two frames: [1, 2, 3], [4]
+			el(idSimpleBlock, block(1, 0, 0x80|0x02, 1, 3, 1, 2, 3, 4)),
+		),
+		el(idCluster,
+			el(idTimecode, u64(2000)),
+			el(idSimpleBlock, block(2, 0, 0x80, 0x34, 0x12)),
+			el(idSimpleBlock, block(1, 20, 0x80, 5)),
+		),
+	}
+	seekHead := func(cues uint64) []byte {
+		return el(idSeekHead, el(idSeek, el(idSeekID, []byte{0x1C, 0x53, 0xBB, 0x6B}), el(idSeekPosition, u64(cues))))
+	}
+	// offsets are relative to the segment data
+	var (
+		offsets []uint64
+		off     = uint64(len(seekHead(0)) + len(info) + len(tracks))
+	)
+	for _, c := range clusters {
+		offsets = append(offsets, off)
+		off += uint64(len(c))
+	}
+	var cues [][]byte
+	for i, pos := range offsets {
+		cues = append(cues, el(idCuePoint,
+			el(idCueTime, u64(uint64(i)*1000)),
+			el(idCueTrackPositions, el(idCueTrack, u64(1)), el(idCueClusterPosition, u64(pos))),
+		))
+	}
+	segment := slices.Concat(append([][]byte{seekHead(off), info, tracks}, append(clusters, el(idCues, cues...))...)...)
+	data := slices.Concat(header, el(idSegment, segment))
+	segStart := int64(len(data) - len(segment))
+
+	rs := &seekRecorder{Reader: bytes.NewReader(data)}
+	r, err := NewReader(rs)
+	require.NoError(t, err)
+	require.Len(t, r.Tracks(), 2)
+	require.Equal(t, 2040*time.Millisecond, r.Duration())
+	tr, ok := r.Track(2)
+	require.True(t, ok)
+	require.Equal(t, 16, tr.BitDepth)
+	require.Equal(t, 1, tr.Channels)
+
+	type frame struct {
+		track uint64
+		ts    int
+		data  []byte
+	}
+	readAll := func() []frame {
+		var out []frame
+		for {
+			b, err := r.ReadBlock()
+			if err == io.EOF {
+				return out
+			}
+			require.NoError(t, err)
+			out = append(out, frame{b.Track, int(b.Timestamp / time.Millisecond), b.Data})
+		}
+	}
+	all := []frame{
+		{1, 0, []byte{1}},
+		{1, 20, []byte{2}},
+		{1, 1000, []byte{1, 2, 3}},
+		{1, 1020, []byte{4}},
+		{2, 2000, []byte{0x34, 0x12}},
+		{1, 2020, []byte{5}},
+	}
+	require.NoError(t, r.Seek(1010*time.Millisecond))
+	// cues are loaded from the seek head position
+	require.Equal(t, []int64{segStart + int64(off), segStart + int64(offsets[1])}, rs.seeks)
+	require.Equal(t, all[3:], readAll())
+
+	require.NoError(t, r.Seek(0))
+	require.Equal(t, all, readAll())
+
+	require.NoError(t, r.Seek(0))
+	pr, err := NewPCM16Reader(r, 2)
+	require.NoError(t, err)
+	pcm := make(media.PCM16Sample, 10)
+	n, err := pr.ReadSample(pcm)
+	require.NoError(t, err)
+	require.Equal(t, media.PCM16Sample{0x1234}, pcm[:n])
+	require.Equal(t, 2000*time.Millisecond+time.Second/8000, pr.Timestamp())
+
+	_, err = NewPCM16Reader(r, 1)
+	require.ErrorIs(t, err, ErrUnknownFormat)
+
+	// truncated file
+	r, err = NewReader(bytes.NewReader(data[:len(data)-len(el(idCues, cues...))-3]))
+	require.NoError(t, err)
+	for err == nil {
+		_, err = r.ReadBlock()
+	}
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/livekit/media-sdk"
+)
+
+// NewTrackReader creates a reader of encoded frames of a single track, for example opus.Sample.
+// Blocks of other tracks are skipped, use Reader.ReadBlock to read multiple tracks at once.
+func NewTrackReader[T ~[]byte](r *Reader, track uint64) (*TrackReader[T], error) {
+	t, ok := r.Track(track)
+	if !ok {
+		return nil, ErrNoTrack
+	}
+	return &TrackReader[T]{r: r, track: t}, nil
+}
+
+// TrackReader reads frames of a single track. See NewTrackReader.
+type TrackReader[T ~[]byte] struct {
+	r     *Reader
+	track Track
+	ts    time.Duration
+}
+
+func (r *TrackReader[T]) String() string {
+	return fmt.Sprintf("WEBM(%s,%d,%d)", r.track.CodecID, r.track.Channels, r.track.SampleRate)
+}
+
+// Track returns the description of the track.
+func (r *TrackReader[T]) Track() Track {
+	return r.track
+}
+
+func (r *TrackReader[T]) SampleRate() int {
+	return r.track.SampleRate
+}
+
+// Timestamp returns the timestamp of the last frame read.
+func (r *TrackReader[T]) Timestamp() time.Duration {
+	return r.ts
+}
+
+// ReadSample reads the next frame of the track into buf. It returns io.ErrShortBuffer if the frame doesn't fit.
+func (r *TrackReader[T]) ReadSample(buf T) (int, error) {
+	for {
+		b, err := r.r.ReadBlock()
+		if err != nil {
+			return 0, err
+		}
+		if b.Track != r.track.Number {
+			continue
+		}
+		r.ts = b.Timestamp
+		if len(buf) < len(b.Data) {
+			return 0, io.ErrShortBuffer
+		}
+		return copy(buf, b.Data), nil
+	}
+}
+
+var _ media.Reader[media.PCM16Sample] = (*PCM16Reader)(nil)
+
+// NewPCM16Reader creates a reader of interleaved 16 bit PCM samples of a single track.
+func NewPCM16Reader(r *Reader, track uint64) (*PCM16Reader, error) {
+	t, ok := r.Track(track)
+	if !ok {
+		return nil, ErrNoTrack
+	}
+	if t.CodecID != CodecPCM16 || (t.BitDepth != 0 && t.BitDepth != 16) {
+		return nil, fmt.Errorf("%w: %s, %d bits", ErrUnknownFormat, t.CodecID, t.BitDepth)
+	}
+	return &PCM16Reader{r: r, track: t}, nil
+}
+
+// PCM16Reader reads PCM samples of a single track. See NewPCM16Reader.
+type PCM16Reader struct {
+	r     *Reader
+	track Track
+	ts    time.Duration
+	buf   []byte // remaining data of the last block
+}
+
+func (r *PCM16Reader) String() string {
+	return fmt.Sprintf("WEBM(%s,%d,%d)", r.track.CodecID, r.track.Channels, r.track.SampleRate)
+}
+
+// Track returns the description of the track.
+func (r *PCM16Reader) Track() Track {
+	return r.track
+}
+
+func (r *PCM16Reader) SampleRate() int {
+	return r.track.SampleRate
+}
+
+// Timestamp returns the timestamp of the next sample to be read.
+func (r *PCM16Reader) Timestamp() time.Duration {
+	return r.ts
+}
+
+// ReadSample reads samples of the current block into buf. Blocks larger than buf are returned in multiple calls.
+func (r *PCM16Reader) ReadSample(buf media.PCM16Sample) (int, error) {
+	for len(r.buf) < 2 {
+		b, err := r.r.ReadBlock()
+		if err != nil {
+			return 0, err
+		}
+		if b.Track == r.track.Number {
+			r.buf = b.Data
+			r.ts = b.Timestamp
+		}
+	}
+	n := min(len(buf), len(r.buf)/2)
+	for i := range n {
+		buf[i] = int16(binary.LittleEndian.Uint16(r.buf[2*i:]))
+	}
+	r.buf = r.buf[2*n:]
+	if rate := r.track.SampleRate; rate != 0 {
+		r.ts += time.Duration(n/max(r.track.Channels, 1)) * time.Second / time.Duration(rate)
+	}
+	return n, nil
+}
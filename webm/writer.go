@@ -17,59 +17,89 @@ package webm
 import (
 	"fmt"
 	"io"
-	"math/rand"
-	"slices"
 	"time"
 
-	"github.com/at-wat/ebml-go/webm"
-
 	"github.com/livekit/media-sdk"
 )
 
 func NewPCM16Writer(w io.WriteCloser, sampleRate int, channels int, sampleDur time.Duration) media.PCM16Writer {
-	return NewWriter[media.PCM16Sample](w, "A_PCM/INT/LIT", channels, sampleRate, sampleDur)
+	return newWriter[media.PCM16Sample](w, Track{
+		CodecID:         CodecPCM16,
+		Channels:        channels,
+		SampleRate:      sampleRate,
+		BitDepth:        16,
+		DefaultDuration: sampleDur,
+	})
 }
 
+// NewWriter creates a single track WebM writer. See NewMuxer for details.
+//
+// Timestamps are derived from the number of samples for PCM16Sample, or from the Duration method of the frame, if present.
+// Otherwise, each frame is assumed to last for sampleDur.
 func NewWriter[T media.Frame](w io.WriteCloser, codec string, channels, sampleRate int, sampleDur time.Duration) media.WriteCloser[T] {
-	ws, err := webm.NewSimpleBlockWriter(w, []webm.TrackEntry{
-		{
-			Name:            "Audio",
-			TrackNumber:     1,
-			TrackUID:        rand.Uint64(),
-			CodecID:         codec,
-			TrackType:       2,
-			DefaultDuration: uint64(sampleDur.Nanoseconds()),
-			Audio: &webm.Audio{
-				SamplingFrequency: float64(sampleRate),
-				Channels:          uint64(channels),
-			},
-		},
+	return newWriter[T](w, Track{
+		CodecID:         codec,
+		Channels:        channels,
+		SampleRate:      sampleRate,
+		DefaultDuration: sampleDur,
 	})
+}
+
+func newWriter[T media.Frame](w io.WriteCloser, t Track) media.WriteCloser[T] {
+	t.Number = 1
+	t.Name = "Audio"
+	m, err := NewMuxer(w, []Track{t})
 	if err != nil {
 		panic(err)
 	}
-	return &writer[T]{codec: codec, ws: ws[0], channels: channels, sampleRate: sampleRate, dur: sampleDur}
+	tw, err := NewTrackWriter[T](m, t.Number)
+	if err != nil {
+		panic(err)
+	}
+	return tw
 }
 
-type writer[T media.Frame] struct {
-	codec      string
-	ws         webm.BlockWriteCloser
-	channels   int
-	sampleRate int
-	dur        time.Duration
-	ts         int64
-	buf        []byte
+// NewTrackWriter creates a writer for a single track of the muxer.
+// The file is finalized when writers of all tracks are closed, or when the muxer is closed.
+func NewTrackWriter[T media.Frame](m *Muxer, track uint64) (*TrackWriter[T], error) {
+	for _, t := range m.tracks {
+		if t.Number == track {
+			return &TrackWriter[T]{m: m, t: t}, nil
+		}
+	}
+	return nil, ErrNoTrack
 }
 
-func (w *writer[T]) String() string {
-	return fmt.Sprintf("WEBM(%s,%d,%d)", w.codec, w.channels, w.sampleRate)
+// TrackWriter writes frames to a track of the muxer. See NewTrackWriter.
+type TrackWriter[T media.Frame] struct {
+	m   *Muxer
+	t   *muxTrack
+	buf []byte
 }
 
-func (w *writer[T]) SampleRate() int {
-	return w.sampleRate
+func (w *TrackWriter[T]) String() string {
+	return fmt.Sprintf("WEBM(%s,%d,%d)", w.t.CodecID, w.t.Channels, w.t.SampleRate)
 }
 
-func (w *writer[T]) WriteSample(sample T) error {
+func (w *TrackWriter[T]) SampleRate() int {
+	return w.t.SampleRate
+}
+
+// samples returns the number of samples per channel in the frame.
+func (w *TrackWriter[T]) samples(sample T) int64 {
+	rate := time.Duration(w.t.SampleRate)
+	switch s := any(sample).(type) {
+	case media.PCM16Sample:
+		return int64(len(s) / max(w.t.Channels, 1))
+	case interface{ Duration() time.Duration }:
+		if dur := s.Duration(); dur > 0 {
+			return int64(dur * rate / time.Second)
+		}
+	}
+	return int64(w.t.DefaultDuration * rate / time.Second)
+}
+
+func (w *TrackWriter[T]) WriteSample(sample T) error {
 	if sz := sample.Size(); cap(w.buf) < sz {
 		w.buf = make([]byte, sz)
 	} else {
@@ -79,11 +109,26 @@ func (w *writer[T]) WriteSample(sample T) error {
 	if err != nil {
 		return err
 	}
-	_, err = w.ws.Write(true, w.ts, slices.Clone(w.buf[:n]))
-	w.ts += w.dur.Milliseconds()
-	return err
+	w.m.mu.Lock()
+	defer w.m.mu.Unlock()
+	if w.m.closed || w.t.closed {
+		return errClosed
+	}
+	return w.m.writeBlock(w.t, w.buf[:n], w.samples(sample))
 }
 
-func (w *writer[T]) Close() error {
-	return w.ws.Close()
+// Close closes the track. The underlying writer is closed with the last track.
+func (w *TrackWriter[T]) Close() error {
+	w.m.mu.Lock()
+	defer w.m.mu.Unlock()
+	if w.t.closed {
+		return nil
+	}
+	w.t.closed = true
+	for _, t := range w.m.tracks {
+		if !t.closed {
+			return nil
+		}
+	}
+	return w.m.close()
 }
@@ -0,0 +1,219 @@
+// Copyright 2025 LiveKit, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webm
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/at-wat/ebml-go"
+	"github.com/at-wat/ebml-go/webm"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/media-sdk"
+)
+
+// durSample is a frame with variable duration, like opus.Sample.
+type durSample []byte
+
+func (s durSample) Size() int {
+	return len(s)
+}
+
+func (s durSample) CopyTo(dst []byte) (int, error) {
+	if len(dst) < len(s) {
+		return 0, io.ErrShortBuffer
+	}
+	return copy(dst, s), nil
+}
+
+func (s durSample) Duration() time.Duration {
+	return time.Duration(s[0]) * time.Millisecond
+}
+
+func writeTracks(t testing.TB, w io.WriteCloser) {
+	m, err := NewMuxer(w, []Track{
+		{Number: 1, Name: "A", CodecID: CodecOpus, SampleRate: 48000, Channels: 2},
+		{Number: 2, Name: "B", CodecID: CodecPCM16, SampleRate: 8000, Channels: 1, BitDepth: 16},
+	})
+	require.NoError(t, err)
+	w1, err := NewTrackWriter[durSample](m, 1)
+	require.NoError(t, err)
+	w2, err := NewTrackWriter[media.PCM16Sample](m, 2)
+	require.NoError(t, err)
+	_, err = NewTrackWriter[durSample](m, 3)
+	require.ErrorIs(t, err, ErrNoTrack)
+
+	// 7 seconds: 20 ms and 40 ms frames on the first track, 12.5 ms frames on the second
+	for i := range 350 {
+		dur := byte(20)
+		if i%2 == 1 {
+			dur = 40
+		}
+		if i < 233 {
+			require.NoError(t, w1.WriteSample(durSample{dur, byte(i)}))
+		}
+		require.NoError(t, w2.WriteSample(make(media.PCM16Sample, 100)))
+		require.NoError(t, w2.WriteSample(make(media.PCM16Sample, 100)))
+	}
+	require.NoError(t, w1.Close())
+	require.Error(t, w1.WriteSample(durSample{20}))
+	require.Equal(t, 8750*time.Millisecond, m.Duration())
+	require.NoError(t, w2.Close())
+}
+
+func TestMuxerSeekable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.webm")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	writeTracks(t, f)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// validate the structure with a different parser
+	var file struct {
+		Header  webm.EBMLHeader `ebml:"EBML"`
+		Segment webm.Segment    `ebml:"Segment"`
+	}
+	require.NoError(t, ebml.Unmarshal(bytes.NewReader(data), &file))
+	require.Equal(t, "webm", file.Header.DocType)
+	require.Equal(t, 8750.0, file.Segment.Info.Duration)
+	require.Len(t, file.Segment.SeekHead.Seek, 3)
+	require.Len(t, file.Segment.Tracks.TrackEntry, 2)
+	require.Len(t, file.Segment.Cluster, 2)
+	require.NotNil(t, file.Segment.Cues)
+	require.Len(t, file.Segment.Cues.CuePoint, 2)
+	require.EqualValues(t, 5000, file.Segment.Cues.CuePoint[1].CueTime)
+
+	rs := &seekRecorder{Reader: bytes.NewReader(data)}
+	r, err := NewReader(rs)
+	require.NoError(t, err)
+	require.Equal(t, 8750*time.Millisecond, r.Duration())
+	tr, ok := r.Track(1)
+	require.True(t, ok)
+	require.Equal(t, "A", tr.Name)
+	require.Equal(t, 2, tr.Channels)
+
+	require.NoError(t, r.Seek(6*time.Second))
+	require.Len(t, rs.seeks, 2) // cues and the second cluster
+	b, err := r.ReadBlock()
+	require.NoError(t, err)
+	require.Equal(t, 6*time.Second, b.Timestamp)
+
+	// timestamps follow sample counts without drift
+	require.NoError(t, r.Seek(0))
+	var last [3]time.Duration
+	counts := [3]int{}
+	for {
+		b, err := r.ReadBlock()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, b.Timestamp, last[b.Track])
+		last[b.Track] = b.Timestamp
+		counts[b.Track]++
+	}
+	require.Equal(t, [3]int{0, 233, 700}, counts)
+	require.Equal(t, 6960*time.Millisecond, last[1]) // 116 * (20 + 40) ms
+	require.Equal(t, 8737*time.Millisecond, last[2]) // 699 * 12.5 ms, in 1 ms ticks
+}
+
+func TestMuxerStream(t *testing.T) {
+	var buf nopCloser
+	writeTracks(t, &buf)
+
+	r, err := NewReader(struct{ io.Reader }{bytes.NewReader(buf.Bytes())})
+	require.NoError(t, err)
+	require.Len(t, r.Tracks(), 2)
+	require.Zero(t, r.Duration())
+	var n int
+	for {
+		_, err := r.ReadBlock()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		n++
+	}
+	require.Equal(t, 233+700, n)
+	require.False(t, r.cuesLoaded)
+	require.ErrorIs(t, r.Seek(0), ErrNotSeekable)
+}
+
+func TestMuxerLateTrack(t *testing.T) {
+	_, err := NewMuxer(&nopCloser{}, []Track{{CodecID: CodecOpus, SampleRate: 48000, Start: -time.Second}})
+	require.Error(t, err)
+
+	var buf nopCloser
+	m, err := NewMuxer(&buf, []Track{
+		{CodecID: CodecOpus, SampleRate: 48000},
+		{CodecID: CodecOpus, SampleRate: 48000, Start: 6 * time.Second},
+		{CodecID: CodecOpus, SampleRate: 48000},
+	})
+	require.NoError(t, err)
+	var ws [3]*TrackWriter[durSample]
+	for i := range ws {
+		ws[i], err = NewTrackWriter[durSample](m, uint64(i+1))
+		require.NoError(t, err)
+	}
+	for range 350 {
+		require.NoError(t, ws[0].WriteSample(durSample{20}))
+	}
+	// starts when the first track is already at 7 seconds
+	for range 50 {
+		require.NoError(t, ws[1].WriteSample(durSample{20}))
+	}
+	// lags behind the current cluster
+	require.NoError(t, ws[2].WriteSample(durSample{20}))
+	require.Equal(t, 7*time.Second, m.Duration())
+	for _, w := range ws {
+		require.NoError(t, w.Close())
+	}
+
+	var file struct {
+		Segment webm.Segment `ebml:"Segment"`
+	}
+	require.NoError(t, ebml.Unmarshal(bytes.NewReader(buf.Bytes()), &file))
+	require.Len(t, file.Segment.Cluster, 2)
+	require.EqualValues(t, 0, file.Segment.Cluster[0].Timecode)
+	require.EqualValues(t, 5000, file.Segment.Cluster[1].Timecode)
+
+	r, err := NewReader(struct{ io.Reader }{bytes.NewReader(buf.Bytes())})
+	require.NoError(t, err)
+	var first, last [4]time.Duration
+	counts := [4]int{}
+	for {
+		b, err := r.ReadBlock()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if counts[b.Track] == 0 {
+			first[b.Track] = b.Timestamp
+		}
+		last[b.Track] = b.Timestamp
+		counts[b.Track]++
+	}
+	require.Equal(t, [4]int{0, 350, 50, 1}, counts)
+	require.Equal(t, 6*time.Second, first[2])
+	require.Equal(t, 6980*time.Millisecond, last[2])
+	require.Equal(t, time.Duration(0), first[3])
+}